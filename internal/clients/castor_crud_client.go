@@ -119,6 +119,40 @@ func (c *CastorCRUDClient) AddPostulacionRevision(ctx context.Context, postulaci
 	return helpers.DoJSON("POST", endpoint, body, &created, c.cfg.RequestTimeout)
 }
 
+// ListPostulacionRevisiones returns the actions registered on a postulación.
+func (c *CastorCRUDClient) ListPostulacionRevisiones(ctx context.Context, postulacionID int64) ([]PostulacionRevision, error) {
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+	endpoint := rootservices.BuildURL(c.cfg.CastorCRUDBaseURL, "postulacion_revision")
+	values := url.Values{}
+	values.Set("limit", "0")
+	values.Set("query", fmt.Sprintf("PostulacionId:%d", postulacionID))
+	urlWithQuery := endpoint + "?" + values.Encode()
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", urlWithQuery, nil, &raw, c.cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return []PostulacionRevision{}, nil
+		}
+		return nil, err
+	}
+
+	result := make([]PostulacionRevision, 0, len(raw))
+	for _, entry := range raw {
+		if len(entry) == 0 {
+			continue
+		}
+		rev := mapPostulacionRevision(entry)
+		// El CRUD puede ignorar el filtro; se descartan revisiones ajenas.
+		if rev.PostulacionId != 0 && rev.PostulacionId != postulacionID {
+			continue
+		}
+		result = append(result, rev)
+	}
+	return result, nil
+}
+
 // ListPostulaciones retrieves postulation records applying CRUD filters.
 func (c *CastorCRUDClient) ListPostulaciones(ctx context.Context, filters map[string]string) ([]models.Postulacion, error) {
 	if err := ctxErr(ctx); err != nil {
@@ -476,7 +510,7 @@ func normalizePostulacionFilterKey(key string) string {
 }
 
 func mapPostulacion(raw postulacionRecord) models.Postulacion {
-	return models.Postulacion{
+	p := models.Postulacion{
 		Id:                raw.Id,
 		EstudianteId:      raw.EstudianteId,
 		OfertaId:          extractOfertaID(raw.OfertaPasantiaId),
@@ -484,6 +518,10 @@ func mapPostulacion(raw postulacionRecord) models.Postulacion {
 		FechaPostulacion:  strings.TrimSpace(raw.FechaPostulacion),
 		EnlaceDocHv:       strings.TrimSpace(raw.EnlaceDocHv),
 	}
+	if !raw.FechaEstado.IsZero() {
+		p.FechaEstado = raw.FechaEstado.Format(time.RFC3339)
+	}
+	return p
 }
 
 func extractOfertaID(raw json.RawMessage) int64 {
@@ -643,6 +681,37 @@ func mapPerfilRecord(raw map[string]interface{}) PerfilRecord {
 	return rec
 }
 
+// PostulacionRevision represents an action registered on a postulación.
+type PostulacionRevision struct {
+	Id            int64
+	PostulacionId int64
+	TutorId       int
+	Accion        string
+	Comentario    string
+	Fecha         time.Time
+}
+
+func mapPostulacionRevision(raw map[string]interface{}) PostulacionRevision {
+	rev := PostulacionRevision{}
+	if v, ok := normalizeToInt64(raw["Id"]); ok {
+		rev.Id = v
+	}
+	if v, ok := normalizeToInt64(raw["PostulacionId"]); ok {
+		rev.PostulacionId = v
+	} else if nested, ok := raw["PostulacionId"].(map[string]interface{}); ok {
+		if v, ok := normalizeToInt64(nested["Id"]); ok {
+			rev.PostulacionId = v
+		}
+	}
+	if v, ok := normalizeToInt(raw["TutorId"]); ok {
+		rev.TutorId = v
+	}
+	rev.Accion = strings.ToUpper(normalizeToString(raw["Accion"]))
+	rev.Comentario = normalizeToString(raw["Comentario"])
+	rev.Fecha = parseTimeValue(normalizeToString(raw["Fecha"]))
+	return rev
+}
+
 func mapPerfilVisita(raw map[string]interface{}) PerfilVisita {
 	visita := PerfilVisita{}
 
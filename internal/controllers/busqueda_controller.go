@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	rootcontrollers "github.com/udistrital/pasantia_mid/controllers"
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	internalservices "github.com/udistrital/pasantia_mid/internal/services"
+)
+
+// BusquedaController expone la búsqueda de texto completo sobre perfiles y ofertas.
+type BusquedaController struct {
+	rootcontrollers.BaseController
+}
+
+// GetEstudiantes busca perfiles visibles por texto libre con facetas.
+// @Summary Buscar estudiantes
+// @Description Busca en resumen, habilidades y proyecto curricular sin distinguir tildes ni plurales. Retorna facetas por proyecto curricular, facultad y modalidad preferida. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"items":[{"perfil_id":12,"resumen":"Desarrollo web","puntaje_busqueda":4.271}],"total":1,"page":1,"size":10,"facetas":{"proyecto_curricular":[{"id":57,"nombre":"Ingeniería de Sistemas","total":1}],"facultad":[],"modalidad":[]},"indice_actualizado":"2025-03-01T10:00:00-05:00"}}
+// @Tags Busqueda
+// @Accept json
+// @Produce json
+// @Param q query string false "Texto libre" Example("desarrollo web")
+// @Param pc_id query int false "Proyecto curricular" Example(57)
+// @Param facultad_id query int false "Facultad" Example(14)
+// @Param modalidad query string false "Modalidad preferida" Example("REMOTA")
+// @Param skills query string false "Habilidades requeridas separadas por coma" Example("golang,sql")
+// @Param page query int false "Página (>=1)" Example(1)
+// @Param size query int false "Tamaño de página (<=100)" Example(10)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 503 {object} internaldto.APIResponseDTO
+// @router /v1/buscar/estudiantes [get]
+func (c *BusquedaController) GetEstudiantes() {
+	filtros, ok := c.parseFiltros()
+	if !ok {
+		return
+	}
+	result, err := internalservices.BuscarEstudiantes(filtros)
+	if err != nil {
+		c.respondError(err, "error buscando estudiantes")
+		return
+	}
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetOfertas busca ofertas por texto libre con facetas.
+// @Summary Buscar ofertas
+// @Description Busca en título, descripción, habilidades y proyectos curriculares. Sin estado explícito solo retorna ofertas abiertas.
+// @Tags Busqueda
+// @Accept json
+// @Produce json
+// @Param q query string false "Texto libre" Example("analista de datos")
+// @Param pc_id query int false "Proyecto curricular" Example(57)
+// @Param facultad_id query int false "Facultad" Example(14)
+// @Param modalidad query string false "Modalidad" Example("HIBRIDA")
+// @Param skills query string false "Habilidades requeridas separadas por coma" Example("excel")
+// @Param estado query string false "Estados separados por coma" Example("OPC_CTR")
+// @Param page query int false "Página (>=1)" Example(1)
+// @Param size query int false "Tamaño de página (<=100)" Example(10)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 503 {object} internaldto.APIResponseDTO
+// @router /v1/buscar/ofertas [get]
+func (c *BusquedaController) GetOfertas() {
+	filtros, ok := c.parseFiltros()
+	if !ok {
+		return
+	}
+	filtros.Estados = strings.TrimSpace(c.GetString("estado"))
+	result, err := internalservices.BuscarOfertas(filtros)
+	if err != nil {
+		c.respondError(err, "error buscando ofertas")
+		return
+	}
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+func (c *BusquedaController) parseFiltros() (internalservices.FiltrosBusqueda, bool) {
+	page, size := internalhelpers.ParsePageSize(c.GetString("page"), c.GetString("size"))
+	filtros := internalservices.FiltrosBusqueda{
+		Query:       strings.TrimSpace(c.GetString("q")),
+		Modalidad:   strings.TrimSpace(c.GetString("modalidad")),
+		Habilidades: strings.TrimSpace(c.GetString("skills")),
+		Page:        page,
+		Size:        size,
+	}
+	var ok bool
+	if filtros.PCID, ok = c.optionalID("pc_id"); !ok {
+		return filtros, false
+	}
+	if filtros.FacultadID, ok = c.optionalID("facultad_id"); !ok {
+		return filtros, false
+	}
+	return filtros, true
+}
+
+func (c *BusquedaController) optionalID(key string) (int, bool) {
+	raw := strings.TrimSpace(c.GetString(key))
+	if raw == "" {
+		return 0, true
+	}
+	val, err := strconv.Atoi(raw)
+	if err != nil || val <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, key+" inválido", err), key+" inválido")
+		return 0, false
+	}
+	return val, true
+}
+
+func (c *BusquedaController) respondError(err error, fallback string) {
+	appErr := helpers.AsAppError(err, fallback)
+	resp := internalhelpers.Fail(appErr.Status, appErr.Message)
+	c.writeJSON(resp.Status, resp)
+}
+
+func (c *BusquedaController) writeJSON(status int, payload internaldto.APIResponseDTO) {
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = payload
+	_ = c.ServeJSON()
+}
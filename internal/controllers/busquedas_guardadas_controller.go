@@ -0,0 +1,260 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	rootcontrollers "github.com/udistrital/pasantia_mid/controllers"
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	internalservices "github.com/udistrital/pasantia_mid/internal/services"
+)
+
+// BusquedasGuardadasController gestiona las búsquedas guardadas con alertas: los tutores guardan
+// búsquedas del catálogo de estudiantes y los estudiantes búsquedas de ofertas.
+type BusquedasGuardadasController struct {
+	rootcontrollers.BaseController
+}
+
+// GetAll lista las búsquedas guardadas del tutor o estudiante.
+// @Summary Listar búsquedas guardadas
+// @Description Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":[{"id":3,"nombre":"Backend Go","tipo":"ESTUDIANTES","filtros":{"q":"backend","pc_id":57,"skills":"Go"},"frecuencia":"DIARIA","activa":true,"total_resultados":8,"pendientes_resumen":2}]}
+// @Tags BusquedasGuardadas
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Tutor dueño (búsquedas de estudiantes)" Example(321)
+// @Param estudiante_id query int false "Estudiante dueño (búsquedas de ofertas)" Example(654)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @router /v1/busquedas-guardadas [get]
+func (c *BusquedasGuardadasController) GetAll() {
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+	result, err := internalservices.ListarBusquedasGuardadas(tutorID, estudianteID)
+	if err != nil {
+		c.respondError(err, "error listando búsquedas guardadas")
+		return
+	}
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// Post guarda una búsqueda y activa sus alertas.
+// @Summary Guardar búsqueda
+// @Description Los resultados actuales no se notifican; solo las coincidencias nuevas. frecuencia: INSTANTANEA (por defecto) o DIARIA. Ejemplo de request: {"nombre":"Backend Go","filtros":{"q":"backend","pc_id":57,"skills":"golang"},"frecuencia":"DIARIA"}
+// @Tags BusquedasGuardadas
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Tutor dueño" Example(321)
+// @Param estudiante_id query int false "Estudiante dueño" Example(654)
+// @Param body body internaldto.BusquedaGuardadaUpsert true "Búsqueda"
+// @Success 201 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/busquedas-guardadas [post]
+func (c *BusquedasGuardadasController) Post() {
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+	var body internaldto.BusquedaGuardadaUpsert
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+	result, err := internalservices.CrearBusquedaGuardada(tutorID, estudianteID, body)
+	if err != nil {
+		c.respondError(err, "error guardando búsqueda")
+		return
+	}
+	resp := internalhelpers.Ok(result)
+	resp.Status = http.StatusCreated
+	resp.Message = "Búsqueda guardada"
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetOne retorna una búsqueda guardada.
+// @Summary Detalle de búsqueda guardada
+// @Tags BusquedasGuardadas
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la búsqueda" Example(3)
+// @Param tutor_id query int false "Tutor dueño" Example(321)
+// @Param estudiante_id query int false "Estudiante dueño" Example(654)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @router /v1/busquedas-guardadas/{id} [get]
+func (c *BusquedasGuardadasController) GetOne() {
+	id, ok := c.parseID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+	result, err := internalservices.GetBusquedaGuardada(id, tutorID, estudianteID)
+	if err != nil {
+		c.respondError(err, "error consultando búsqueda guardada")
+		return
+	}
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// Put actualiza nombre, filtros, frecuencia o estado de la búsqueda.
+// @Summary Actualizar búsqueda guardada
+// @Description Los campos omitidos conservan su valor. Si cambian los filtros o se reactiva, los resultados actuales pasan a ser la nueva base. Ejemplo de request: {"frecuencia":"INSTANTANEA","activa":false}
+// @Tags BusquedasGuardadas
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la búsqueda" Example(3)
+// @Param tutor_id query int false "Tutor dueño" Example(321)
+// @Param estudiante_id query int false "Estudiante dueño" Example(654)
+// @Param body body internaldto.BusquedaGuardadaUpsert true "Cambios"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @router /v1/busquedas-guardadas/{id} [put]
+func (c *BusquedasGuardadasController) Put() {
+	id, ok := c.parseID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+	var body internaldto.BusquedaGuardadaUpsert
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+	result, err := internalservices.ActualizarBusquedaGuardada(id, tutorID, estudianteID, body)
+	if err != nil {
+		c.respondError(err, "error actualizando búsqueda guardada")
+		return
+	}
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// Delete elimina una búsqueda guardada.
+// @Summary Eliminar búsqueda guardada
+// @Tags BusquedasGuardadas
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la búsqueda" Example(3)
+// @Param tutor_id query int false "Tutor dueño" Example(321)
+// @Param estudiante_id query int false "Estudiante dueño" Example(654)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @router /v1/busquedas-guardadas/{id} [delete]
+func (c *BusquedasGuardadasController) Delete() {
+	id, ok := c.parseID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+	if err := internalservices.EliminarBusquedaGuardada(id, tutorID, estudianteID); err != nil {
+		c.respondError(err, "error eliminando búsqueda guardada")
+		return
+	}
+	resp := internalhelpers.Ok(map[string]interface{}{"id": id})
+	resp.Message = "Búsqueda eliminada"
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetResultados ejecuta la búsqueda guardada y retorna las coincidencias actuales.
+// @Summary Resultados de búsqueda guardada
+// @Description Misma respuesta que /v1/buscar/estudiantes o /v1/buscar/ofertas según el tipo de la búsqueda.
+// @Tags BusquedasGuardadas
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la búsqueda" Example(3)
+// @Param tutor_id query int false "Tutor dueño" Example(321)
+// @Param estudiante_id query int false "Estudiante dueño" Example(654)
+// @Param page query int false "Página (>=1)" Example(1)
+// @Param size query int false "Tamaño de página (<=100)" Example(10)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 503 {object} internaldto.APIResponseDTO
+// @router /v1/busquedas-guardadas/{id}/resultados [get]
+func (c *BusquedasGuardadasController) GetResultados() {
+	id, ok := c.parseID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+	page, size := internalhelpers.ParsePageSize(c.GetString("page"), c.GetString("size"))
+	result, err := internalservices.ResultadosBusquedaGuardada(id, tutorID, estudianteID, page, size)
+	if err != nil {
+		c.respondError(err, "error ejecutando búsqueda guardada")
+		return
+	}
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// requireActor lee tutor_id o estudiante_id; al menos uno es obligatorio.
+func (c *BusquedasGuardadasController) requireActor() (int, int, bool) {
+	var tutorID, estudianteID int
+	if raw := strings.TrimSpace(c.GetString("tutor_id")); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			c.respondError(helpers.NewAppError(http.StatusBadRequest, "tutor_id inválido", err), "tutor_id inválido")
+			return 0, 0, false
+		}
+		tutorID = id
+	}
+	if raw := strings.TrimSpace(c.GetString("estudiante_id")); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			c.respondError(helpers.NewAppError(http.StatusBadRequest, "estudiante_id inválido", err), "estudiante_id inválido")
+			return 0, 0, false
+		}
+		estudianteID = id
+	}
+	if tutorID <= 0 && estudianteID <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "tutor_id o estudiante_id requerido", nil), "tutor_id o estudiante_id requerido")
+		return 0, 0, false
+	}
+	return tutorID, estudianteID, true
+}
+
+func (c *BusquedasGuardadasController) parseID() (int, bool) {
+	raw := strings.TrimSpace(c.Ctx.Input.Param(":id"))
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "id inválido", err), "id inválido")
+		return 0, false
+	}
+	return id, true
+}
+
+func (c *BusquedasGuardadasController) respondError(err error, fallback string) {
+	appErr := helpers.AsAppError(err, fallback)
+	resp := internalhelpers.Fail(appErr.Status, appErr.Message)
+	c.writeJSON(resp.Status, resp)
+}
+
+func (c *BusquedasGuardadasController) writeJSON(status int, payload internaldto.APIResponseDTO) {
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = payload
+	_ = c.ServeJSON()
+}
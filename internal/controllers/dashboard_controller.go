@@ -6,11 +6,13 @@ import (
 	"strings"
 
 	rootcontrollers "github.com/udistrital/pasantia_mid/controllers"
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
 	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
 	internalservices "github.com/udistrital/pasantia_mid/internal/services"
 )
 
-// DashboardController expone los dashboards por rol (estudiante / tutor).
+// DashboardController expone los dashboards por rol (estudiante / tutor / coordinación).
 type DashboardController struct{ rootcontrollers.BaseController }
 
 // GET /v1/estudiantes/dashboard?estudiante_id=...
@@ -45,8 +47,77 @@ func (c *DashboardController) GetTutor() {
 	c.writeJSON(resp.Status, resp)
 }
 
+// GetCoordinacion retorna el tablero agregado de coordinación.
+// @Summary Dashboard de coordinación
+// @Description Agregados por facultad o proyecto curricular (jerarquía de Oikos) y periodo académico (YYYY-1 enero-junio, YYYY-2 julio-diciembre): ofertas por estado, postulantes por oferta, tiempo a la selección, tasa de colocación, empresas con más actividad y estudiantes sin postulaciones. Requiere rol de coordinación o decanatura; los filtros se limitan a las facultades y proyectos curriculares asignados en el token (claims facultad_id/facultades y proyecto_curricular_id/proyectos_curriculares) y sin filtros se agrega toda la asignación. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"filtro":{"facultad_id":14,"proyectos_curriculares":[20,25],"periodo":"2026-2","desde":"2026-07-01","hasta":"2026-12-31"},"total_ofertas":12,"total_postulaciones":58,"ofertas_por_estado":[{"code":"OPC_CTR","estado":"Creada","total":7}],"postulantes_por_oferta":[{"oferta_id":21,"titulo":"Practicante de datos","estado":"OPC_CTR","empresa_id":321,"postulantes":14,"seleccionados":1}],"promedio_postulantes":4.8,"tiempo_seleccion":{"muestras":6,"promedio_dias":12.5,"mediana_dias":10},"colocacion":{"estudiantes":240,"postulados":96,"seleccionados":6,"tasa":2.5},"top_empresas":[{"empresa_id":321,"empresa":"Acme SAS","ofertas":3,"postulaciones":20,"seleccionados":2}],"estudiantes_sin_postulacion":{"total":144,"items":[{"tercero_id":4567,"perfil_id":12,"proyecto_curricular_id":20,"nombre":"Laura Gómez"}]}}}
+// @Tags Dashboard
+// @Accept json
+// @Produce json
+// @Param facultad_id query int false "Id de la facultad en Oikos" Example(14)
+// @Param proyecto_curricular_id query int false "Id del proyecto curricular" Example(20)
+// @Param periodo query string false "Periodo académico (YYYY-1 o YYYY-2)" Example(2026-2)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/coordinacion/dashboard [get]
+func (c *DashboardController) GetCoordinacion() {
+	if !c.requireCoordinacion() {
+		return
+	}
+	facultadID, ok := c.optionalID("facultad_id")
+	if !ok {
+		return
+	}
+	pcID, ok := c.optionalID("proyecto_curricular_id")
+	if !ok {
+		return
+	}
+
+	facultades, proyectos := internalhelpers.AsignacionAcademica(c.Ctx)
+	data, err := internalservices.GetDashboardCoordinacion(c.Ctx, internaldto.CoordinacionDashboardFiltro{
+		FacultadID:           facultadID,
+		ProyectoCurricularID: pcID,
+		Periodo:              strings.TrimSpace(c.GetString("periodo")),
+		FacultadesAsignadas:  facultades,
+		ProyectosAsignados:   proyectos,
+	})
+	if err != nil {
+		appErr := helpers.AsAppError(err, "error consultando dashboard de coordinación")
+		resp := internalhelpers.Fail(appErr.Status, appErr.Message)
+		c.writeJSON(resp.Status, resp)
+		return
+	}
+	resp := internalhelpers.Ok(data)
+	c.writeJSON(resp.Status, resp)
+}
+
 // --------------------- helpers locales ---------------------
 
+// requireCoordinacion admite coordinadores de pasantías y decanaturas.
+func (c *DashboardController) requireCoordinacion() bool {
+	if internalhelpers.EsCoordinacion(c.Ctx) || internalhelpers.EsDecanatura(c.Ctx) {
+		return true
+	}
+	resp := internalhelpers.Fail(http.StatusForbidden, "requiere rol de coordinación o decanatura")
+	c.writeJSON(resp.Status, resp)
+	return false
+}
+
+func (c *DashboardController) optionalID(name string) (int, bool) {
+	raw := strings.TrimSpace(c.GetString(name))
+	if raw == "" {
+		return 0, true
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		resp := internalhelpers.Fail(http.StatusBadRequest, name+" inválido")
+		c.writeJSON(resp.Status, resp)
+		return 0, false
+	}
+	return id, true
+}
+
 func (c *DashboardController) requireEstudiante() (int, bool) {
 	raw := strings.TrimSpace(c.GetString("estudiante_id")) // si ya usan JWT, aquí leer claim
 	if raw == "" {
@@ -0,0 +1,313 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	rootcontrollers "github.com/udistrital/pasantia_mid/controllers"
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	internalservices "github.com/udistrital/pasantia_mid/internal/services"
+)
+
+// DatosPersonalesController atiende los derechos de habeas data del estudiante: copia de sus datos
+// y solicitudes de eliminación, que coordinación confirma o rechaza.
+type DatosPersonalesController struct {
+	rootcontrollers.BaseController
+}
+
+// GetExport descarga un ZIP con los datos personales del estudiante.
+// @Summary Exportar mis datos
+// @Description ZIP con archivos JSON: perfil, postulaciones (con comentarios públicos), invitaciones, visitas recibidas, consentimientos y solicitudes de eliminación.
+// @Tags Datos personales
+// @Produce application/zip
+// @Param estudiante_id query int true "Id del estudiante (tercero)" Example(4567)
+// @Success 200 {file} file
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/estudiantes/mis-datos/export [get]
+func (c *DatosPersonalesController) GetExport() {
+	estudianteID, ok := c.requireEstudiante()
+	if !ok {
+		return
+	}
+
+	archivo, err := internalservices.ExportarMisDatos(c.Ctx, estudianteID)
+	if err != nil {
+		c.respondError(err, "error exportando datos personales")
+		return
+	}
+
+	c.Ctx.Output.Header("Content-Type", archivo.ContentType)
+	c.Ctx.Output.Header("Content-Disposition", `attachment; filename="`+archivo.Nombre+`"`)
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	_ = c.Ctx.Output.Body(archivo.Contenido)
+}
+
+// GetMisSolicitudes lista las solicitudes de eliminación del estudiante.
+// @Summary Solicitudes de eliminación de datos
+// @Description Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":[{"id":3,"tercero_id":4567,"perfil_id":12,"estado":"PENDIENTE","motivo":"Terminé mis estudios","fecha_solicitud":"2026-05-02T10:00:00Z"}]}
+// @Tags Datos personales
+// @Produce json
+// @Param estudiante_id query int true "Id del estudiante (tercero)" Example(4567)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/estudiantes/mis-datos/eliminacion [get]
+func (c *DatosPersonalesController) GetMisSolicitudes() {
+	estudianteID, ok := c.requireEstudiante()
+	if !ok {
+		return
+	}
+
+	result, err := internalservices.ListarSolicitudesEliminacionEstudiante(estudianteID)
+	if err != nil {
+		c.respondError(err, "error consultando solicitudes de eliminación")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PostSolicitud registra una solicitud de eliminación de los datos del estudiante.
+// @Summary Solicitar eliminación de datos
+// @Description La solicitud queda PENDIENTE hasta que coordinación la confirme. Al ejecutarse se anonimizan el perfil, las postulaciones y las invitaciones y se borran las visitas al perfil, conservando las estadísticas agregadas. Responde 409 si ya hay una en trámite. Ejemplo de request: {"motivo":"Terminé mis estudios"}
+// @Tags Datos personales
+// @Accept json
+// @Produce json
+// @Param estudiante_id query int true "Id del estudiante (tercero)" Example(4567)
+// @Param body body internaldto.SolicitudEliminacionReq false "Motivo"
+// @Success 201 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/estudiantes/mis-datos/eliminacion [post]
+func (c *DatosPersonalesController) PostSolicitud() {
+	estudianteID, ok := c.requireEstudiante()
+	if !ok {
+		return
+	}
+	var body internaldto.SolicitudEliminacionReq
+	if len(c.Ctx.Input.RequestBody) > 0 {
+		if err := c.ParseJSONBody(&body); err != nil {
+			c.respondError(err, "cuerpo inválido")
+			return
+		}
+	}
+
+	result, err := internalservices.SolicitarEliminacion(estudianteID, body.Motivo)
+	if err != nil {
+		c.respondError(err, "error registrando solicitud de eliminación")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	resp.Status = http.StatusCreated
+	resp.Message = "Solicitud registrada"
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetSolicitudes lista las solicitudes de eliminación para coordinación.
+// @Summary Solicitudes de eliminación de datos (coordinación)
+// @Description Requiere rol de coordinación. Estados: PENDIENTE, RECHAZADA, EJECUTADA, ERROR.
+// @Tags Datos personales
+// @Produce json
+// @Param estado query string false "Filtrar por estado" Example(PENDIENTE)
+// @Param page query int false "Página" Example(1)
+// @Param size query int false "Tamaño de página" Example(20)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/coordinacion/solicitudes-eliminacion [get]
+func (c *DatosPersonalesController) GetSolicitudes() {
+	if !c.requireCoordinacion() {
+		return
+	}
+	page, size := internalhelpers.ParsePageSize(c.GetString("page"), c.GetString("size"))
+
+	result, err := internalservices.ListarSolicitudesEliminacion(c.GetString("estado"), page, size)
+	if err != nil {
+		c.respondError(err, "error consultando solicitudes de eliminación")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetSolicitud retorna una solicitud con su bitácora.
+// @Summary Detalle de solicitud de eliminación (coordinación)
+// @Description Incluye la bitácora completa: solicitud, confirmación o rechazo, cada paso ejecutado y su resultado.
+// @Tags Datos personales
+// @Produce json
+// @Param id path int true "Id de la solicitud" Example(3)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/coordinacion/solicitudes-eliminacion/{id} [get]
+func (c *DatosPersonalesController) GetSolicitud() {
+	if !c.requireCoordinacion() {
+		return
+	}
+	id, ok := c.parseID()
+	if !ok {
+		return
+	}
+
+	result, err := internalservices.GetSolicitudEliminacion(id)
+	if err != nil {
+		c.respondError(err, "error consultando solicitud de eliminación")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PutConfirmar confirma y ejecuta la eliminación.
+// @Summary Confirmar solicitud de eliminación (coordinación)
+// @Description Anonimiza perfil, postulaciones, invitaciones y comentarios del estudiante y elimina sus búsquedas guardadas, las visitas y las notas de tutores sobre el perfil. Si un paso o su registro en la bitácora falla la solicitud queda en ERROR y puede confirmarse de nuevo. Ejemplo de request: {"observacion":"Verificada identidad del titular"}
+// @Tags Datos personales
+// @Accept json
+// @Produce json
+// @Param id path int true "Id de la solicitud" Example(3)
+// @Param body body internaldto.SolicitudEliminacionResolucion false "Observación"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/coordinacion/solicitudes-eliminacion/{id}/confirmar [put]
+func (c *DatosPersonalesController) PutConfirmar() {
+	if !c.requireCoordinacion() {
+		return
+	}
+	id, ok := c.parseID()
+	if !ok {
+		return
+	}
+	body, ok := c.parseResolucion()
+	if !ok {
+		return
+	}
+
+	result, err := internalservices.ConfirmarSolicitudEliminacion(c.Ctx.Request.Context(), id, c.coordinadorID(), body.Observacion)
+	if err != nil {
+		c.respondError(err, "error confirmando solicitud de eliminación")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PutRechazar rechaza la solicitud sin modificar los datos.
+// @Summary Rechazar solicitud de eliminación (coordinación)
+// @Description La observación es obligatoria. Ejemplo de request: {"observacion":"El estudiante tiene una pasantía en curso"}
+// @Tags Datos personales
+// @Accept json
+// @Produce json
+// @Param id path int true "Id de la solicitud" Example(3)
+// @Param body body internaldto.SolicitudEliminacionResolucion true "Observación"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/coordinacion/solicitudes-eliminacion/{id}/rechazar [put]
+func (c *DatosPersonalesController) PutRechazar() {
+	if !c.requireCoordinacion() {
+		return
+	}
+	id, ok := c.parseID()
+	if !ok {
+		return
+	}
+	body, ok := c.parseResolucion()
+	if !ok {
+		return
+	}
+
+	result, err := internalservices.RechazarSolicitudEliminacion(id, c.coordinadorID(), body.Observacion)
+	if err != nil {
+		c.respondError(err, "error rechazando solicitud de eliminación")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// --------------------------
+// Helpers locales
+// --------------------------
+
+func (c *DatosPersonalesController) parseResolucion() (internaldto.SolicitudEliminacionResolucion, bool) {
+	var body internaldto.SolicitudEliminacionResolucion
+	if len(c.Ctx.Input.RequestBody) == 0 {
+		return body, true
+	}
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(err, "cuerpo inválido")
+		return body, false
+	}
+	return body, true
+}
+
+// coordinadorID toma el tercero_id del token; si no viene la bitácora registra el actor en 0.
+func (c *DatosPersonalesController) coordinadorID() int {
+	id, err := internalhelpers.GetTerceroID(c.Ctx)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func (c *DatosPersonalesController) requireCoordinacion() bool {
+	if internalhelpers.EsCoordinacion(c.Ctx) {
+		return true
+	}
+	c.respondError(helpers.NewAppError(http.StatusForbidden, "requiere rol de coordinación", nil), "requiere rol de coordinación")
+	return false
+}
+
+func (c *DatosPersonalesController) requireEstudiante() (int, bool) {
+	raw := strings.TrimSpace(c.GetString("estudiante_id"))
+	if raw == "" {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "estudiante_id requerido", nil), "estudiante_id requerido")
+		return 0, false
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "estudiante_id inválido", err), "estudiante_id inválido")
+		return 0, false
+	}
+	return id, true
+}
+
+func (c *DatosPersonalesController) parseID() (int, bool) {
+	raw := strings.TrimSpace(c.Ctx.Input.Param(":id"))
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "id inválido", err), "id inválido")
+		return 0, false
+	}
+	return id, true
+}
+
+func (c *DatosPersonalesController) respondError(err error, fallback string) {
+	appErr := helpers.AsAppError(err, fallback)
+	resp := internalhelpers.Fail(appErr.Status, appErr.Message)
+	c.writeJSON(resp.Status, resp)
+}
+
+func (c *DatosPersonalesController) writeJSON(status int, payload internaldto.APIResponseDTO) {
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = payload
+	_ = c.ServeJSON()
+}
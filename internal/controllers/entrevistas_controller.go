@@ -0,0 +1,358 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	rootcontrollers "github.com/udistrital/pasantia_mid/controllers"
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	internalservices "github.com/udistrital/pasantia_mid/internal/services"
+)
+
+// EntrevistasController gestiona el agendamiento de entrevistas de postulaciones preseleccionadas.
+type EntrevistasController struct {
+	rootcontrollers.BaseController
+}
+
+// PostProponer propone franjas de entrevista para una postulación.
+// @Summary Proponer entrevista
+// @Description Solo para postulaciones preseleccionadas. Requiere lugar o enlace_video. Ejemplo de request: {"slots":[{"inicio":"2026-11-03T14:00:00Z","fin":"2026-11-03T14:30:00Z"}],"enlace_video":"https://meet.example.com/abc"}
+// @Tags Entrevistas
+// @Accept json
+// @Produce json
+// @Param tutor_id query int true "Id del tutor" Example(7890)
+// @Param id path int true "Id de la postulación" Example(101)
+// @Param body body internaldto.EntrevistaPropuesta true "Franjas propuestas"
+// @Success 201 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *EntrevistasController) PostProponer() {
+	postulacionID, ok := c.parsePostulacionID()
+	if !ok {
+		return
+	}
+	tutorID, ok := c.requireTutor()
+	if !ok {
+		return
+	}
+
+	var body internaldto.EntrevistaPropuesta
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+
+	result, err := internalservices.ProponerEntrevista(c.Ctx, tutorID, postulacionID, body)
+	if err != nil {
+		c.respondError(err, "error proponiendo entrevista")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	resp.Status = http.StatusCreated
+	resp.Message = "Entrevista propuesta"
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetByPostulacion lista las entrevistas de la postulación.
+// @Summary Entrevistas de la postulación
+// @Description Acepta tutor_id (dueño de la oferta) o estudiante_id (postulado).
+// @Tags Entrevistas
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param estudiante_id query int false "Id del estudiante" Example(4567)
+// @Param id path int true "Id de la postulación" Example(101)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *EntrevistasController) GetByPostulacion() {
+	postulacionID, ok := c.parsePostulacionID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+
+	result, err := internalservices.ListarEntrevistasPostulacion(c.Ctx, postulacionID, tutorID, estudianteID)
+	if err != nil {
+		c.respondError(err, "error consultando entrevistas")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetById retorna el detalle de la entrevista.
+// @Summary Detalle de entrevista
+// @Tags Entrevistas
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param estudiante_id query int false "Id del estudiante" Example(4567)
+// @Param id path int true "Id de la entrevista" Example(12)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *EntrevistasController) GetById() {
+	entrevistaID, ok := c.parseEntrevistaID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+
+	result, err := internalservices.GetEntrevista(c.Ctx, entrevistaID, tutorID, estudianteID)
+	if err != nil {
+		c.respondError(err, "error consultando entrevista")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PutConfirmar elige una de las franjas propuestas.
+// @Summary Confirmar entrevista
+// @Description La confirma la contraparte de quien propuso las franjas. Ejemplo de request: {"slot_index":0}
+// @Tags Entrevistas
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param estudiante_id query int false "Id del estudiante" Example(4567)
+// @Param id path int true "Id de la entrevista" Example(12)
+// @Param body body internaldto.EntrevistaConfirmacion true "Franja elegida"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *EntrevistasController) PutConfirmar() {
+	entrevistaID, ok := c.parseEntrevistaID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+
+	var body internaldto.EntrevistaConfirmacion
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+
+	result, err := internalservices.ConfirmarEntrevista(c.Ctx, entrevistaID, tutorID, estudianteID, body)
+	if err != nil {
+		c.respondError(err, "error confirmando entrevista")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	resp.Message = "Entrevista confirmada"
+	c.writeJSON(resp.Status, resp)
+}
+
+// PutReprogramar propone nuevas franjas para la entrevista.
+// @Summary Reprogramar entrevista
+// @Description Cualquiera de las partes puede reprogramar; la otra debe confirmar una nueva franja. Si no se envía lugar ni enlace_video se conservan los actuales.
+// @Tags Entrevistas
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param estudiante_id query int false "Id del estudiante" Example(4567)
+// @Param id path int true "Id de la entrevista" Example(12)
+// @Param body body internaldto.EntrevistaPropuesta true "Nuevas franjas"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *EntrevistasController) PutReprogramar() {
+	entrevistaID, ok := c.parseEntrevistaID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+
+	var body internaldto.EntrevistaPropuesta
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+
+	result, err := internalservices.ReprogramarEntrevista(c.Ctx, entrevistaID, tutorID, estudianteID, body)
+	if err != nil {
+		c.respondError(err, "error reprogramando entrevista")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	resp.Message = "Entrevista reprogramada"
+	c.writeJSON(resp.Status, resp)
+}
+
+// PutCancelar cancela la entrevista.
+// @Summary Cancelar entrevista
+// @Description Ejemplo de request: {"motivo":"Cambio de agenda"}
+// @Tags Entrevistas
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param estudiante_id query int false "Id del estudiante" Example(4567)
+// @Param id path int true "Id de la entrevista" Example(12)
+// @Param body body internaldto.EntrevistaCancelacion false "Motivo"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *EntrevistasController) PutCancelar() {
+	entrevistaID, ok := c.parseEntrevistaID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+
+	var body internaldto.EntrevistaCancelacion
+	if len(c.Ctx.Input.RequestBody) > 0 {
+		if err := c.ParseJSONBody(&body); err != nil {
+			c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+			return
+		}
+	}
+
+	result, err := internalservices.CancelarEntrevista(c.Ctx, entrevistaID, tutorID, estudianteID, body)
+	if err != nil {
+		c.respondError(err, "error cancelando entrevista")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	resp.Message = "Entrevista cancelada"
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetICS descarga la entrevista en formato iCalendar.
+// @Summary Exportar entrevista (.ics)
+// @Description Disponible para tutor y estudiante una vez confirmada la franja.
+// @Tags Entrevistas
+// @Produce text/calendar
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param estudiante_id query int false "Id del estudiante" Example(4567)
+// @Param id path int true "Id de la entrevista" Example(12)
+// @Success 200 {file} file
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *EntrevistasController) GetICS() {
+	entrevistaID, ok := c.parseEntrevistaID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+
+	content, filename, err := internalservices.ExportarEntrevistaICS(c.Ctx, entrevistaID, tutorID, estudianteID)
+	if err != nil {
+		c.respondError(err, "error exportando entrevista")
+		return
+	}
+
+	c.Ctx.Output.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Ctx.Output.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	_ = c.Ctx.Output.Body(content)
+}
+
+// requireActor lee tutor_id o estudiante_id; al menos uno es obligatorio.
+func (c *EntrevistasController) requireActor() (int, int, bool) {
+	var tutorID, estudianteID int
+	if raw := strings.TrimSpace(c.GetString("tutor_id")); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			c.respondError(helpers.NewAppError(http.StatusBadRequest, "tutor_id inválido", err), "tutor_id inválido")
+			return 0, 0, false
+		}
+		tutorID = id
+	}
+	if raw := strings.TrimSpace(c.GetString("estudiante_id")); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			c.respondError(helpers.NewAppError(http.StatusBadRequest, "estudiante_id inválido", err), "estudiante_id inválido")
+			return 0, 0, false
+		}
+		estudianteID = id
+	}
+	if tutorID <= 0 && estudianteID <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "tutor_id o estudiante_id requerido", nil), "tutor_id o estudiante_id requerido")
+		return 0, 0, false
+	}
+	return tutorID, estudianteID, true
+}
+
+func (c *EntrevistasController) requireTutor() (int, bool) {
+	raw := strings.TrimSpace(c.GetString("tutor_id"))
+	if raw == "" {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "tutor_id requerido", nil), "tutor_id requerido")
+		return 0, false
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "tutor_id inválido", err), "tutor_id inválido")
+		return 0, false
+	}
+	return id, true
+}
+
+func (c *EntrevistasController) parsePostulacionID() (int64, bool) {
+	raw := strings.TrimSpace(c.Ctx.Input.Param(":id"))
+	val, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || val <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "id inválido", err), "id inválido")
+		return 0, false
+	}
+	return val, true
+}
+
+func (c *EntrevistasController) parseEntrevistaID() (int, bool) {
+	raw := strings.TrimSpace(c.Ctx.Input.Param(":id"))
+	val, err := strconv.Atoi(raw)
+	if err != nil || val <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "id inválido", err), "id inválido")
+		return 0, false
+	}
+	return val, true
+}
+
+func (c *EntrevistasController) respondError(err error, fallback string) {
+	appErr := helpers.AsAppError(err, fallback)
+	resp := internalhelpers.Fail(appErr.Status, appErr.Message)
+	c.writeJSON(resp.Status, resp)
+}
+
+func (c *EntrevistasController) writeJSON(status int, payload internaldto.APIResponseDTO) {
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = payload
+	_ = c.ServeJSON()
+}
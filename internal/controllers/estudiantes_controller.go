@@ -112,13 +112,13 @@ func (c *EstudiantesController) PutActualizarPerfil() {
 	c.writeJSON(resp.Status, resp)
 }
 
-// PutVisibilidad actualiza el estado de visibilidad del perfil.
+// PutVisibilidad actualiza el estado de visibilidad del perfil y el modo anónimo.
 // @Summary Gestión de perfil de Estudiante (sin elegibilidad)
-// @Description Actualiza únicamente la visibilidad. Ejemplo de request: {"visible":true}
+// @Description Actualiza la visibilidad y/o el modo anónimo. En modo anónimo los tutores ven proyecto curricular, habilidades y resumen, pero no la identidad ni el CV, hasta que el estudiante acepte una invitación suya o se postule a una de sus ofertas. Ejemplo de request: {"visible":true,"anonimo":true}
 // @Tags Estudiantes
 // @Accept json
 // @Produce json
-// @Param body body internaldto.EstudiantePerfilVisibilidadReq true "Payload de visibilidad" Example({"tercero_id":4567,"visible":true})
+// @Param body body internaldto.EstudiantePerfilVisibilidadReq true "Payload de visibilidad" Example({"tercero_id":4567,"visible":true,"anonimo":true})
 // @Success 200 {object} internaldto.APIResponseDTO
 // @Failure 400 {object} internaldto.APIResponseDTO
 // @Failure 404 {object} internaldto.APIResponseDTO
@@ -134,13 +134,14 @@ func (c *EstudiantesController) PutVisibilidad() {
 	if !ok {
 		return
 	}
-	if body.Visible == nil {
-		c.respondError(helpers.NewAppError(http.StatusBadRequest, "visible es requerido", nil), "visible es requerido")
+	if body.Visible == nil && body.Anonimo == nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "visible o anonimo es requerido", nil), "visible o anonimo es requerido")
 		return
 	}
 
 	payload := internaldto.EstudiantePerfilUpsert{
 		Visible: body.Visible,
+		Anonimo: body.Anonimo,
 	}
 	perfil, err := internalservices.ActualizarPerfil(c.Ctx, terceroID, payload)
 	if err != nil {
@@ -192,6 +193,131 @@ func (c *EstudiantesController) PutCV() {
 	c.writeJSON(resp.Status, resp)
 }
 
+// GetConsentimiento consulta el consentimiento de tratamiento de datos frente a los términos vigentes.
+// @Summary Consentimiento de tratamiento de datos
+// @Description Devuelve la versión vigente de los términos, la aceptada por el estudiante y si debe volver a aceptarlos (requiere_aceptacion). Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"tercero_id":4567,"perfil_id":12,"version_vigente":"2","version_aceptada":"1","aceptado":true,"vigente":false,"requiere_aceptacion":true}}
+// @Tags Estudiantes
+// @Accept json
+// @Produce json
+// @Param tercero_id query int true "Id del tercero (estudiante)" Example(4567)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/estudiantes/consentimiento [get]
+func (c *EstudiantesController) GetConsentimiento() {
+	terceroID, ok := c.parseTerceroID()
+	if !ok {
+		return
+	}
+
+	estado, err := internalservices.EstadoConsentimiento(terceroID)
+	if err != nil {
+		c.respondError(err, "error consultando consentimiento")
+		return
+	}
+
+	resp := internalhelpers.Ok(estado)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PostConsentimiento registra la aceptación de los términos vigentes de tratamiento de datos.
+// @Summary Consentimiento de tratamiento de datos
+// @Description Acepta la versión vigente de los términos (Ley 1581 de 2012). Se guarda la versión, la fecha, la IP y el user agent. Responde 409 si la versión no es la vigente; sin versión vigente configurada se omite version. Ejemplo de request: {"tercero_id":4567,"version":"2"}
+// @Tags Estudiantes
+// @Accept json
+// @Produce json
+// @Param body body internaldto.ConsentimientoReq true "Versión aceptada" Example({"tercero_id":4567,"version":"2"})
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/estudiantes/consentimiento [post]
+func (c *EstudiantesController) PostConsentimiento() {
+	var body internaldto.ConsentimientoReq
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(err, "cuerpo inválido")
+		return
+	}
+	terceroID, ok := c.parseBodyTerceroID(body.TerceroID)
+	if !ok {
+		return
+	}
+
+	estado, err := internalservices.AceptarConsentimiento(c.Ctx, terceroID, body.Version)
+	if err != nil {
+		c.respondError(err, "error registrando consentimiento")
+		return
+	}
+
+	resp := internalhelpers.Ok(estado)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PostRevocarConsentimiento revoca el consentimiento de tratamiento de datos.
+// @Summary Consentimiento de tratamiento de datos
+// @Description Revoca el consentimiento y oculta el perfil del explorador. Para volver a aparecer el estudiante debe aceptar de nuevo los términos y activar la visibilidad. Ejemplo de request: {"tercero_id":4567,"motivo":"Ya no busco práctica"}
+// @Tags Estudiantes
+// @Accept json
+// @Produce json
+// @Param body body internaldto.ConsentimientoRevocarReq true "Revocación" Example({"tercero_id":4567,"motivo":"Ya no busco práctica"})
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/estudiantes/consentimiento/revocar [post]
+func (c *EstudiantesController) PostRevocarConsentimiento() {
+	var body internaldto.ConsentimientoRevocarReq
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(err, "cuerpo inválido")
+		return
+	}
+	terceroID, ok := c.parseBodyTerceroID(body.TerceroID)
+	if !ok {
+		return
+	}
+
+	estado, err := internalservices.RevocarConsentimiento(c.Ctx, terceroID, body.Motivo)
+	if err != nil {
+		c.respondError(err, "error revocando consentimiento")
+		return
+	}
+
+	resp := internalhelpers.Ok(estado)
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetHistorialConsentimiento lista la auditoría de consentimientos del estudiante.
+// @Summary Consentimiento de tratamiento de datos
+// @Description Devuelve las aceptaciones y revocaciones registradas, de la más reciente a la más antigua. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"items":[{"id":7,"tercero_id":4567,"perfil_id":12,"accion":"ACEPTACION","version":"2","ip":"10.0.0.8","user_agent":"Mozilla/5.0","fecha":"2026-03-01T14:00:00Z"}],"page":1,"size":20,"total":1}}
+// @Tags Estudiantes
+// @Accept json
+// @Produce json
+// @Param tercero_id query int true "Id del tercero (estudiante)" Example(4567)
+// @Param page query int false "Página" Example(1)
+// @Param size query int false "Tamaño de página" Example(20)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/estudiantes/consentimiento/historial [get]
+func (c *EstudiantesController) GetHistorialConsentimiento() {
+	terceroID, ok := c.parseTerceroID()
+	if !ok {
+		return
+	}
+	page, size := internalhelpers.ParsePageSize(c.GetString("page"), c.GetString("size"))
+
+	result, err := internalservices.HistorialConsentimiento(terceroID, page, size)
+	if err != nil {
+		c.respondError(err, "error consultando historial de consentimiento")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
 // PostPerfilPorDocumento identifica si existe un perfil en Castor a partir del documento.
 // @Summary Consulta perfil de estudiante por documento
 // @Description Recibe el número de cédula y retorna si el estudiante ya está registrado en Castor.
@@ -270,20 +396,61 @@ func (c *EstudiantesController) writeJSON(status int, payload internaldto.APIRes
 	_ = c.ServeJSON()
 }
 
-// --------------------------
-// GET /v1/estudiantes/perfil/visitas
-// Devuelve quién vio mi perfil (agregado por tutor)
-// --------------------------
+// GetVisitas devuelve la analítica de visitas al perfil del estudiante.
+// @Summary Visitas a mi perfil
+// @Description Visitas deduplicadas por tutor dentro de la ventana configurada (ventana_min) entre desde y hasta (YYYY-MM-DD, por defecto los últimos 30 días). Incluye serie por día o semana, desglose por empresa y conversión de visitas a invitaciones. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"desde":"2026-09-19","hasta":"2026-10-18","granularidad":"semana","ventana_min":30,"total":5,"items":[{"tutor_id":7890,"nombre":"Ana Pérez","empresa_id":321,"empresa":"Acme SAS","total":3,"ultima_visita":"2026-10-17T15:04:05Z","invito":true}],"serie":[{"periodo":"2026-10-12","visitas":3,"tutores":2}],"por_empresa":[{"empresa_id":321,"empresa":"Acme SAS","visitas":3,"tutores":1}],"conversion":{"tutores_visitantes":2,"tutores_invitaron":1,"invitaciones":1,"tasa":50}}}
+// @Tags Estudiantes
+// @Accept json
+// @Produce json
+// @Param estudiante_id query int true "Id del estudiante (tercero)" Example(4567)
+// @Param desde query string false "Fecha inicial (YYYY-MM-DD)" Example(2026-09-19)
+// @Param hasta query string false "Fecha final inclusive (YYYY-MM-DD)" Example(2026-10-18)
+// @Param granularidad query string false "dia | semana" Example(semana)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/estudiantes/perfil/visitas [get]
 func (c *EstudiantesController) GetVisitas() {
 	estudianteID, ok := c.requireEstudiante()
 	if !ok {
 		return
 	}
 
-	result, err := internalservices.ListarVisitasPorEstudiante(c.Ctx, estudianteID)
+	result, err := internalservices.AnaliticaVisitasPerfil(c.Ctx, estudianteID,
+		c.GetString("desde"), c.GetString("hasta"), c.GetString("granularidad"))
 	if err != nil {
-		resp := internalhelpers.Fail(http.StatusInternalServerError, err.Error())
-		c.writeJSON(resp.Status, resp)
+		c.respondError(err, "error consultando visitas")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetOfertasRecomendadas lista las ofertas abiertas ordenadas por afinidad con el perfil.
+// @Summary Ofertas recomendadas para el estudiante
+// @Description Combina proyecto curricular, habilidades, modalidad y ciudad preferidas y recencia. Cada ítem trae puntaje_match (0-100) y razones. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"items":[{"id":21,"titulo":"Practicante de datos","puntaje_match":86.5,"razones":["Dirigida a tu proyecto curricular","Tienes 2 de 3 habilidades requeridas: SQL, Python"]}],"page":1,"size":20,"total":1}}
+// @Tags Estudiantes
+// @Accept json
+// @Produce json
+// @Param estudiante_id query int true "Id del estudiante (tercero)" Example(4567)
+// @Param page query int false "Página" Example(1)
+// @Param size query int false "Tamaño de página" Example(20)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *EstudiantesController) GetOfertasRecomendadas() {
+	estudianteID, ok := c.requireEstudiante()
+	if !ok {
+		return
+	}
+	page, size := internalhelpers.ParsePageSize(c.GetString("page"), c.GetString("size"))
+
+	result, err := internalservices.RecomendarOfertas(c.Ctx.Request.Context(), estudianteID, page, size)
+	if err != nil {
+		c.respondError(err, "error consultando ofertas recomendadas")
 		return
 	}
 
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	rootcontrollers "github.com/udistrital/pasantia_mid/controllers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	internalservices "github.com/udistrital/pasantia_mid/internal/services"
+)
+
+// EventosController expone el stream SSE con la actividad del usuario autenticado.
+type EventosController struct {
+	rootcontrollers.BaseController
+}
+
+// GetStream abre el stream de eventos del usuario del token.
+// @Summary Stream de eventos (SSE)
+// @Description Server-Sent Events con la actividad hecha a través del MID que concierne al usuario del JWT: al estudiante (claim tercero_id) le llegan invitacion.creada, postulacion.actualizada y perfil.visitado; al tutor (claim tutor_id), postulacion.creada, postulacion.actualizada e invitacion.respondida. El token va en Authorization o, para EventSource del navegador, en access_token. Cada evento lleva id; al reconectar con Last-Event-ID se reenvían los eventos pendientes y, si ya no están en memoria, llega un evento resync para recargar la bandeja. Cada eventos_heartbeat_seg se envía un comentario de keep-alive. Ejemplo: id: 1760790000000042 / event: invitacion.creada / data: {"tipo":"invitacion.creada","perfil_id":12,"tutor_id":77,"oferta_id":21,"invitacion_id":5,"estado":"PENDIENTE","fecha":"2026-10-18T15:04:05Z"}
+// @Tags Eventos
+// @Produce text/event-stream
+// @Param Authorization header string false "Bearer <JWT>"
+// @Param access_token query string false "JWT, alternativa al header para EventSource"
+// @Param Last-Event-ID header string false "Último id recibido, para reanudar"
+// @Success 200 {string} string "text/event-stream"
+// @Failure 401 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/eventos/stream [get]
+func (c *EventosController) GetStream() {
+	terceroID, tutorID, ok := c.requireUsuario()
+	if !ok {
+		return
+	}
+	flusher, ok := c.Ctx.ResponseWriter.ResponseWriter.(http.Flusher)
+	if !ok {
+		resp := internalhelpers.Fail(http.StatusInternalServerError, "el servidor no soporta streaming")
+		c.writeJSON(resp.Status, resp)
+		return
+	}
+
+	ctx := c.Ctx.Request.Context()
+	ultimoID := strings.TrimSpace(c.Ctx.Input.Header("Last-Event-ID"))
+	if ultimoID == "" {
+		ultimoID = strings.TrimSpace(c.GetString("lastEventId"))
+	}
+	stream := internalservices.AbrirStreamEventos(ctx, terceroID, tutorID, ultimoID)
+	defer stream.Cerrar()
+
+	w := c.Ctx.ResponseWriter
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if stream.Resync {
+		fmt.Fprint(w, "event: resync\ndata: {}\n\n")
+	}
+	for _, e := range stream.Pendientes {
+		if !escribirEventoSSE(w, e) {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(internalservices.HeartbeatEventos())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stream.Cerrado:
+			return
+		case e := <-stream.Eventos:
+			if !escribirEventoSSE(w, e) {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func escribirEventoSSE(w http.ResponseWriter, e internalservices.EventoStream) bool {
+	data, err := json.Marshal(e.Evento)
+	if err != nil {
+		return true
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Tipo, data)
+	return err == nil
+}
+
+// --------------------- helpers locales ---------------------
+
+// requireUsuario toma tercero_id y tutor_id del JWT; basta con uno de los dos.
+func (c *EventosController) requireUsuario() (int, int, bool) {
+	if strings.TrimSpace(c.Ctx.Input.Header("Authorization")) == "" {
+		if token := strings.TrimSpace(c.GetString("access_token")); token != "" {
+			c.Ctx.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	if _, err := internalhelpers.Claims(c.Ctx); err != nil {
+		resp := internalhelpers.Fail(http.StatusUnauthorized, "token inválido o ausente")
+		c.writeJSON(resp.Status, resp)
+		return 0, 0, false
+	}
+	terceroID, _ := internalhelpers.GetTerceroID(c.Ctx)
+	tutorID, _ := internalhelpers.GetTutorID(c.Ctx)
+	if terceroID <= 0 && tutorID <= 0 {
+		resp := internalhelpers.Fail(http.StatusUnauthorized, "el token no identifica estudiante ni tutor")
+		c.writeJSON(resp.Status, resp)
+		return 0, 0, false
+	}
+	return terceroID, tutorID, true
+}
+
+func (c *EventosController) writeJSON(status int, payload internaldto.APIResponseDTO) {
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = payload
+	_ = c.ServeJSON()
+}
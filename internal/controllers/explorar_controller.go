@@ -69,7 +69,7 @@ func (c *ExplorarController) GetCatalogo() {
 
 // GetPerfil devuelve el detalle de un perfil.
 // @Summary Detalle de perfil de estudiante
-// @Description Retorna información del perfil incluyendo si está guardado por el tutor.
+// @Description Retorna información del perfil incluyendo si está guardado por el tutor. Responde 404 si el estudiante no tiene consentimiento vigente de tratamiento de datos.
 // @Tags Explorar
 // @Accept json
 // @Produce json
@@ -160,9 +160,216 @@ func (c *ExplorarController) DeleteGuardar() {
 	c.writeJSON(resp.Status, resp)
 }
 
+// PutGuardar organiza un perfil guardado: carpeta y nota privada del tutor.
+// @Summary Organizar perfil guardado
+// @Description Asigna carpeta (carpeta_id = 0 lo deja sin carpeta) o nota privada. Si el perfil no estaba guardado lo guarda. Ejemplo de request: {"carpeta_id":4,"nota":"Buen perfil para backend, contactar en marzo"}
+// @Tags Explorar
+// @Accept json
+// @Produce json
+// @Param tutor_id query int true "Id del tutor" Example(7890)
+// @Param perfil_id path int true "Id del perfil" Example(12)
+// @Param body body internaldto.GuardadoUpdate true "Carpeta y nota"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/explorar/estudiantes/{perfil_id}/guardar [put]
+func (c *ExplorarController) PutGuardar() {
+	perfilID, ok := c.parsePerfilID()
+	if !ok {
+		return
+	}
+	tutorID, ok := c.requireTutor()
+	if !ok {
+		return
+	}
+	var body internaldto.GuardadoUpdate
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+
+	result, err := internalservices.ActualizarGuardado(tutorID, perfilID, body)
+	if err != nil {
+		c.respondError(err, "error actualizando perfil guardado")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetGuardados lista los perfiles guardados por el tutor.
+// @Summary Perfiles guardados
+// @Description Lista paginada de perfiles guardados con carpeta y nota privada, del más reciente al más antiguo. Los perfiles que el estudiante ocultó solo conservan perfil_id. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"items":[{"perfil_id":12,"resumen":"Ingeniero de sistemas","guardado":true,"carpeta_id":4,"carpeta_nombre":"Backend","nota":"Contactar en marzo","fecha_guardado":"2025-02-10T15:04:05Z"}],"page":1,"size":10,"total":1}}
+// @Tags Explorar
+// @Accept json
+// @Produce json
+// @Param tutor_id query int true "Id del tutor" Example(7890)
+// @Param carpeta_id query int false "Filtrar por carpeta (0 = sin carpeta)" Example(4)
+// @Param page query int false "Página (>=1)" Example(1)
+// @Param size query int false "Tamaño de página (<=100)" Example(10)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/explorar/guardados [get]
+func (c *ExplorarController) GetGuardados() {
+	tutorID, ok := c.requireTutor()
+	if !ok {
+		return
+	}
+	var carpetaID *int
+	if raw := strings.TrimSpace(c.GetString("carpeta_id")); raw != "" {
+		val, err := strconv.Atoi(raw)
+		if err != nil || val < 0 {
+			c.respondError(helpers.NewAppError(http.StatusBadRequest, "carpeta_id inválido", err), "carpeta_id inválido")
+			return
+		}
+		carpetaID = &val
+	}
+	page, size := internalhelpers.ParsePageSize(c.GetString("page"), c.GetString("size"))
+
+	result, err := internalservices.ListarGuardados(c.Ctx.Request.Context(), tutorID, carpetaID, page, size)
+	if err != nil {
+		c.respondError(err, "error consultando perfiles guardados")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetCarpetas lista las carpetas de guardados del tutor.
+// @Summary Carpetas de guardados
+// @Description Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":[{"id":4,"nombre":"Backend","total":3}]}
+// @Tags Explorar
+// @Accept json
+// @Produce json
+// @Param tutor_id query int true "Id del tutor" Example(7890)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/explorar/carpetas [get]
+func (c *ExplorarController) GetCarpetas() {
+	tutorID, ok := c.requireTutor()
+	if !ok {
+		return
+	}
+	result, err := internalservices.ListarCarpetas(tutorID)
+	if err != nil {
+		c.respondError(err, "error consultando carpetas")
+		return
+	}
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PostCarpeta crea una carpeta de guardados.
+// @Summary Crear carpeta de guardados
+// @Description Ejemplo de request: {"nombre":"Backend"}
+// @Tags Explorar
+// @Accept json
+// @Produce json
+// @Param tutor_id query int true "Id del tutor" Example(7890)
+// @Param body body internaldto.CarpetaUpsert true "Carpeta"
+// @Success 201 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/explorar/carpetas [post]
+func (c *ExplorarController) PostCarpeta() {
+	tutorID, ok := c.requireTutor()
+	if !ok {
+		return
+	}
+	var body internaldto.CarpetaUpsert
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+	result, err := internalservices.CrearCarpeta(tutorID, body)
+	if err != nil {
+		c.respondError(err, "error creando carpeta")
+		return
+	}
+	resp := internalhelpers.Ok(result)
+	resp.Status = http.StatusCreated
+	resp.Message = "Carpeta creada"
+	c.writeJSON(resp.Status, resp)
+}
+
+// PutCarpeta renombra una carpeta de guardados.
+// @Summary Renombrar carpeta de guardados
+// @Description Ejemplo de request: {"nombre":"Backend 2025"}
+// @Tags Explorar
+// @Accept json
+// @Produce json
+// @Param tutor_id query int true "Id del tutor" Example(7890)
+// @Param id path int true "Id de la carpeta" Example(4)
+// @Param body body internaldto.CarpetaUpsert true "Carpeta"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @router /v1/explorar/carpetas/{id} [put]
+func (c *ExplorarController) PutCarpeta() {
+	carpetaID, ok := c.parseCarpetaID()
+	if !ok {
+		return
+	}
+	tutorID, ok := c.requireTutor()
+	if !ok {
+		return
+	}
+	var body internaldto.CarpetaUpsert
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+	result, err := internalservices.RenombrarCarpeta(tutorID, carpetaID, body)
+	if err != nil {
+		c.respondError(err, "error renombrando carpeta")
+		return
+	}
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// DeleteCarpeta elimina una carpeta; los perfiles que contenía siguen guardados sin carpeta.
+// @Summary Eliminar carpeta de guardados
+// @Tags Explorar
+// @Accept json
+// @Produce json
+// @Param tutor_id query int true "Id del tutor" Example(7890)
+// @Param id path int true "Id de la carpeta" Example(4)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @router /v1/explorar/carpetas/{id} [delete]
+func (c *ExplorarController) DeleteCarpeta() {
+	carpetaID, ok := c.parseCarpetaID()
+	if !ok {
+		return
+	}
+	tutorID, ok := c.requireTutor()
+	if !ok {
+		return
+	}
+	if err := internalservices.EliminarCarpeta(tutorID, carpetaID); err != nil {
+		c.respondError(err, "error eliminando carpeta")
+		return
+	}
+	resp := internalhelpers.Ok(map[string]string{"message": "Carpeta eliminada"})
+	c.writeJSON(resp.Status, resp)
+}
+
 // PostVisita registra la visita de un tutor a un perfil.
 // @Summary Registrar visita de perfil
-// @Description Registra el evento de visita para métricas. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"message":"Visita registrada"}}
+// @Description Registra el evento de visita para métricas. Las visitas repetidas del mismo tutor dentro de la ventana de deduplicación no se registran de nuevo. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"message":"Visita registrada"}}
 // @Tags Explorar
 // @Accept json
 // @Produce json
@@ -182,12 +389,54 @@ func (c *ExplorarController) PostVisita() {
 		return
 	}
 
-	if err := internalservices.RegistrarVisita(c.Ctx, tutorID, perfilID); err != nil {
+	registrada, err := internalservices.RegistrarVisita(c.Ctx, tutorID, perfilID)
+	if err != nil {
 		c.respondError(err, "error registrando visita")
 		return
 	}
 
-	resp := internalhelpers.Ok(map[string]string{"message": "Visita registrada"})
+	message := "Visita registrada"
+	if !registrada {
+		message = "Visita ya registrada recientemente"
+	}
+	resp := internalhelpers.Ok(map[string]string{"message": message})
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetCandidatosSugeridos sugiere perfiles de estudiantes para una oferta del tutor.
+// @Summary Candidatos sugeridos para una oferta
+// @Description Ordena los perfiles visibles y con tratamiento de datos aceptado según su afinidad con los proyectos curriculares y habilidades de la oferta. Excluye estudiantes ya postulados o invitados. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"items":[{"perfil_id":12,"resumen":"Ingeniero de sistemas","puntaje_match":82.5,"razones":["Pertenece a un proyecto curricular de la oferta"]}],"page":1,"size":10,"total":7}}
+// @Tags Explorar
+// @Accept json
+// @Produce json
+// @Param tutor_id query int true "Id del tutor dueño de la oferta" Example(7890)
+// @Param id path int true "Id de la oferta" Example(45)
+// @Param page query int false "Página (>=1)" Example(1)
+// @Param size query int false "Tamaño de página (<=100)" Example(10)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/ofertas/{id}/candidatos-sugeridos [get]
+func (c *ExplorarController) GetCandidatosSugeridos() {
+	ofertaID, ok := c.parseOfertaID()
+	if !ok {
+		return
+	}
+	tutorID, ok := c.requireTutor()
+	if !ok {
+		return
+	}
+	page, size := internalhelpers.ParsePageSize(c.GetString("page"), c.GetString("size"))
+
+	result, err := internalservices.CandidatosSugeridos(c.Ctx.Request.Context(), tutorID, ofertaID, page, size)
+	if err != nil {
+		c.respondError(err, "error consultando candidatos sugeridos")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
 	c.writeJSON(resp.Status, resp)
 }
 
@@ -201,6 +450,26 @@ func (c *ExplorarController) parsePerfilID() (int, bool) {
 	return val, true
 }
 
+func (c *ExplorarController) parseCarpetaID() (int, bool) {
+	raw := strings.TrimSpace(c.Ctx.Input.Param(":id"))
+	val, err := strconv.Atoi(raw)
+	if err != nil || val <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "id de carpeta inválido", err), "id de carpeta inválido")
+		return 0, false
+	}
+	return val, true
+}
+
+func (c *ExplorarController) parseOfertaID() (int, bool) {
+	raw := strings.TrimSpace(c.Ctx.Input.Param(":id"))
+	val, err := strconv.Atoi(raw)
+	if err != nil || val <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "id de oferta inválido", err), "id de oferta inválido")
+		return 0, false
+	}
+	return val, true
+}
+
 func (c *ExplorarController) requireTutor() (int, bool) {
 	raw := strings.TrimSpace(c.GetString("tutor_id"))
 	if raw == "" {
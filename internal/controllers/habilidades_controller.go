@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	rootcontrollers "github.com/udistrital/pasantia_mid/controllers"
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	internalservices "github.com/udistrital/pasantia_mid/internal/services"
+)
+
+// HabilidadesController expone el catálogo de habilidades con nombres canónicos y sinónimos.
+type HabilidadesController struct {
+	rootcontrollers.BaseController
+}
+
+// GetAll lista el catálogo de habilidades y los niveles de dominio admitidos.
+// @Summary Catálogo de habilidades
+// @Description La búsqueda por q coincide con el nombre canónico o cualquier sinónimo, sin distinguir tildes. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"items":[{"nombre":"Go","categoria":"Lenguajes de programación","sinonimos":["golang"]}],"total":1,"niveles":["BASICO","INTERMEDIO","AVANZADO","EXPERTO"]}}
+// @Tags Habilidades
+// @Accept json
+// @Produce json
+// @Param q query string false "Texto a buscar en nombre o sinónimos" Example("golang")
+// @Param categoria query string false "Filtrar por categoría" Example("Datos")
+// @Success 200 {object} internaldto.APIResponseDTO
+// @router /v1/catalogos/habilidades [get]
+func (c *HabilidadesController) GetAll() {
+	result := internalservices.ListarHabilidades(c.GetString("q"), c.GetString("categoria"))
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// Post agrega una habilidad al catálogo. Requiere rol de coordinación.
+// @Summary Crear habilidad
+// @Description Ejemplo de request: {"nombre":"Kubernetes","categoria":"Herramientas","sinonimos":["k8s"]}
+// @Tags Habilidades
+// @Accept json
+// @Produce json
+// @Param body body internaldto.HabilidadUpsert true "Habilidad"
+// @Success 201 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/catalogos/habilidades [post]
+func (c *HabilidadesController) Post() {
+	if !c.requireCoordinacion() {
+		return
+	}
+	var body internaldto.HabilidadUpsert
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+
+	result, err := internalservices.CrearHabilidad(body)
+	if err != nil {
+		c.respondError(err, "error creando habilidad")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	resp.Status = http.StatusCreated
+	resp.Message = "Habilidad creada"
+	c.writeJSON(resp.Status, resp)
+}
+
+// Put actualiza nombre, categoría y sinónimos de una habilidad. Requiere rol de coordinación.
+// @Summary Actualizar habilidad
+// @Tags Habilidades
+// @Accept json
+// @Produce json
+// @Param id path int true "Id de la habilidad" Example(4)
+// @Param body body internaldto.HabilidadUpsert true "Habilidad"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/catalogos/habilidades/{id} [put]
+func (c *HabilidadesController) Put() {
+	if !c.requireCoordinacion() {
+		return
+	}
+	id, err := strconv.Atoi(strings.TrimSpace(c.Ctx.Input.Param(":id")))
+	if err != nil || id <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "id inválido", err), "id inválido")
+		return
+	}
+	var body internaldto.HabilidadUpsert
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+
+	result, err := internalservices.ActualizarHabilidad(id, body)
+	if err != nil {
+		c.respondError(err, "error actualizando habilidad")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+func (c *HabilidadesController) requireCoordinacion() bool {
+	if internalhelpers.EsCoordinacion(c.Ctx) {
+		return true
+	}
+	c.respondError(helpers.NewAppError(http.StatusForbidden, "requiere rol de coordinación", nil), "requiere rol de coordinación")
+	return false
+}
+
+func (c *HabilidadesController) respondError(err error, fallback string) {
+	appErr := helpers.AsAppError(err, fallback)
+	resp := internalhelpers.Fail(appErr.Status, appErr.Message)
+	c.writeJSON(resp.Status, resp)
+}
+
+func (c *HabilidadesController) writeJSON(status int, payload internaldto.APIResponseDTO) {
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = payload
+	_ = c.ServeJSON()
+}
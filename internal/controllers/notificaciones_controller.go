@@ -0,0 +1,284 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	rootcontrollers "github.com/udistrital/pasantia_mid/controllers"
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	internalservices "github.com/udistrital/pasantia_mid/internal/services"
+)
+
+// NotificacionesController expone la bandeja local de notificaciones del tercero autenticado.
+type NotificacionesController struct {
+	rootcontrollers.BaseController
+}
+
+// GetAll lista la bandeja del tercero.
+// @Summary Bandeja de notificaciones
+// @Description Notificaciones del tercero, de la más reciente a la más antigua. Incluye las que no se pudieron entregar por el canal externo (estado_envio FALLIDA o SIN_CANAL), las que van por correo en un resumen (EN_RESUMEN) y las que el tercero no quiere por correo (OMITIDA). Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"items":[{"id":31,"tercero_id":4567,"asunto":"Nueva entrevista","plantilla":"entrevista","datos":{"entrevista_id":8},"leida":false,"estado_envio":"ENVIADA","fecha_creacion":"2026-10-18T15:04:05Z"}],"page":1,"size":20,"total":1}}
+// @Tags Notificaciones
+// @Produce json
+// @Param tercero_id query int false "Id del tercero; se ignora si el JWT trae tercero_id" Example(4567)
+// @Param solo_no_leidas query bool false "Solo las no leídas" Example(true)
+// @Param page query int false "Página" Example(1)
+// @Param size query int false "Tamaño de página" Example(20)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/notificaciones [get]
+func (c *NotificacionesController) GetAll() {
+	terceroID, ok := c.requireTercero()
+	if !ok {
+		return
+	}
+	soloNoLeidas, _ := c.GetBool("solo_no_leidas", false)
+
+	data, err := internalservices.ListarNotificaciones(terceroID, soloNoLeidas, c.GetString("page"), c.GetString("size"))
+	if err != nil {
+		c.respondError(err, "error consultando notificaciones")
+		return
+	}
+	resp := internalhelpers.Ok(data)
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetNoLeidas retorna el contador de no leídas.
+// @Summary Contador de notificaciones no leídas
+// @Description Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"no_leidas":3}}
+// @Tags Notificaciones
+// @Produce json
+// @Param tercero_id query int false "Id del tercero; se ignora si el JWT trae tercero_id" Example(4567)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/notificaciones/no-leidas [get]
+func (c *NotificacionesController) GetNoLeidas() {
+	terceroID, ok := c.requireTercero()
+	if !ok {
+		return
+	}
+	data, err := internalservices.ContarNotificacionesNoLeidas(terceroID)
+	if err != nil {
+		c.respondError(err, "error contando notificaciones")
+		return
+	}
+	resp := internalhelpers.Ok(data)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PutLeida marca una notificación como leída.
+// @Summary Marcar notificación como leída
+// @Description Idempotente. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"id":31,"tercero_id":4567,"asunto":"Nueva entrevista","plantilla":"entrevista","leida":true,"estado_envio":"ENVIADA","fecha_creacion":"2026-10-18T15:04:05Z","fecha_lectura":"2026-10-18T16:00:00Z"}}
+// @Tags Notificaciones
+// @Produce json
+// @Param id path int true "Id de la notificación" Example(31)
+// @Param tercero_id query int false "Id del tercero; se ignora si el JWT trae tercero_id" Example(4567)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/notificaciones/:id/leida [put]
+func (c *NotificacionesController) PutLeida() {
+	terceroID, ok := c.requireTercero()
+	if !ok {
+		return
+	}
+	id, ok := c.parseID()
+	if !ok {
+		return
+	}
+	data, err := internalservices.MarcarNotificacionLeida(terceroID, id)
+	if err != nil {
+		c.respondError(err, "error marcando notificación")
+		return
+	}
+	resp := internalhelpers.Ok(data)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PutTodasLeidas marca como leídas todas las notificaciones del tercero.
+// @Summary Marcar todas las notificaciones como leídas
+// @Description Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"actualizadas":3}}
+// @Tags Notificaciones
+// @Produce json
+// @Param tercero_id query int false "Id del tercero; se ignora si el JWT trae tercero_id" Example(4567)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/notificaciones/leidas [put]
+func (c *NotificacionesController) PutTodasLeidas() {
+	terceroID, ok := c.requireTercero()
+	if !ok {
+		return
+	}
+	data, err := internalservices.MarcarTodasNotificacionesLeidas(terceroID)
+	if err != nil {
+		c.respondError(err, "error marcando notificaciones")
+		return
+	}
+	resp := internalhelpers.Ok(data)
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetPreferencias retorna las preferencias de notificación del tercero.
+// @Summary Preferencias de notificación
+// @Description Frecuencia por tipo de notificación (plantilla) y canal: app (bandeja) y email (entrega externa). Valores: INSTANTANEA, DIARIA, SEMANAL o DESACTIVADA; DIARIA y SEMANAL agrupan las notificaciones en un resumen. Se listan todos los tipos configurables con su valor efectivo. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"tercero_id":4567,"por_defecto":{"app":"INSTANTANEA","email":"DIARIA"},"tipos":{"postulacion_estado":{"app":"INSTANTANEA","email":"SEMANAL"},"invitacion_recibida":{"app":"INSTANTANEA","email":"DIARIA"}},"fecha_actualizacion":"2026-10-18T15:04:05Z"}}
+// @Tags Notificaciones
+// @Produce json
+// @Param tercero_id query int false "Id del tercero; se ignora si el JWT trae tercero_id" Example(4567)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/notificaciones/preferencias [get]
+func (c *NotificacionesController) GetPreferencias() {
+	terceroID, ok := c.requireTercero()
+	if !ok {
+		return
+	}
+	data, err := internalservices.ObtenerPreferenciasNotificacion(terceroID)
+	if err != nil {
+		c.respondError(err, "error consultando preferencias")
+		return
+	}
+	resp := internalhelpers.Ok(data)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PutPreferencias reemplaza las preferencias de notificación del tercero.
+// @Summary Actualizar preferencias de notificación
+// @Description Reemplaza las preferencias: sin por_defecto todo es INSTANTANEA y un canal vacío en un tipo toma el valor de por_defecto. Las notificaciones que ya esperan un resumen salen con él. Ejemplo de request: {"por_defecto":{"app":"INSTANTANEA","email":"DIARIA"},"tipos":{"postulacion_estado":{"email":"SEMANAL"},"busqueda_guardada_alerta":{"app":"DIARIA","email":"DESACTIVADA"}}}
+// @Tags Notificaciones
+// @Accept json
+// @Produce json
+// @Param tercero_id query int false "Id del tercero; se ignora si el JWT trae tercero_id" Example(4567)
+// @Param body body internaldto.PreferenciasNotificacionUpdate true "Preferencias"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+// @router /v1/notificaciones/preferencias [put]
+func (c *NotificacionesController) PutPreferencias() {
+	terceroID, ok := c.requireTercero()
+	if !ok {
+		return
+	}
+	var body internaldto.PreferenciasNotificacionUpdate
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+	data, err := internalservices.ActualizarPreferenciasNotificacion(terceroID, body)
+	if err != nil {
+		c.respondError(err, "error guardando preferencias")
+		return
+	}
+	resp := internalhelpers.Ok(data)
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetOutbox lista los mensajes del outbox de notificaciones.
+// @Summary Outbox de notificaciones
+// @Description Entregas registradas por el MID con su estado: PENDIENTE (en cola o esperando reintento), ENVIADA o MUERTA (agotó los reintentos). Requiere rol de administración o coordinación. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":[{"id":12,"canal":"SERVICIO","estado":"MUERTA","intentos":6,"ultimo_error":"error enviando notificación","fecha_creacion":"2026-10-18T15:04:05Z","notificacion":{"id":31,"tercero_id":4567,"asunto":"Nueva entrevista","plantilla":"entrevista","leida":false,"estado_envio":"FALLIDA","fecha_creacion":"2026-10-18T15:04:05Z"}}]}
+// @Tags Notificaciones
+// @Produce json
+// @Param estado query string false "PENDIENTE, ENVIADA o MUERTA" Example(MUERTA)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 503 {object} internaldto.APIResponseDTO
+// @router /v1/admin/notificaciones/outbox [get]
+func (c *NotificacionesController) GetOutbox() {
+	if !c.requireOperador() {
+		return
+	}
+	data, err := internalservices.ListarOutbox(c.GetString("estado"))
+	if err != nil {
+		c.respondError(err, "error consultando outbox")
+		return
+	}
+	resp := internalhelpers.Ok(data)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PostReintentar vuelve a encolar un mensaje muerto del outbox.
+// @Summary Reintentar mensaje del outbox
+// @Description Pasa un mensaje MUERTA a PENDIENTE con los intentos en cero y el canal activo; el worker lo entrega de inmediato. Requiere rol de administración o coordinación.
+// @Tags Notificaciones
+// @Produce json
+// @Param id path int true "Id del mensaje" Example(12)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @router /v1/admin/notificaciones/outbox/:id/reintentar [post]
+func (c *NotificacionesController) PostReintentar() {
+	if !c.requireOperador() {
+		return
+	}
+	id, ok := c.parseID()
+	if !ok {
+		return
+	}
+	data, err := internalservices.ReintentarOutbox(int64(id))
+	if err != nil {
+		c.respondError(err, "error reintentando mensaje")
+		return
+	}
+	resp := internalhelpers.Ok(data)
+	c.writeJSON(resp.Status, resp)
+}
+
+// --------------------- helpers locales ---------------------
+
+// requireOperador admite administración y coordinación.
+func (c *NotificacionesController) requireOperador() bool {
+	if internalhelpers.EsAdministracion(c.Ctx) || internalhelpers.EsCoordinacion(c.Ctx) {
+		return true
+	}
+	c.respondError(helpers.NewAppError(http.StatusForbidden, "requiere rol de administración o coordinación", nil), "requiere rol de administración o coordinación")
+	return false
+}
+
+// requireTercero toma tercero_id del JWT; sin token se acepta el parámetro tercero_id.
+func (c *NotificacionesController) requireTercero() (int, bool) {
+	if id, err := internalhelpers.GetTerceroID(c.Ctx); err == nil && id > 0 {
+		return id, true
+	}
+	raw := strings.TrimSpace(c.GetString("tercero_id"))
+	if raw == "" {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "tercero_id requerido", nil), "tercero_id requerido")
+		return 0, false
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "tercero_id inválido", err), "tercero_id inválido")
+		return 0, false
+	}
+	return id, true
+}
+
+func (c *NotificacionesController) parseID() (int, bool) {
+	raw := strings.TrimSpace(c.Ctx.Input.Param(":id"))
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "id inválido", err), "id inválido")
+		return 0, false
+	}
+	return id, true
+}
+
+func (c *NotificacionesController) respondError(err error, fallback string) {
+	appErr := helpers.AsAppError(err, fallback)
+	resp := internalhelpers.Fail(appErr.Status, appErr.Message)
+	c.writeJSON(resp.Status, resp)
+}
+
+func (c *NotificacionesController) writeJSON(status int, payload internaldto.APIResponseDTO) {
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = payload
+	_ = c.ServeJSON()
+}
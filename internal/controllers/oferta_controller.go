@@ -23,7 +23,7 @@ type OfertaController struct {
 type CrearOfertaReq = internalservices.CrearOfertaReq
 
 // @Summary Crear oferta con PCs asociados
-// @Description Crea oferta en Castor_CRUD y asocia proyectos curriculares en lote.
+// @Description Crea oferta en Castor_CRUD y asocia proyectos curriculares en lote. Modalidad, ciudad y habilidades requeridas son opcionales y alimentan las recomendaciones. Ejemplo de request: {"oferta":{"titulo":"Practicante de datos","descripcion":"Apoyo en analítica","modalidad":"HIBRIDA","ciudad":"Bogotá","habilidades":["SQL","Python"]},"proyectos_curriculares":[57]}
 // @Tags Ofertas
 // @Accept json
 // @Produce json
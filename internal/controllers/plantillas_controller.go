@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	rootcontrollers "github.com/udistrital/pasantia_mid/controllers"
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	internalservices "github.com/udistrital/pasantia_mid/internal/services"
+)
+
+// PlantillasController expone las plantillas de notificación para su revisión.
+type PlantillasController struct {
+	rootcontrollers.BaseController
+}
+
+// GetAll lista las plantillas registradas.
+// @Summary Plantillas de notificación
+// @Description Plantillas que el MID renderiza antes de entregar la notificación, con sus idiomas y variables. Requiere rol de administración o coordinación. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":[{"id":"oferta_cancelada","descripcion":"La oferta a la que el estudiante postuló fue cancelada","locales":["en","es"],"variables":["nombre","oferta_titulo","empresa","oferta_id"]}]}
+// @Tags Notificaciones
+// @Produce json
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @router /v1/admin/plantillas [get]
+func (c *PlantillasController) GetAll() {
+	if !c.requireOperador() {
+		return
+	}
+	resp := internalhelpers.Ok(internalservices.ListarPlantillasNotificacion())
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetPreview renderiza una plantilla con datos de ejemplo.
+// @Summary Previsualizar plantilla de notificación
+// @Description Renderiza asunto, texto y HTML con los datos de ejemplo de la plantilla; datos (objeto JSON) reemplaza o agrega variables. Si la plantilla no tiene el idioma pedido se usa español. Requiere rol de administración o coordinación. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"id":"oferta_cancelada","locale":"en","asunto":"The internship Data analytics intern was cancelled","texto":"Hello Laura Gómez, ...","html":"<p>Hello Laura Gómez,</p>..."}}
+// @Tags Notificaciones
+// @Produce json
+// @Param id path string true "Id de la plantilla" Example(oferta_cancelada)
+// @Param locale query string false "es o en; por defecto notificaciones_locale" Example(en)
+// @Param datos query string false "Variables en JSON" Example({"oferta_titulo":"Data analytics intern"})
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 422 {object} internaldto.APIResponseDTO
+// @router /v1/admin/plantillas/:id/preview [get]
+func (c *PlantillasController) GetPreview() {
+	if !c.requireOperador() {
+		return
+	}
+	id := strings.TrimSpace(c.Ctx.Input.Param(":id"))
+	data, err := internalservices.PrevisualizarPlantilla(id, c.GetString("locale"), c.GetString("datos"))
+	if err != nil {
+		c.respondError(err, "error renderizando plantilla")
+		return
+	}
+	resp := internalhelpers.Ok(data)
+	c.writeJSON(resp.Status, resp)
+}
+
+// --------------------- helpers locales ---------------------
+
+// requireOperador admite administración y coordinación.
+func (c *PlantillasController) requireOperador() bool {
+	if internalhelpers.EsAdministracion(c.Ctx) || internalhelpers.EsCoordinacion(c.Ctx) {
+		return true
+	}
+	c.respondError(helpers.NewAppError(http.StatusForbidden, "requiere rol de administración o coordinación", nil), "requiere rol de administración o coordinación")
+	return false
+}
+
+func (c *PlantillasController) respondError(err error, fallback string) {
+	appErr := helpers.AsAppError(err, fallback)
+	resp := internalhelpers.Fail(appErr.Status, appErr.Message)
+	c.writeJSON(resp.Status, resp)
+}
+
+func (c *PlantillasController) writeJSON(status int, payload internaldto.APIResponseDTO) {
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = payload
+	_ = c.ServeJSON()
+}
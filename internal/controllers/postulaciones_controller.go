@@ -19,12 +19,14 @@ type PostulacionesController struct {
 
 // GetByOferta lista las postulaciones de una oferta asociada al tutor.
 // @Summary Listar postulaciones de la oferta
-// @Description Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"items":[{"id":101,"estado":"PPE_CTR","visto":true}],"total":1}}
+// @Description Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"items":[{"id":101,"estado":"PPE_CTR","visto":true,"puntaje":82.5}],"total":1}}
 // @Tags Postulaciones
 // @Accept json
 // @Produce json
 // @Param tutor_id query int true "Id del tutor" Example(7890)
 // @Param id path int true "Id de la oferta" Example(21)
+// @Param sort query string false "Campo de ordenamiento (puntaje)" Example(puntaje)
+// @Param order query string false "asc | desc (default desc)" Example(desc)
 // @Success 200 {object} internaldto.APIResponseDTO
 // @Failure 400 {object} internaldto.APIResponseDTO
 // @Failure 404 {object} internaldto.APIResponseDTO
@@ -39,7 +41,16 @@ func (c *PostulacionesController) GetByOferta() {
 		return
 	}
 
-	result, err := internalservices.ListarPostulaciones(c.Ctx.Request.Context(), tutorID, ofertaID)
+	orden := internalservices.PostulacionesOrden{
+		SortBy: strings.TrimSpace(c.GetString("sort")),
+		Order:  strings.TrimSpace(c.GetString("order")),
+	}
+	if orden.SortBy != "" && !strings.EqualFold(orden.SortBy, "puntaje") {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "sort no soportado", nil), "sort no soportado")
+		return
+	}
+
+	result, err := internalservices.ListarPostulaciones(c.Ctx.Request.Context(), tutorID, ofertaID, orden)
 	if err != nil {
 		c.respondError(err, "error consultando postulaciones")
 		return
@@ -49,6 +60,46 @@ func (c *PostulacionesController) GetByOferta() {
 	c.writeJSON(resp.Status, resp)
 }
 
+// GetExport descarga las postulaciones de la oferta en CSV o XLSX.
+// @Summary Exportar postulaciones de la oferta
+// @Description Incluye estudiante, proyecto curricular, estado, fecha, visto, hoja de vida y puntaje. Disponible para el tutor dueño de la oferta o coordinación (rol en el token) si los proyectos curriculares de la oferta están en su asignación.
+// @Tags Postulaciones
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param id path int true "Id de la oferta" Example(21)
+// @Param format query string false "csv | xlsx (default csv)" Example(xlsx)
+// @Success 200 {file} file
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *PostulacionesController) GetExport() {
+	ofertaID, ok := c.parseOfertaID()
+	if !ok {
+		return
+	}
+	var coordinacion *internaldto.AsignacionCoordinacion
+	tutorID := 0
+	if internalhelpers.EsCoordinacion(c.Ctx) {
+		facultades, proyectos := internalhelpers.AsignacionAcademica(c.Ctx)
+		coordinacion = &internaldto.AsignacionCoordinacion{Facultades: facultades, Proyectos: proyectos}
+	} else if tutorID, ok = c.requireTutor(); !ok {
+		return
+	}
+
+	archivo, err := internalservices.ExportarPostulaciones(c.Ctx.Request.Context(), tutorID, ofertaID, c.GetString("format"), coordinacion)
+	if err != nil {
+		c.respondError(err, "error exportando postulaciones")
+		return
+	}
+
+	c.Ctx.Output.Header("Content-Type", archivo.ContentType)
+	c.Ctx.Output.Header("Content-Disposition", `attachment; filename="`+archivo.Nombre+`"`)
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	_ = c.Ctx.Output.Body(archivo.Contenido)
+}
+
 // PostAccion ejecuta una acción sobre una postulación.
 // @Summary Ejecutar acción sobre postulación
 // @Description Acciones válidas: VISTO, DESCARTAR, PRESELECCIONAR, SELECCIONAR. Ejemplo de request: {"accion":"PRESELECCIONAR","comentario":"Avanza a entrevista"}
@@ -93,6 +144,51 @@ func (c *PostulacionesController) PostAccion() {
 	c.writeJSON(resp.Status, resp)
 }
 
+// PostAccionesLote ejecuta una acción sobre varias postulaciones de la oferta.
+// @Summary Acciones en lote sobre postulaciones
+// @Description Acciones válidas: VISTO, DESCARTAR, PRESELECCIONAR. Retorna el resultado por ítem. Ejemplo de request: {"ids":[101,102],"accion":"DESCARTAR","comentario":"Perfil no ajustado"}
+// @Tags Postulaciones
+// @Accept json
+// @Produce json
+// @Param tutor_id query int true "Id del tutor" Example(7890)
+// @Param id path int true "Id de la oferta" Example(21)
+// @Param body body internaldto.PostulacionAccionLote true "Acción en lote"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *PostulacionesController) PostAccionesLote() {
+	ofertaID, ok := c.parseOfertaID()
+	if !ok {
+		return
+	}
+	tutorID, ok := c.requireTutor()
+	if !ok {
+		return
+	}
+
+	var body internaldto.PostulacionAccionLote
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+	if strings.TrimSpace(body.Accion) == "" {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "accion requerida", nil), "accion requerida")
+		return
+	}
+
+	result, err := internalservices.EjecutarAccionesLote(c.Ctx.Request.Context(), tutorID, ofertaID, body)
+	if err != nil {
+		c.respondError(err, "error ejecutando acciones")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	resp.Message = "Acciones procesadas"
+	c.writeJSON(resp.Status, resp)
+}
+
 // PutVisto marca una postulación como vista.
 // @Summary Marcar postulación como vista
 // @Description Marca la postulación como vista (PSRV_CTR) si está en PSPO_CTR.
@@ -161,6 +257,143 @@ func (c *PostulacionesController) PutAceptarSeleccion() {
 	c.writeJSON(resp.Status, resp)
 }
 
+// GetHistorial retorna el historial cronológico de la postulación.
+// @Summary Historial de la postulación
+// @Description Acciones registradas sobre la postulación con el nombre del actor. Acepta tutor_id (dueño de la oferta) o estudiante_id (postulado).
+// @Tags Postulaciones
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param estudiante_id query int false "Id del estudiante" Example(4567)
+// @Param id path int true "Id de la postulación" Example(101)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *PostulacionesController) GetHistorial() {
+	postulacionID, ok := c.parsePostulacionID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+
+	result, err := internalservices.HistorialPostulacion(c.Ctx.Request.Context(), postulacionID, tutorID, estudianteID)
+	if err != nil {
+		c.respondError(err, "error consultando historial")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetComentarios lista el hilo de comentarios de la postulación.
+// @Summary Comentarios de la postulación
+// @Description El estudiante solo recibe los comentarios con visibilidad PUBLICA.
+// @Tags Postulaciones
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param estudiante_id query int false "Id del estudiante" Example(4567)
+// @Param id path int true "Id de la postulación" Example(101)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *PostulacionesController) GetComentarios() {
+	postulacionID, ok := c.parsePostulacionID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+
+	result, err := internalservices.ListarComentariosPostulacion(c.Ctx.Request.Context(), postulacionID, tutorID, estudianteID)
+	if err != nil {
+		c.respondError(err, "error consultando comentarios")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PostComentario agrega un comentario al hilo de la postulación.
+// @Summary Comentar postulación
+// @Description Visibilidad PUBLICA (default) o INTERNA (solo tutor). Ejemplo de request: {"mensaje":"¿Tienes disponibilidad el lunes?","visibilidad":"PUBLICA"}
+// @Tags Postulaciones
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param estudiante_id query int false "Id del estudiante" Example(4567)
+// @Param id path int true "Id de la postulación" Example(101)
+// @Param body body internaldto.PostulacionComentarioCreate true "Comentario"
+// @Success 201 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *PostulacionesController) PostComentario() {
+	postulacionID, ok := c.parsePostulacionID()
+	if !ok {
+		return
+	}
+	tutorID, estudianteID, ok := c.requireActor()
+	if !ok {
+		return
+	}
+
+	var body internaldto.PostulacionComentarioCreate
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+
+	result, err := internalservices.CrearComentarioPostulacion(c.Ctx.Request.Context(), postulacionID, tutorID, estudianteID, body)
+	if err != nil {
+		c.respondError(err, "error registrando comentario")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	resp.Status = http.StatusCreated
+	resp.Message = "Comentario registrado"
+	c.writeJSON(resp.Status, resp)
+}
+
+// requireActor lee tutor_id o estudiante_id; al menos uno es obligatorio.
+func (c *PostulacionesController) requireActor() (int, int, bool) {
+	var tutorID, estudianteID int
+	if raw := strings.TrimSpace(c.GetString("tutor_id")); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			c.respondError(helpers.NewAppError(http.StatusBadRequest, "tutor_id inválido", err), "tutor_id inválido")
+			return 0, 0, false
+		}
+		tutorID = id
+	}
+	if raw := strings.TrimSpace(c.GetString("estudiante_id")); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			c.respondError(helpers.NewAppError(http.StatusBadRequest, "estudiante_id inválido", err), "estudiante_id inválido")
+			return 0, 0, false
+		}
+		estudianteID = id
+	}
+	if tutorID <= 0 && estudianteID <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "tutor_id o estudiante_id requerido", nil), "tutor_id o estudiante_id requerido")
+		return 0, 0, false
+	}
+	return tutorID, estudianteID, true
+}
+
 func (c *PostulacionesController) requireTutor() (int, bool) {
 	raw := strings.TrimSpace(c.GetString("tutor_id"))
 	if raw == "" {
@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	rootcontrollers "github.com/udistrital/pasantia_mid/controllers"
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	internalservices "github.com/udistrital/pasantia_mid/internal/services"
+)
+
+// RubricaController gestiona la rúbrica de evaluación por oferta y la calificación de postulaciones.
+type RubricaController struct {
+	rootcontrollers.BaseController
+}
+
+// GetRubrica retorna los criterios ponderados de la oferta.
+// @Summary Consultar rúbrica de la oferta
+// @Description Disponible para el tutor dueño de la oferta o para coordinación (rol en el token) si los proyectos curriculares de la oferta están en su asignación. Ejemplo de respuesta: {"Success":true,"Status":200,"Message":"OK","Data":{"oferta_id":21,"criterios":[{"codigo":"TEC","nombre":"Conocimientos técnicos","peso":0.6,"escala_max":5}],"peso_total":1,"configurada":true}}
+// @Tags Evaluacion
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param id path int true "Id de la oferta" Example(21)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *RubricaController) GetRubrica() {
+	ofertaID, ok := c.parseOfertaID()
+	if !ok {
+		return
+	}
+	tutorID, coordinacion, ok := c.requireTutorOCoordinacion()
+	if !ok {
+		return
+	}
+
+	result, err := internalservices.GetRubricaOferta(c.Ctx.Request.Context(), tutorID, ofertaID, coordinacion)
+	if err != nil {
+		c.respondError(err, "error consultando rúbrica")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PutRubrica crea o reemplaza la rúbrica de la oferta.
+// @Summary Definir rúbrica de la oferta
+// @Description El peso de cada criterio es relativo; escala_max por defecto es 5. Ejemplo de request: {"criterios":[{"codigo":"TEC","nombre":"Conocimientos técnicos","peso":0.6},{"codigo":"COM","nombre":"Comunicación","peso":0.4}]}
+// @Tags Evaluacion
+// @Accept json
+// @Produce json
+// @Param tutor_id query int true "Id del tutor" Example(7890)
+// @Param id path int true "Id de la oferta" Example(21)
+// @Param body body internaldto.RubricaUpsert true "Criterios de la rúbrica"
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *RubricaController) PutRubrica() {
+	ofertaID, ok := c.parseOfertaID()
+	if !ok {
+		return
+	}
+	tutorID, ok := c.requireTutor()
+	if !ok {
+		return
+	}
+
+	var body internaldto.RubricaUpsert
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+
+	result, err := internalservices.GuardarRubricaOferta(c.Ctx.Request.Context(), tutorID, ofertaID, body)
+	if err != nil {
+		c.respondError(err, "error guardando rúbrica")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	resp.Message = "Rúbrica guardada"
+	c.writeJSON(resp.Status, resp)
+}
+
+// GetEvaluacion retorna la última evaluación de la postulación y su histórico.
+// @Summary Consultar evaluación de la postulación
+// @Description Solo para el tutor dueño de la oferta o coordinación dentro de su asignación; el estudiante no tiene acceso a los puntajes.
+// @Tags Evaluacion
+// @Accept json
+// @Produce json
+// @Param tutor_id query int false "Id del tutor" Example(7890)
+// @Param id path int true "Id de la postulación" Example(101)
+// @Success 200 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *RubricaController) GetEvaluacion() {
+	postulacionID, ok := c.parsePostulacionID()
+	if !ok {
+		return
+	}
+	tutorID, coordinacion, ok := c.requireTutorOCoordinacion()
+	if !ok {
+		return
+	}
+
+	result, err := internalservices.GetEvaluacionPostulacion(c.Ctx.Request.Context(), tutorID, postulacionID, coordinacion)
+	if err != nil {
+		c.respondError(err, "error consultando evaluación")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	c.writeJSON(resp.Status, resp)
+}
+
+// PostEvaluacion califica la postulación con la rúbrica de la oferta.
+// @Summary Evaluar postulación
+// @Description Se requiere un puntaje por cada criterio de la rúbrica. Ejemplo de request: {"puntajes":[{"criterio":"TEC","valor":4},{"criterio":"COM","valor":5}],"comentario":"Buen perfil"}
+// @Tags Evaluacion
+// @Accept json
+// @Produce json
+// @Param tutor_id query int true "Id del tutor" Example(7890)
+// @Param id path int true "Id de la postulación" Example(101)
+// @Param body body internaldto.EvaluacionPostulacionReq true "Puntajes por criterio"
+// @Success 201 {object} internaldto.APIResponseDTO
+// @Failure 400 {object} internaldto.APIResponseDTO
+// @Failure 403 {object} internaldto.APIResponseDTO
+// @Failure 404 {object} internaldto.APIResponseDTO
+// @Failure 409 {object} internaldto.APIResponseDTO
+// @Failure 500 {object} internaldto.APIResponseDTO
+func (c *RubricaController) PostEvaluacion() {
+	postulacionID, ok := c.parsePostulacionID()
+	if !ok {
+		return
+	}
+	tutorID, ok := c.requireTutor()
+	if !ok {
+		return
+	}
+
+	var body internaldto.EvaluacionPostulacionReq
+	if err := c.ParseJSONBody(&body); err != nil {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "cuerpo inválido", err), "cuerpo inválido")
+		return
+	}
+
+	result, err := internalservices.EvaluarPostulacion(c.Ctx.Request.Context(), tutorID, postulacionID, body)
+	if err != nil {
+		c.respondError(err, "error registrando evaluación")
+		return
+	}
+
+	resp := internalhelpers.Ok(result)
+	resp.Status = http.StatusCreated
+	resp.Message = "Evaluación registrada"
+	c.writeJSON(resp.Status, resp)
+}
+
+// requireTutorOCoordinacion acepta un token con rol de coordinación, retornando su asignación
+// académica, o en su defecto exige tutor_id.
+func (c *RubricaController) requireTutorOCoordinacion() (int, *internaldto.AsignacionCoordinacion, bool) {
+	if internalhelpers.EsCoordinacion(c.Ctx) {
+		facultades, proyectos := internalhelpers.AsignacionAcademica(c.Ctx)
+		return 0, &internaldto.AsignacionCoordinacion{Facultades: facultades, Proyectos: proyectos}, true
+	}
+	tutorID, ok := c.requireTutor()
+	return tutorID, nil, ok
+}
+
+func (c *RubricaController) requireTutor() (int, bool) {
+	raw := strings.TrimSpace(c.GetString("tutor_id"))
+	if raw == "" {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "tutor_id requerido", nil), "tutor_id requerido")
+		return 0, false
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "tutor_id inválido", err), "tutor_id inválido")
+		return 0, false
+	}
+	return id, true
+}
+
+func (c *RubricaController) parseOfertaID() (int, bool) {
+	raw := strings.TrimSpace(c.Ctx.Input.Param(":id"))
+	val, err := strconv.Atoi(raw)
+	if err != nil || val <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "id inválido", err), "id inválido")
+		return 0, false
+	}
+	return val, true
+}
+
+func (c *RubricaController) parsePostulacionID() (int64, bool) {
+	raw := strings.TrimSpace(c.Ctx.Input.Param(":id"))
+	val, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || val <= 0 {
+		c.respondError(helpers.NewAppError(http.StatusBadRequest, "id inválido", err), "id inválido")
+		return 0, false
+	}
+	return val, true
+}
+
+func (c *RubricaController) respondError(err error, fallback string) {
+	appErr := helpers.AsAppError(err, fallback)
+	resp := internalhelpers.Fail(appErr.Status, appErr.Message)
+	c.writeJSON(resp.Status, resp)
+}
+
+func (c *RubricaController) writeJSON(status int, payload internaldto.APIResponseDTO) {
+	c.Ctx.Output.SetStatus(status)
+	c.Data["json"] = payload
+	_ = c.ServeJSON()
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+// BusquedaGuardadaFiltros son los criterios de una búsqueda guardada.
+type BusquedaGuardadaFiltros struct {
+	Query      string `json:"q,omitempty"`
+	PCID       int    `json:"pc_id,omitempty"`
+	FacultadID int    `json:"facultad_id,omitempty"`
+	Modalidad  string `json:"modalidad,omitempty"`
+	Skills     string `json:"skills,omitempty"`
+}
+
+// BusquedaGuardadaUpsert define el cuerpo para crear o actualizar una búsqueda guardada.
+type BusquedaGuardadaUpsert struct {
+	Nombre     string                   `json:"nombre"`
+	Filtros    *BusquedaGuardadaFiltros `json:"filtros,omitempty"`
+	Frecuencia string                   `json:"frecuencia,omitempty"`
+	Activa     *bool                    `json:"activa,omitempty"`
+}
+
+// BusquedaGuardada es una búsqueda guardada por un tutor (perfiles) o un estudiante (ofertas).
+type BusquedaGuardada struct {
+	ID              int                     `json:"id"`
+	Nombre          string                  `json:"nombre"`
+	Tipo            string                  `json:"tipo"`
+	Filtros         BusquedaGuardadaFiltros `json:"filtros"`
+	Frecuencia      string                  `json:"frecuencia"`
+	Activa          bool                    `json:"activa"`
+	TotalResultados int                     `json:"total_resultados"`
+	Pendientes      int                     `json:"pendientes_resumen"`
+	UltimaEjecucion *time.Time              `json:"ultima_ejecucion,omitempty"`
+	UltimoEnvio     *time.Time              `json:"ultimo_envio,omitempty"`
+	FechaCreacion   *time.Time              `json:"fecha_creacion,omitempty"`
+}
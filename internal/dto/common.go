@@ -29,6 +29,7 @@ type EstudiantePerfilCard struct {
 	Visible                  bool                   `json:"visible"`
 	Guardado                 bool                   `json:"guardado"`
 	TratamientoDatosAceptado bool                   `json:"tratamiento_datos_aceptado"`
+	Anonimo                  bool                   `json:"anonimo"`
 }
 
 // PostulacionAccion encapsula la acción tomada sobre una postulación.
@@ -37,6 +38,19 @@ type PostulacionAccion struct {
 	Comentario *string `json:"comentario,omitempty"`
 }
 
+// PostulacionAccionLote aplica una misma acción a varias postulaciones de una oferta.
+type PostulacionAccionLote struct {
+	Ids        []int64 `json:"ids"`
+	Accion     string  `json:"accion"`
+	Comentario *string `json:"comentario,omitempty"`
+}
+
+// PostulacionComentarioCreate describe un comentario en el hilo de la postulación.
+type PostulacionComentarioCreate struct {
+	Mensaje     string `json:"mensaje"`
+	Visibilidad string `json:"visibilidad,omitempty"`
+}
+
 // InvitacionCreate describe la solicitud para crear/invitar a un estudiante.
 type InvitacionCreate struct {
 	OfertaID         *int64 `json:"oferta_id,omitempty"`
@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// ConsentimientoReq es el cuerpo para aceptar los términos de tratamiento de datos.
+// Version debe coincidir con la versión vigente de los términos.
+type ConsentimientoReq struct {
+	TerceroID *int   `json:"tercero_id"`
+	Version   string `json:"version"`
+}
+
+// ConsentimientoRevocarReq es el cuerpo para revocar el consentimiento de tratamiento de datos.
+type ConsentimientoRevocarReq struct {
+	TerceroID *int   `json:"tercero_id"`
+	Motivo    string `json:"motivo,omitempty"`
+}
+
+// ConsentimientoEstado resume el consentimiento del estudiante frente a los términos vigentes.
+// RequiereAceptacion indica que el cliente debe volver a mostrar los términos.
+type ConsentimientoEstado struct {
+	TerceroID          int        `json:"tercero_id"`
+	PerfilID           int        `json:"perfil_id"`
+	VersionVigente     string     `json:"version_vigente"`
+	TerminosURL        string     `json:"terminos_url,omitempty"`
+	VersionAceptada    string     `json:"version_aceptada,omitempty"`
+	Aceptado           bool       `json:"aceptado"`
+	Vigente            bool       `json:"vigente"`
+	RequiereAceptacion bool       `json:"requiere_aceptacion"`
+	FechaAceptacion    *time.Time `json:"fecha_aceptacion,omitempty"`
+}
+
+// ConsentimientoEvento es un registro de la auditoría de consentimientos.
+type ConsentimientoEvento struct {
+	ID        int        `json:"id"`
+	TerceroID int        `json:"tercero_id"`
+	PerfilID  int        `json:"perfil_id"`
+	Accion    string     `json:"accion"`
+	Version   string     `json:"version,omitempty"`
+	IP        string     `json:"ip,omitempty"`
+	UserAgent string     `json:"user_agent,omitempty"`
+	Motivo    string     `json:"motivo,omitempty"`
+	Fecha     *time.Time `json:"fecha,omitempty"`
+}
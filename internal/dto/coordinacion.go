@@ -0,0 +1,91 @@
+package dto
+
+// CoordinacionDashboardFiltro describe el alcance del tablero de coordinación. Sin facultad ni
+// proyecto curricular se consideran todos los programas; Periodo es YYYY-1 o YYYY-2.
+type CoordinacionDashboardFiltro struct {
+	FacultadID            int    `json:"facultad_id,omitempty"`
+	ProyectoCurricularID  int    `json:"proyecto_curricular_id,omitempty"`
+	ProyectosCurriculares []int  `json:"proyectos_curriculares,omitempty"`
+	Periodo               string `json:"periodo,omitempty"`
+	Desde                 string `json:"desde,omitempty"`
+	Hasta                 string `json:"hasta,omitempty"`
+	// Asignación del usuario (claims del token): los filtros deben quedar dentro de ella.
+	FacultadesAsignadas []int `json:"-"`
+	ProyectosAsignados  []int `json:"-"`
+}
+
+// AsignacionCoordinacion son las facultades y proyectos curriculares asignados a un usuario de
+// coordinación según los claims del token; acota las ofertas que puede consultar.
+type AsignacionCoordinacion struct {
+	Facultades []int
+	Proyectos  []int
+}
+
+// EstadoConteo es el total de registros en un estado con su nombre en parámetros.
+type EstadoConteo struct {
+	Codigo string `json:"code"`
+	Estado string `json:"estado"`
+	Total  int    `json:"total"`
+}
+
+// OfertaPostulantes resume los postulantes de una oferta del alcance.
+type OfertaPostulantes struct {
+	OfertaID      int64  `json:"oferta_id"`
+	Titulo        string `json:"titulo"`
+	Estado        string `json:"estado"`
+	EmpresaID     int64  `json:"empresa_id,omitempty"`
+	Postulantes   int    `json:"postulantes"`
+	Seleccionados int    `json:"seleccionados"`
+}
+
+// TiempoSeleccion mide en días el tiempo entre la postulación y la selección del estudiante.
+type TiempoSeleccion struct {
+	Muestras     int     `json:"muestras"`
+	PromedioDias float64 `json:"promedio_dias"`
+	MedianaDias  float64 `json:"mediana_dias"`
+}
+
+// Colocacion es la tasa de estudiantes del alcance seleccionados en alguna oferta (porcentaje).
+type Colocacion struct {
+	Estudiantes   int     `json:"estudiantes"`
+	Postulados    int     `json:"postulados"`
+	Seleccionados int     `json:"seleccionados"`
+	Tasa          float64 `json:"tasa"`
+}
+
+// EmpresaResumen agrupa la actividad de una empresa en el alcance.
+type EmpresaResumen struct {
+	EmpresaID     int64  `json:"empresa_id"`
+	Empresa       string `json:"empresa"`
+	Ofertas       int    `json:"ofertas"`
+	Postulaciones int    `json:"postulaciones"`
+	Seleccionados int    `json:"seleccionados"`
+}
+
+// EstudianteSinPostulacion es un estudiante del alcance que no se ha postulado en el periodo.
+type EstudianteSinPostulacion struct {
+	TerceroID            int    `json:"tercero_id"`
+	PerfilID             int    `json:"perfil_id"`
+	ProyectoCurricularID int    `json:"proyecto_curricular_id"`
+	Nombre               string `json:"nombre,omitempty"`
+}
+
+// EstudiantesSinPostulacion lista (hasta un máximo) los estudiantes sin postulaciones y su total.
+type EstudiantesSinPostulacion struct {
+	Total int                        `json:"total"`
+	Items []EstudianteSinPostulacion `json:"items"`
+}
+
+// CoordinacionDashboard es el tablero agregado para coordinadores de proyecto curricular y decanaturas.
+type CoordinacionDashboard struct {
+	Filtro                    CoordinacionDashboardFiltro `json:"filtro"`
+	TotalOfertas              int                         `json:"total_ofertas"`
+	TotalPostulaciones        int                         `json:"total_postulaciones"`
+	OfertasPorEstado          []EstadoConteo              `json:"ofertas_por_estado"`
+	PostulantesPorOferta      []OfertaPostulantes         `json:"postulantes_por_oferta"`
+	PromedioPostulantes       float64                     `json:"promedio_postulantes"`
+	TiempoSeleccion           TiempoSeleccion             `json:"tiempo_seleccion"`
+	Colocacion                Colocacion                  `json:"colocacion"`
+	TopEmpresas               []EmpresaResumen            `json:"top_empresas"`
+	EstudiantesSinPostulacion EstudiantesSinPostulacion   `json:"estudiantes_sin_postulacion"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// SolicitudEliminacionReq es el cuerpo con el que el estudiante pide eliminar sus datos.
+type SolicitudEliminacionReq struct {
+	Motivo string `json:"motivo,omitempty"`
+}
+
+// SolicitudEliminacionResolucion es el cuerpo con el que coordinación confirma o rechaza la solicitud.
+type SolicitudEliminacionResolucion struct {
+	Observacion string `json:"observacion,omitempty"`
+}
+
+// SolicitudEliminacionEvento es una entrada de la bitácora de la solicitud.
+type SolicitudEliminacionEvento struct {
+	Accion  string     `json:"accion"`
+	ActorID int        `json:"actor_id,omitempty"`
+	Rol     string     `json:"rol,omitempty"`
+	Detalle string     `json:"detalle,omitempty"`
+	Fecha   *time.Time `json:"fecha,omitempty"`
+}
+
+// SolicitudEliminacion es una solicitud de eliminación de datos personales (habeas data).
+// Resultado cuenta los registros anonimizados o eliminados por tipo una vez ejecutada.
+type SolicitudEliminacion struct {
+	ID              int                          `json:"id"`
+	TerceroID       int                          `json:"tercero_id"`
+	PerfilID        int                          `json:"perfil_id,omitempty"`
+	Estado          string                       `json:"estado"`
+	Motivo          string                       `json:"motivo,omitempty"`
+	Observacion     string                       `json:"observacion,omitempty"`
+	CoordinadorID   int                          `json:"coordinador_id,omitempty"`
+	Resultado       map[string]int               `json:"resultado,omitempty"`
+	FechaSolicitud  *time.Time                   `json:"fecha_solicitud,omitempty"`
+	FechaResolucion *time.Time                   `json:"fecha_resolucion,omitempty"`
+	Eventos         []SolicitudEliminacionEvento `json:"eventos,omitempty"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+// EntrevistaSlot es una franja horaria propuesta para la entrevista.
+type EntrevistaSlot struct {
+	Inicio time.Time `json:"inicio"`
+	Fin    time.Time `json:"fin"`
+}
+
+// EntrevistaPropuesta propone franjas para una entrevista; requiere lugar o enlace de video.
+type EntrevistaPropuesta struct {
+	Slots       []EntrevistaSlot `json:"slots"`
+	Lugar       string           `json:"lugar,omitempty"`
+	EnlaceVideo string           `json:"enlace_video,omitempty"`
+	Notas       string           `json:"notas,omitempty"`
+}
+
+// EntrevistaConfirmacion selecciona una de las franjas propuestas por índice.
+type EntrevistaConfirmacion struct {
+	SlotIndex int `json:"slot_index"`
+}
+
+// EntrevistaCancelacion registra el motivo de la cancelación.
+type EntrevistaCancelacion struct {
+	Motivo string `json:"motivo,omitempty"`
+}
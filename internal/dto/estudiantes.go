@@ -8,6 +8,9 @@ type EstudiantePerfilUpsert struct {
 	CVDocumentoID            *string `json:"cv_documento_id,omitempty"`
 	Visible                  *bool   `json:"visible,omitempty"`
 	TratamientoDatosAceptado *bool   `json:"tratamiento_datos_aceptado,omitempty"`
+	ModalidadPreferida       *string `json:"modalidad_preferida,omitempty"`
+	CiudadPreferida          *string `json:"ciudad_preferida,omitempty"`
+	Anonimo                  *bool   `json:"anonimo,omitempty"`
 }
 
 // EstudiantePerfilUpsertReq agrega la llave del tercero al payload genérico.
@@ -17,9 +20,12 @@ type EstudiantePerfilUpsertReq struct {
 }
 
 // EstudiantePerfilVisibilidadReq representa el cuerpo mínimo para actualizar visibilidad.
+// Con anonimo = true los tutores solo ven el perfil sin datos de identificación ni CV
+// hasta que el estudiante acepte una invitación suya o se postule a una de sus ofertas.
 type EstudiantePerfilVisibilidadReq struct {
 	TerceroID *int  `json:"tercero_id"`
 	Visible   *bool `json:"visible"`
+	Anonimo   *bool `json:"anonimo,omitempty"`
 }
 
 // EstudiantePerfilCVReq representa el cuerpo mínimo para actualizar el CV.
@@ -41,3 +47,21 @@ type EstudiantePerfilDocumentoResp struct {
 	PerfilID    *int                   `json:"perfil_id,omitempty"`
 	Perfil      map[string]interface{} `json:"perfil,omitempty"`
 }
+
+// PerfilCompletitudItem es un criterio del puntaje de completitud del perfil.
+type PerfilCompletitudItem struct {
+	Clave    string `json:"clave"`
+	Peso     int    `json:"peso"`
+	Cumplido bool   `json:"cumplido"`
+	Mensaje  string `json:"mensaje,omitempty"`
+}
+
+// PerfilCompletitud resume qué tan completo está el perfil (0-100) y qué le falta al estudiante.
+// PuedePostular es falso mientras el puntaje no alcance el umbral configurado.
+type PerfilCompletitud struct {
+	Puntaje       int                     `json:"puntaje"`
+	Umbral        int                     `json:"umbral"`
+	PuedePostular bool                    `json:"puede_postular"`
+	Items         []PerfilCompletitudItem `json:"items"`
+	Faltantes     []string                `json:"faltantes"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// CarpetaGuardados agrupa perfiles guardados por un tutor.
+type CarpetaGuardados struct {
+	ID     int    `json:"id"`
+	Nombre string `json:"nombre"`
+	Total  int    `json:"total"`
+}
+
+// CarpetaUpsert define el cuerpo para crear o renombrar una carpeta.
+type CarpetaUpsert struct {
+	Nombre string `json:"nombre"`
+}
+
+// GuardadoUpdate mueve un perfil guardado de carpeta o cambia su nota privada.
+// carpeta_id = 0 lo deja sin carpeta; los campos omitidos no cambian.
+type GuardadoUpdate struct {
+	CarpetaID *int    `json:"carpeta_id,omitempty"`
+	Nota      *string `json:"nota,omitempty"`
+}
+
+// PerfilGuardado es la tarjeta de un perfil guardado con la organización privada del tutor.
+type PerfilGuardado struct {
+	EstudiantePerfilCard
+	CarpetaID     *int       `json:"carpeta_id"`
+	CarpetaNombre string     `json:"carpeta_nombre,omitempty"`
+	Nota          string     `json:"nota"`
+	FechaGuardado *time.Time `json:"fecha_guardado,omitempty"`
+}
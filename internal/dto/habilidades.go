@@ -0,0 +1,23 @@
+package dto
+
+// Habilidad es una entrada del catálogo de habilidades con su nombre canónico.
+type Habilidad struct {
+	ID        int      `json:"id,omitempty"`
+	Nombre    string   `json:"nombre"`
+	Categoria string   `json:"categoria"`
+	Sinonimos []string `json:"sinonimos"`
+}
+
+// HabilidadUpsert define el cuerpo para crear o actualizar una habilidad del catálogo.
+type HabilidadUpsert struct {
+	Nombre    string   `json:"nombre"`
+	Categoria string   `json:"categoria"`
+	Sinonimos []string `json:"sinonimos"`
+}
+
+// HabilidadPerfil es una habilidad declarada por el estudiante, ya normalizada al catálogo.
+type HabilidadPerfil struct {
+	Nombre    string `json:"nombre"`
+	Categoria string `json:"categoria,omitempty"`
+	Nivel     string `json:"nivel,omitempty"`
+}
@@ -0,0 +1,125 @@
+package dto
+
+// Estados de envío externo de una notificación de la bandeja.
+const (
+	NotificacionEnvioEnviada   = "ENVIADA"
+	NotificacionEnvioFallida   = "FALLIDA"
+	NotificacionEnvioSinCanal  = "SIN_CANAL"
+	NotificacionEnvioPendiente = "PENDIENTE"
+	NotificacionEnvioResumen   = "EN_RESUMEN"
+	NotificacionEnvioOmitida   = "OMITIDA"
+)
+
+// Notificacion es la copia en la bandeja local de una notificación enviada a un tercero. Se
+// guarda aunque el envío externo falle o no esté configurado; EstadoEnvio indica qué pasó.
+type Notificacion struct {
+	ID            int         `json:"id"`
+	TerceroID     int         `json:"tercero_id"`
+	Asunto        string      `json:"asunto"`
+	Plantilla     string      `json:"plantilla"`
+	Cuerpo        string      `json:"cuerpo,omitempty"`
+	CuerpoHTML    string      `json:"cuerpo_html,omitempty"`
+	Locale        string      `json:"locale,omitempty"`
+	Datos         interface{} `json:"datos,omitempty"`
+	Leida         bool        `json:"leida"`
+	EstadoEnvio   string      `json:"estado_envio"`
+	FechaCreacion string      `json:"fecha_creacion"`
+	FechaLectura  string      `json:"fecha_lectura,omitempty"`
+}
+
+// NotificacionesNoLeidas es el contador de la bandeja.
+type NotificacionesNoLeidas struct {
+	NoLeidas int64 `json:"no_leidas"`
+}
+
+// NotificacionesMarcadas informa cuántas notificaciones se marcaron como leídas.
+type NotificacionesMarcadas struct {
+	Actualizadas int `json:"actualizadas"`
+}
+
+// Estados de un mensaje del outbox; MUERTA es la cola de mensajes que agotaron los reintentos.
+const (
+	OutboxPendiente = "PENDIENTE"
+	OutboxEnviada   = "ENVIADA"
+	OutboxMuerta    = "MUERTA"
+)
+
+// Canales de entrega externa: el servicio HTTP de notificaciones y el correo por SMTP.
+const (
+	OutboxCanalServicio = "SERVICIO"
+	OutboxCanalSMTP     = "SMTP"
+)
+
+// MensajeOutbox es una entrega pendiente de una notificación por un canal.
+type MensajeOutbox struct {
+	ID             int64        `json:"id"`
+	Canal          string       `json:"canal"`
+	Estado         string       `json:"estado"`
+	Intentos       int          `json:"intentos"`
+	ProximoIntento string       `json:"proximo_intento,omitempty"`
+	UltimoError    string       `json:"ultimo_error,omitempty"`
+	FechaCreacion  string       `json:"fecha_creacion"`
+	FechaEnvio     string       `json:"fecha_envio,omitempty"`
+	Notificacion   Notificacion `json:"notificacion"`
+}
+
+// PlantillaResumen describe una plantilla registrada en el MID.
+type PlantillaResumen struct {
+	ID          string   `json:"id"`
+	Descripcion string   `json:"descripcion"`
+	Locales     []string `json:"locales"`
+	Variables   []string `json:"variables"`
+}
+
+// PlantillaRenderizada es el asunto y el cuerpo (texto y HTML) de una plantilla con sus datos.
+type PlantillaRenderizada struct {
+	ID     string `json:"id"`
+	Locale string `json:"locale"`
+	Asunto string `json:"asunto"`
+	Texto  string `json:"texto"`
+	HTML   string `json:"html"`
+}
+
+// Frecuencias con que un tercero recibe un tipo de notificación por un canal.
+const (
+	FrecuenciaInstantanea = "INSTANTANEA"
+	FrecuenciaDiaria      = "DIARIA"
+	FrecuenciaSemanal     = "SEMANAL"
+	FrecuenciaDesactivada = "DESACTIVADA"
+)
+
+// Canales de las preferencias: la bandeja del sistema y el correo (entrega externa).
+const (
+	CanalApp   = "app"
+	CanalEmail = "email"
+)
+
+// PreferenciaCanales es la frecuencia de cada canal para un tipo de notificación.
+type PreferenciaCanales struct {
+	App   string `json:"app"`
+	Email string `json:"email"`
+}
+
+// PreferenciasNotificacion son las preferencias del tercero. Tipos usa como clave la plantilla;
+// los tipos que no aparecen siguen PorDefecto.
+type PreferenciasNotificacion struct {
+	TerceroID          int                           `json:"tercero_id"`
+	PorDefecto         PreferenciaCanales            `json:"por_defecto"`
+	Tipos              map[string]PreferenciaCanales `json:"tipos"`
+	FechaActualizacion string                        `json:"fecha_actualizacion,omitempty"`
+}
+
+// PreferenciasNotificacionUpdate reemplaza las preferencias; un canal vacío en un tipo toma el
+// valor de por_defecto.
+type PreferenciasNotificacionUpdate struct {
+	PorDefecto *PreferenciaCanales           `json:"por_defecto"`
+	Tipos      map[string]PreferenciaCanales `json:"tipos"`
+}
+
+// NotificacionDiferida es una notificación que espera el próximo resumen del canal.
+type NotificacionDiferida struct {
+	ID           int64        `json:"id"`
+	Canal        string       `json:"canal"`
+	Frecuencia   string       `json:"frecuencia"`
+	Notificacion Notificacion `json:"notificacion"`
+}
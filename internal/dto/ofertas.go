@@ -11,6 +11,44 @@ type OfertaCreateResp struct {
 	EmpresaTerceroID      int        `json:"empresa_tercero_id"`
 	TutorExternoID        int        `json:"tutor_externo_id"`
 	Modalidad             string     `json:"modalidad"`
+	Ciudad                string     `json:"ciudad,omitempty"`
+	Habilidades           []string   `json:"habilidades,omitempty"`
 	Estado                string     `json:"estado"`
 	ProyectosCurriculares []int      `json:"proyectos_curriculares"`
 }
+
+// RubricaCriterio es un criterio ponderado de evaluación de candidatos.
+type RubricaCriterio struct {
+	Codigo      string  `json:"codigo"`
+	Nombre      string  `json:"nombre"`
+	Descripcion string  `json:"descripcion,omitempty"`
+	Peso        float64 `json:"peso"`
+	EscalaMax   int     `json:"escala_max"`
+}
+
+// RubricaUpsert reemplaza los criterios de la rúbrica de una oferta.
+type RubricaUpsert struct {
+	Criterios []RubricaCriterio `json:"criterios"`
+}
+
+// PuntajeCriterio es el valor asignado a un criterio de la rúbrica.
+type PuntajeCriterio struct {
+	Criterio string  `json:"criterio"`
+	Valor    float64 `json:"valor"`
+}
+
+// EvaluacionPostulacionReq es el payload para calificar una postulación.
+type EvaluacionPostulacionReq struct {
+	Puntajes   []PuntajeCriterio `json:"puntajes"`
+	Comentario *string           `json:"comentario,omitempty"`
+}
+
+// EvaluacionPostulacion es la calificación registrada; Puntaje está normalizado de 0 a 100.
+type EvaluacionPostulacion struct {
+	PostulacionID int64             `json:"postulacion_id,omitempty"`
+	TutorID       int               `json:"tutor_id,omitempty"`
+	Puntaje       float64           `json:"puntaje"`
+	Puntajes      []PuntajeCriterio `json:"puntajes"`
+	Comentario    string            `json:"comentario,omitempty"`
+	Fecha         string            `json:"fecha,omitempty"`
+}
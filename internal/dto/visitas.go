@@ -0,0 +1,50 @@
+package dto
+
+// VisitaTutorResumen agrupa las visitas de un tutor al perfil dentro del rango consultado.
+type VisitaTutorResumen struct {
+	TutorID      int    `json:"tutor_id"`
+	Nombre       string `json:"nombre,omitempty"`
+	EmpresaID    int    `json:"empresa_id,omitempty"`
+	Empresa      string `json:"empresa,omitempty"`
+	Total        int    `json:"total"`
+	UltimaVisita string `json:"ultima_visita,omitempty"`
+	Invito       bool   `json:"invito"`
+}
+
+// VisitasSeriePunto es el conteo de visitas de un día o de una semana (que inicia el lunes).
+type VisitasSeriePunto struct {
+	Periodo string `json:"periodo"`
+	Visitas int    `json:"visitas"`
+	Tutores int    `json:"tutores"`
+}
+
+// VisitasEmpresa agrupa las visitas por la empresa activa del tutor; EmpresaID 0 son tutores sin empresa.
+type VisitasEmpresa struct {
+	EmpresaID int    `json:"empresa_id"`
+	Empresa   string `json:"empresa"`
+	Visitas   int    `json:"visitas"`
+	Tutores   int    `json:"tutores"`
+}
+
+// VisitasConversion relaciona los tutores que visitaron el perfil con los que luego enviaron una invitación.
+// Tasa es el porcentaje de tutores visitantes que invitaron.
+type VisitasConversion struct {
+	TutoresVisitantes int     `json:"tutores_visitantes"`
+	TutoresInvitaron  int     `json:"tutores_invitaron"`
+	Invitaciones      int     `json:"invitaciones"`
+	Tasa              float64 `json:"tasa"`
+}
+
+// VisitasPerfilAnalitica es la analítica de visitas al perfil del estudiante en un rango de fechas.
+// Las visitas repetidas de un tutor dentro de la ventana de deduplicación cuentan una sola vez.
+type VisitasPerfilAnalitica struct {
+	Desde        string               `json:"desde"`
+	Hasta        string               `json:"hasta"`
+	Granularidad string               `json:"granularidad"`
+	VentanaMin   int                  `json:"ventana_min"`
+	Total        int                  `json:"total"`
+	Items        []VisitaTutorResumen `json:"items"`
+	Serie        []VisitasSeriePunto  `json:"serie"`
+	PorEmpresa   []VisitasEmpresa     `json:"por_empresa"`
+	Conversion   VisitasConversion    `json:"conversion"`
+}
@@ -79,6 +79,51 @@ func RequireRole(ctx *context.Context, roles ...string) error {
 	return errors.New("insufficient roles")
 }
 
+// AsignacionAcademica retorna las facultades y proyectos curriculares asignados al usuario según
+// los claims facultad_id / facultades y proyecto_curricular_id / proyectos_curriculares. Acepta un
+// número, una lista o un texto separado por comas.
+func AsignacionAcademica(ctx *context.Context) (facultades, proyectos []int) {
+	claims, err := Claims(ctx)
+	if err != nil {
+		return nil, nil
+	}
+	facultades = append(parseIntsValue(claims["facultad_id"]), parseIntsValue(claims["facultades"])...)
+	proyectos = append(parseIntsValue(claims["proyecto_curricular_id"]), parseIntsValue(claims["proyectos_curriculares"])...)
+	return facultades, proyectos
+}
+
+func parseIntsValue(raw interface{}) []int {
+	var items []interface{}
+	switch v := raw.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		items = v
+	case string:
+		for _, part := range strings.Split(v, ",") {
+			items = append(items, part)
+		}
+	default:
+		items = []interface{}{v}
+	}
+	out := make([]int, 0, len(items))
+	for _, item := range items {
+		var n int64
+		switch x := item.(type) {
+		case float64:
+			n = int64(x)
+		case json.Number:
+			n, _ = x.Int64()
+		case string:
+			n, _ = json.Number(strings.TrimSpace(x)).Int64()
+		}
+		if n > 0 {
+			out = append(out, int(n))
+		}
+	}
+	return out
+}
+
 func getIntClaim(ctx *context.Context, key string) (int, error) {
 	claims, err := Claims(ctx)
 	if err != nil {
@@ -196,3 +241,27 @@ func parseRolesValue(raw interface{}) []string {
 		return nil
 	}
 }
+
+// RolesCoordinacion agrupa los roles con acceso de coordinación de pasantías.
+var RolesCoordinacion = []string{"COORDINADOR", "COORDINADOR_PASANTIAS"}
+
+// EsCoordinacion indica si el token del request trae algún rol de coordinación.
+func EsCoordinacion(ctx *context.Context) bool {
+	return RequireRole(ctx, RolesCoordinacion...) == nil
+}
+
+// RolesDecanatura agrupa los roles de decanos y asistentes de decanatura.
+var RolesDecanatura = []string{"DECANO", "ASISTENTE_DECANATURA"}
+
+// EsDecanatura indica si el token del request trae algún rol de decanatura.
+func EsDecanatura(ctx *context.Context) bool {
+	return RequireRole(ctx, RolesDecanatura...) == nil
+}
+
+// RolesAdministracion agrupa los roles de operación de la plataforma.
+var RolesAdministracion = []string{"ADMINISTRADOR", "ADMIN_PASANTIAS"}
+
+// EsAdministracion indica si el token del request trae algún rol de administración.
+func EsAdministracion(ctx *context.Context) bool {
+	return RequireRole(ctx, RolesAdministracion...) == nil
+}
@@ -0,0 +1,143 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	roothelpers "github.com/udistrital/pasantia_mid/helpers"
+)
+
+// Warning describe una sección de una respuesta agregada que no se pudo calcular. La respuesta se
+// entrega con el resto de secciones y la sección fallida queda en su valor por defecto.
+type Warning struct {
+	Seccion string `json:"seccion"`
+	Mensaje string `json:"mensaje"`
+}
+
+// FanOut ejecuta secciones independientes en paralelo con un límite de concurrencia y un timeout
+// por sección. Los errores, timeouts y panics no cancelan las demás secciones: quedan como Warning.
+type FanOut struct {
+	ctx     context.Context
+	timeout time.Duration
+	sem     chan struct{}
+	wg      sync.WaitGroup
+
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+// NewFanOut crea un ejecutor; limit <= 0 equivale a 1 y timeout <= 0 deja solo el plazo de ctx.
+func NewFanOut(ctx context.Context, limit int, timeout time.Duration) *FanOut {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if limit <= 0 {
+		limit = 1
+	}
+	return &FanOut{ctx: ctx, timeout: timeout, sem: make(chan struct{}, limit)}
+}
+
+// Future es el resultado de una sección; Value y OK solo son válidos después de Wait.
+type Future[T any] struct {
+	value T
+	ok    bool
+}
+
+// Value retorna el valor calculado o el valor cero de T si la sección falló.
+func (f *Future[T]) Value() T {
+	return f.value
+}
+
+// OK indica si la sección terminó sin error dentro de su timeout.
+func (f *Future[T]) OK() bool {
+	return f.ok
+}
+
+// Submit agenda fn como la sección indicada. Si fn no termina a tiempo su resultado se descarta
+// aunque siga corriendo, de modo que nunca escribe sobre la respuesta ya entregada.
+func Submit[T any](f *FanOut, seccion string, fn func(ctx context.Context) (T, error)) *Future[T] {
+	future := &Future[T]{}
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		select {
+		case f.sem <- struct{}{}:
+		case <-f.ctx.Done():
+			f.Warn(seccion, f.ctx.Err())
+			return
+		}
+		defer func() { <-f.sem }()
+
+		ctx, cancel := f.ctx, context.CancelFunc(func() {})
+		if f.timeout > 0 {
+			ctx, cancel = context.WithTimeout(f.ctx, f.timeout)
+		}
+		defer cancel()
+
+		type outcome struct {
+			value T
+			err   error
+		}
+		done := make(chan outcome, 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					done <- outcome{err: fmt.Errorf("panic: %v", r)}
+				}
+			}()
+			v, err := fn(ctx)
+			done <- outcome{value: v, err: err}
+		}()
+
+		select {
+		case out := <-done:
+			if out.err != nil {
+				f.Warn(seccion, out.err)
+				return
+			}
+			future.value, future.ok = out.value, true
+		case <-ctx.Done():
+			f.Warn(seccion, ctx.Err())
+		}
+	}()
+	return future
+}
+
+// Warn registra una advertencia para la sección; útil para fallos detectados fuera de Submit.
+func (f *FanOut) Warn(seccion string, err error) {
+	if err == nil {
+		return
+	}
+	mensaje := "error consultando la sección"
+	var appErr *roothelpers.AppError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		mensaje = "tiempo de espera agotado"
+	case errors.Is(err, context.Canceled):
+		mensaje = "consulta cancelada"
+	case errors.As(err, &appErr) && appErr.Message != "":
+		mensaje = appErr.Message
+	}
+	f.mu.Lock()
+	f.warnings = append(f.warnings, Warning{Seccion: seccion, Mensaje: mensaje})
+	f.mu.Unlock()
+}
+
+// Wait espera todas las secciones agendadas y retorna las advertencias acumuladas ordenadas por
+// sección (nunca nil). Se puede llamar varias veces para ejecutar por fases.
+func (f *FanOut) Wait() []Warning {
+	f.wg.Wait()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]Warning, len(f.warnings))
+	copy(out, f.warnings)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Seccion < out[j].Seccion
+	})
+	return out
+}
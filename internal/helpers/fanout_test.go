@@ -0,0 +1,138 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	roothelpers "github.com/udistrital/pasantia_mid/helpers"
+)
+
+func TestFanOutSecciones(t *testing.T) {
+	tests := []struct {
+		nombre      string
+		fn          func(ctx context.Context) (int, error)
+		wantOK      bool
+		wantValor   int
+		wantMensaje string
+	}{
+		{
+			nombre: "sección correcta",
+			fn:     func(context.Context) (int, error) { return 7, nil },
+			wantOK: true, wantValor: 7,
+		},
+		{
+			nombre:      "error genérico",
+			fn:          func(context.Context) (int, error) { return 7, errors.New("boom") },
+			wantMensaje: "error consultando la sección",
+		},
+		{
+			nombre: "error de la aplicación conserva su mensaje",
+			fn: func(context.Context) (int, error) {
+				return 0, roothelpers.NewAppError(http.StatusBadGateway, "error consultando ofertas", nil)
+			},
+			wantMensaje: "error consultando ofertas",
+		},
+		{
+			nombre:      "panic",
+			fn:          func(context.Context) (int, error) { panic("nil map") },
+			wantMensaje: "error consultando la sección",
+		},
+		{
+			nombre: "timeout descarta el resultado tardío",
+			fn: func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond)
+				return 7, nil
+			},
+			wantMensaje: "tiempo de espera agotado",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			fan := NewFanOut(context.Background(), 2, 20*time.Millisecond)
+			f := Submit(fan, "seccion", tt.fn)
+			warnings := fan.Wait()
+
+			if f.OK() != tt.wantOK || f.Value() != tt.wantValor {
+				t.Errorf("OK = %v, Value = %d; se esperaba %v, %d", f.OK(), f.Value(), tt.wantOK, tt.wantValor)
+			}
+			if tt.wantMensaje == "" {
+				if len(warnings) != 0 {
+					t.Errorf("advertencias inesperadas: %v", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || warnings[0].Seccion != "seccion" || warnings[0].Mensaje != tt.wantMensaje {
+				t.Errorf("advertencias = %v, se esperaba %q", warnings, tt.wantMensaje)
+			}
+		})
+	}
+}
+
+func TestFanOutLimiteDeConcurrencia(t *testing.T) {
+	const limite = 3
+	fan := NewFanOut(context.Background(), limite, 0)
+	var activas, maximo int32
+	futures := make([]*Future[int], 0, 10)
+	for i := 0; i < 10; i++ {
+		i := i
+		futures = append(futures, Submit(fan, "s", func(context.Context) (int, error) {
+			n := atomic.AddInt32(&activas, 1)
+			for {
+				m := atomic.LoadInt32(&maximo)
+				if n <= m || atomic.CompareAndSwapInt32(&maximo, m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&activas, -1)
+			return i, nil
+		}))
+	}
+	if warnings := fan.Wait(); len(warnings) != 0 {
+		t.Fatalf("advertencias inesperadas: %v", warnings)
+	}
+	if maximo > limite {
+		t.Errorf("%d secciones simultáneas, el límite es %d", maximo, limite)
+	}
+	for i, f := range futures {
+		if !f.OK() || f.Value() != i {
+			t.Errorf("sección %d: OK = %v, Value = %d", i, f.OK(), f.Value())
+		}
+	}
+}
+
+func TestFanOutContextoCancelado(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fan := NewFanOut(ctx, 0, 0)
+	f := Submit(fan, "b", func(context.Context) (int, error) { return 1, nil })
+	fan.Warn("a", nil)
+	warnings := fan.Wait()
+
+	if f.OK() {
+		t.Error("la sección corrió con el contexto cancelado")
+	}
+	if len(warnings) != 1 || warnings[0].Seccion != "b" || warnings[0].Mensaje != "consulta cancelada" {
+		t.Errorf("advertencias = %v", warnings)
+	}
+}
+
+func TestFanOutWaitOrdenaPorSeccion(t *testing.T) {
+	fan := NewFanOut(context.Background(), 4, 0)
+	for _, s := range []string{"ofertas", "agenda", "metricas"} {
+		Submit(fan, s, func(context.Context) (struct{}, error) { return struct{}{}, errors.New("x") })
+	}
+	warnings := fan.Wait()
+	got := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		got = append(got, w.Seccion)
+	}
+	if len(got) != 3 || got[0] != "agenda" || got[1] != "metricas" || got[2] != "ofertas" {
+		t.Errorf("secciones = %v", got)
+	}
+}
@@ -0,0 +1,92 @@
+package helpers
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const icalTimeLayout = "20060102T150405Z"
+
+// ICalEvento describe un evento para exportar en formato iCalendar (RFC 5545).
+type ICalEvento struct {
+	UID         string
+	Resumen     string
+	Descripcion string
+	Lugar       string
+	URL         string
+	Inicio      time.Time
+	Fin         time.Time
+	Cancelado   bool
+	Secuencia   int
+	Organizador string
+}
+
+// BuildICS genera un calendario .ics con un único evento.
+func BuildICS(ev ICalEvento) []byte {
+	var buf bytes.Buffer
+	writeICSLine(&buf, "BEGIN:VCALENDAR")
+	writeICSLine(&buf, "VERSION:2.0")
+	writeICSLine(&buf, "PRODID:-//Universidad Distrital//pasantias_mid//ES")
+	writeICSLine(&buf, "CALSCALE:GREGORIAN")
+	if ev.Cancelado {
+		writeICSLine(&buf, "METHOD:CANCEL")
+	} else {
+		writeICSLine(&buf, "METHOD:PUBLISH")
+	}
+	writeICSLine(&buf, "BEGIN:VEVENT")
+	writeICSLine(&buf, "UID:"+escapeICSText(ev.UID))
+	writeICSLine(&buf, "DTSTAMP:"+time.Now().UTC().Format(icalTimeLayout))
+	writeICSLine(&buf, "DTSTART:"+ev.Inicio.UTC().Format(icalTimeLayout))
+	writeICSLine(&buf, "DTEND:"+ev.Fin.UTC().Format(icalTimeLayout))
+	writeICSLine(&buf, "SEQUENCE:"+strconv.Itoa(ev.Secuencia))
+	writeICSLine(&buf, "SUMMARY:"+escapeICSText(ev.Resumen))
+	if ev.Descripcion != "" {
+		writeICSLine(&buf, "DESCRIPTION:"+escapeICSText(ev.Descripcion))
+	}
+	if ev.Lugar != "" {
+		writeICSLine(&buf, "LOCATION:"+escapeICSText(ev.Lugar))
+	}
+	if ev.URL != "" {
+		writeICSLine(&buf, "URL:"+ev.URL)
+	}
+	if ev.Organizador != "" {
+		writeICSLine(&buf, "ORGANIZER;CN="+escapeICSParam(ev.Organizador)+":noreply@udistrital.edu.co")
+	}
+	if ev.Cancelado {
+		writeICSLine(&buf, "STATUS:CANCELLED")
+	} else {
+		writeICSLine(&buf, "STATUS:CONFIRMED")
+	}
+	writeICSLine(&buf, "END:VEVENT")
+	writeICSLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// writeICSLine escribe la línea plegándola a 75 octetos como exige el RFC.
+func writeICSLine(buf *bytes.Buffer, line string) {
+	const limit = 75
+	for len(line) > limit {
+		cut := limit
+		// No partir caracteres UTF-8 multibyte.
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+func escapeICSText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(strings.TrimSpace(s))
+}
+
+func escapeICSParam(s string) string {
+	s = strings.NewReplacer(`"`, "", "\n", " ", "\r", "").Replace(strings.TrimSpace(s))
+	return `"` + s + `"`
+}
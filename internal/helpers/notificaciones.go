@@ -5,10 +5,13 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	roothelpers "github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
 	rootservices "github.com/udistrital/pasantia_mid/services"
 
+	"github.com/beego/beego/v2/core/logs"
 	beego "github.com/beego/beego/v2/server/web"
 	"github.com/beego/beego/v2/server/web/context"
 )
@@ -18,20 +21,278 @@ type notificacionesClient struct{}
 // Notificaciones expone el wrapper al servicio de notificaciones.
 var Notificaciones = notificacionesClient{}
 
+// NotificacionStore persiste la bandeja local de notificaciones por tercero. La implementación
+// se registra al arrancar con SetNotificacionStore.
+type NotificacionStore interface {
+	Crear(n *internaldto.Notificacion) error
+	Listar(terceroID int, soloNoLeidas bool, page, size int) ([]internaldto.Notificacion, int64, error)
+	ContarNoLeidas(terceroID int) (int64, error)
+	MarcarLeida(terceroID, id int) (*internaldto.Notificacion, error)
+	MarcarTodasLeidas(terceroID int) (int, error)
+	ActualizarEnvio(terceroID, id int, estado string) error
+}
+
+// NotificacionOutbox es la cola de entregas pendientes. Send registra ahí la intención de envío y
+// un worker la entrega con reintentos; la implementación se registra con SetNotificacionOutbox.
+type NotificacionOutbox interface {
+	Encolar(m *internaldto.MensajeOutbox) error
+	Obtener(id int64) (*internaldto.MensajeOutbox, error)
+	Actualizar(m internaldto.MensajeOutbox) error
+	// Vencidos retorna los pendientes cuyo próximo intento ya llegó, los más antiguos primero.
+	Vencidos(ahora time.Time, limite int) ([]internaldto.MensajeOutbox, error)
+	Listar(estado string) ([]internaldto.MensajeOutbox, error)
+	// Purgar elimina los enviados antes de la fecha indicada.
+	Purgar(antesDe time.Time) (int, error)
+}
+
+// NotificacionPreferencias resuelve con qué frecuencia recibe el tercero cada tipo de notificación
+// por canal y guarda las que esperan un resumen. Se registra con SetNotificacionPreferencias; sin
+// ella todo se entrega al instante.
+type NotificacionPreferencias interface {
+	Resolver(terceroID int, tipo string) internaldto.PreferenciaCanales
+	Diferir(d *internaldto.NotificacionDiferida) error
+}
+
+// CanalNotificacion entrega la notificación fuera del MID: el servicio HTTP de notificaciones,
+// SMTP, etc. El canal activo se registra al arrancar con SetCanalNotificacion.
+type CanalNotificacion interface {
+	// Nombre identifica el canal en los mensajes del outbox.
+	Nombre() string
+	Entregar(ctx *context.Context, n internaldto.Notificacion) error
+}
+
 var (
 	notificacionesBaseOnce sync.Once
 	notificacionesBase     string
+
+	notificacionStoreMu sync.RWMutex
+	notificacionStore   NotificacionStore
+	notificacionOutbox  NotificacionOutbox
+	notificacionPrefs   NotificacionPreferencias
+	canalNotificacion   CanalNotificacion
+	canalRegistrado     bool
 )
 
-// Send dispara una notificación hacia un tercero.
-func (notificacionesClient) Send(ctx *context.Context, toTerceroID int, asunto, plantilla string, data interface{}) error {
-	base := notificacionesBaseURL()
-	if base == "" {
+// SetNotificacionStore registra el almacenamiento de la bandeja; nil la desactiva.
+func SetNotificacionStore(store NotificacionStore) {
+	notificacionStoreMu.Lock()
+	notificacionStore = store
+	notificacionStoreMu.Unlock()
+}
+
+// GetNotificacionStore retorna el almacenamiento registrado o nil.
+func GetNotificacionStore() NotificacionStore {
+	notificacionStoreMu.RLock()
+	defer notificacionStoreMu.RUnlock()
+	return notificacionStore
+}
+
+// SetNotificacionOutbox registra la cola de entregas; con nil Send entrega en línea.
+func SetNotificacionOutbox(outbox NotificacionOutbox) {
+	notificacionStoreMu.Lock()
+	notificacionOutbox = outbox
+	notificacionStoreMu.Unlock()
+}
+
+// GetNotificacionOutbox retorna la cola de entregas registrada o nil.
+func GetNotificacionOutbox() NotificacionOutbox {
+	notificacionStoreMu.RLock()
+	defer notificacionStoreMu.RUnlock()
+	return notificacionOutbox
+}
+
+// SetNotificacionPreferencias registra las preferencias por tercero; nil entrega todo al instante.
+func SetNotificacionPreferencias(prefs NotificacionPreferencias) {
+	notificacionStoreMu.Lock()
+	notificacionPrefs = prefs
+	notificacionStoreMu.Unlock()
+}
+
+// GetNotificacionPreferencias retorna las preferencias registradas o nil.
+func GetNotificacionPreferencias() NotificacionPreferencias {
+	notificacionStoreMu.RLock()
+	defer notificacionStoreMu.RUnlock()
+	return notificacionPrefs
+}
+
+// SetCanalNotificacion registra el canal externo; nil deja las notificaciones solo en la bandeja.
+func SetCanalNotificacion(canal CanalNotificacion) {
+	notificacionStoreMu.Lock()
+	canalNotificacion = canal
+	canalRegistrado = true
+	notificacionStoreMu.Unlock()
+}
+
+// CanalNotificacionActivo retorna el canal registrado o, si no se registró ninguno, el servicio
+// HTTP cuando hay notificaciones_base_url. Retorna nil si no hay canal.
+func CanalNotificacionActivo() CanalNotificacion {
+	notificacionStoreMu.RLock()
+	canal, registrado := canalNotificacion, canalRegistrado
+	notificacionStoreMu.RUnlock()
+	if registrado {
+		return canal
+	}
+	return CanalServicioNotificaciones()
+}
+
+// CanalNotificacionPorNombre retorna el canal con que se registró un mensaje del outbox: el
+// activo si coincide o, para SERVICIO, el servicio HTTP mientras siga configurado.
+func CanalNotificacionPorNombre(nombre string) CanalNotificacion {
+	if nombre == "" {
+		nombre = internaldto.OutboxCanalServicio
+	}
+	if canal := CanalNotificacionActivo(); canal != nil && canal.Nombre() == nombre {
+		return canal
+	}
+	if nombre == internaldto.OutboxCanalServicio {
+		return CanalServicioNotificaciones()
+	}
+	return nil
+}
+
+// CanalServicioNotificaciones retorna el canal del servicio HTTP de notificaciones o nil si no
+// hay notificaciones_base_url.
+func CanalServicioNotificaciones() CanalNotificacion {
+	if notificacionesBaseURL() == "" {
 		return nil
 	}
+	return canalServicio{}
+}
+
+// Send deja la notificación en la bandeja del tercero y la entrega por el canal externo, según
+// las preferencias del tercero para la plantilla: al instante, en el resumen diario o semanal, o
+// nunca. Con un outbox registrado la entrega queda encolada y Send solo falla si no se pudo
+// registrar; sin outbox se entrega en línea y se retorna el error del envío o, si este funcionó,
+// el de la bandeja.
+func (c notificacionesClient) Send(ctx *context.Context, toTerceroID int, asunto, plantilla string, data interface{}) error {
 	if toTerceroID <= 0 {
 		return roothelpers.NewAppError(http.StatusBadRequest, "tercero destino inválido", nil)
 	}
+	canales := internaldto.PreferenciaCanales{App: internaldto.FrecuenciaInstantanea, Email: internaldto.FrecuenciaInstantanea}
+	if prefs := GetNotificacionPreferencias(); prefs != nil {
+		canales = prefs.Resolver(toTerceroID, strings.TrimSpace(plantilla))
+	}
+	return c.enviar(ctx, nuevaNotificacion(toTerceroID, asunto, plantilla, data), canales)
+}
+
+// EnviarResumen entrega un resumen por un solo canal, sin consultar las preferencias.
+func (c notificacionesClient) EnviarResumen(toTerceroID int, canal, asunto string, data interface{}) error {
+	canales := internaldto.PreferenciaCanales{App: internaldto.FrecuenciaDesactivada, Email: internaldto.FrecuenciaDesactivada}
+	if canal == internaldto.CanalApp {
+		canales.App = internaldto.FrecuenciaInstantanea
+	} else {
+		canales.Email = internaldto.FrecuenciaInstantanea
+	}
+	return c.enviar(nil, nuevaNotificacion(toTerceroID, asunto, PlantillaResumenNotificaciones, data), canales)
+}
+
+func nuevaNotificacion(toTerceroID int, asunto, plantilla string, data interface{}) internaldto.Notificacion {
+	n := internaldto.Notificacion{
+		TerceroID:     toTerceroID,
+		Asunto:        strings.TrimSpace(asunto),
+		Plantilla:     strings.TrimSpace(plantilla),
+		Datos:         data,
+		EstadoEnvio:   internaldto.NotificacionEnvioEnviada,
+		FechaCreacion: time.Now().UTC().Format(time.RFC3339),
+	}
+	aplicarPlantilla(&n)
+	return n
+}
+
+func (c notificacionesClient) enviar(ctx *context.Context, n internaldto.Notificacion, canales internaldto.PreferenciaCanales) error {
+	canal := CanalNotificacionActivo()
+	outbox := GetNotificacionOutbox()
+
+	var errEnvio error
+	encolar := false
+	switch {
+	case canales.Email == internaldto.FrecuenciaDesactivada:
+		n.EstadoEnvio = internaldto.NotificacionEnvioOmitida
+	case canal == nil:
+		n.EstadoEnvio = internaldto.NotificacionEnvioSinCanal
+	case esFrecuenciaResumen(canales.Email):
+		n.EstadoEnvio = internaldto.NotificacionEnvioResumen
+	case outbox != nil:
+		n.EstadoEnvio = internaldto.NotificacionEnvioPendiente
+		encolar = true
+	default:
+		if errEnvio = canal.Entregar(ctx, n); errEnvio != nil {
+			n.EstadoEnvio = internaldto.NotificacionEnvioFallida
+		}
+	}
+
+	var errBandeja error
+	switch {
+	case canales.App == internaldto.FrecuenciaInstantanea:
+		if store := GetNotificacionStore(); store != nil {
+			if err := store.Crear(&n); err != nil {
+				logs.Warn("bandeja de notificaciones, tercero", n.TerceroID, ":", err)
+				errBandeja = roothelpers.AsAppError(err, "error guardando notificación")
+			}
+		}
+	case esFrecuenciaResumen(canales.App):
+		errBandeja = diferirNotificacion(n, internaldto.CanalApp, canales.App)
+	}
+
+	if n.EstadoEnvio == internaldto.NotificacionEnvioResumen {
+		if err := diferirNotificacion(n, internaldto.CanalEmail, canales.Email); err != nil {
+			return err
+		}
+	}
+	if encolar {
+		m := &internaldto.MensajeOutbox{
+			Canal:          canal.Nombre(),
+			Estado:         internaldto.OutboxPendiente,
+			ProximoIntento: n.FechaCreacion,
+			FechaCreacion:  n.FechaCreacion,
+			Notificacion:   n,
+		}
+		if err := outbox.Encolar(m); err != nil {
+			logs.Error("outbox de notificaciones, tercero", n.TerceroID, ":", err)
+			// Sin mensaje en el outbox nadie sacaría la notificación de PENDIENTE.
+			if store := GetNotificacionStore(); store != nil && n.ID > 0 {
+				if errEstado := store.ActualizarEnvio(n.TerceroID, n.ID, internaldto.NotificacionEnvioFallida); errEstado != nil {
+					logs.Warn("bandeja de notificaciones, tercero", n.TerceroID, ":", errEstado)
+				}
+			}
+			return roothelpers.AsAppError(err, "error registrando notificación")
+		}
+		return nil
+	}
+	if errEnvio != nil {
+		return errEnvio
+	}
+	return errBandeja
+}
+
+// diferirNotificacion guarda la notificación para el próximo resumen del canal.
+func diferirNotificacion(n internaldto.Notificacion, canal, frecuencia string) error {
+	prefs := GetNotificacionPreferencias()
+	if prefs == nil {
+		return roothelpers.NewAppError(http.StatusServiceUnavailable, "resúmenes de notificaciones no disponibles", nil)
+	}
+	d := &internaldto.NotificacionDiferida{Canal: canal, Frecuencia: frecuencia, Notificacion: n}
+	if err := prefs.Diferir(d); err != nil {
+		logs.Error("resumen de notificaciones, tercero", n.TerceroID, ":", err)
+		return roothelpers.AsAppError(err, "error registrando notificación")
+	}
+	return nil
+}
+
+func esFrecuenciaResumen(frecuencia string) bool {
+	return frecuencia == internaldto.FrecuenciaDiaria || frecuencia == internaldto.FrecuenciaSemanal
+}
+
+// canalServicio entrega por el servicio HTTP de notificaciones (notificaciones_base_url).
+type canalServicio struct{}
+
+func (canalServicio) Nombre() string { return internaldto.OutboxCanalServicio }
+
+func (canalServicio) Entregar(ctx *context.Context, n internaldto.Notificacion) error {
+	base := notificacionesBaseURL()
+	if base == "" {
+		return roothelpers.NewAppError(http.StatusServiceUnavailable, "servicio de notificaciones no configurado", nil)
+	}
 
 	headers := copyRequestHeaders(ctx)
 	if _, ok := headers["Authorization"]; !ok {
@@ -39,10 +300,13 @@ func (notificacionesClient) Send(ctx *context.Context, toTerceroID int, asunto,
 	}
 
 	body := map[string]interface{}{
-		"TerceroId": toTerceroID,
-		"Asunto":    strings.TrimSpace(asunto),
-		"Plantilla": strings.TrimSpace(plantilla),
-		"Datos":     data,
+		"TerceroId":  n.TerceroID,
+		"Asunto":     n.Asunto,
+		"Plantilla":  n.Plantilla,
+		"Datos":      n.Datos,
+		"Cuerpo":     n.Cuerpo,
+		"CuerpoHtml": n.CuerpoHTML,
+		"Locale":     n.Locale,
 	}
 
 	cfg := rootservices.GetConfig()
@@ -54,6 +318,24 @@ func (notificacionesClient) Send(ctx *context.Context, toTerceroID int, asunto,
 	return nil
 }
 
+// aplicarPlantilla renderiza asunto y cuerpo cuando la plantilla está registrada en el MID. Si
+// falla el render se conserva el asunto recibido y el canal externo resuelve la plantilla.
+func aplicarPlantilla(n *internaldto.Notificacion) {
+	if _, ok := ObtenerPlantilla(n.Plantilla); !ok {
+		return
+	}
+	locale := LocaleNotificacion(n.Datos)
+	r, err := RenderPlantilla(n.Plantilla, locale, n.Datos)
+	if err != nil {
+		logs.Warn("plantilla de notificación", n.Plantilla, ":", err)
+		return
+	}
+	n.Asunto = r.Asunto
+	n.Cuerpo = r.Texto
+	n.CuerpoHTML = r.HTML
+	n.Locale = r.Locale
+}
+
 func notificacionesBaseURL() string {
 	notificacionesBaseOnce.Do(func() {
 		if v := strings.TrimSpace(os.Getenv("NOTIFICACIONES_BASE_URL")); v != "" {
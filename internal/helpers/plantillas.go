@@ -0,0 +1,456 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	beego "github.com/beego/beego/v2/server/web"
+
+	roothelpers "github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+)
+
+// Plantillas de notificación definidas en el MID.
+const (
+	PlantillaInvitacionRecibida      = "invitacion_recibida"
+	PlantillaPostulacionEstado       = "postulacion_estado"
+	PlantillaPostulacionSeleccionada = "postulacion_seleccionada"
+	PlantillaEntrevistaProgramada    = "entrevista_programada"
+	PlantillaOfertaCancelada         = "oferta_cancelada"
+	PlantillaResumenNotificaciones   = "resumen_notificaciones"
+
+	LocaleES      = "es"
+	LocaleEN      = "en"
+	localeDefault = LocaleES
+)
+
+// zonaNotificaciones es la hora de Colombia (UTC-5, sin horario de verano) para las fechas.
+var zonaNotificaciones = time.FixedZone("COT", -5*60*60)
+
+// PlantillaNotificacion define asunto y cuerpo por idioma. Asunto y Texto usan text/template y
+// HTML usa html/template; los datos se acceden por clave (.oferta_titulo). Ejemplo son los datos
+// con que se previsualiza.
+type PlantillaNotificacion struct {
+	ID          string
+	Descripcion string
+	Variables   []string
+	Ejemplo     map[string]interface{}
+	Variantes   map[string]VariantePlantilla
+}
+
+// VariantePlantilla es la versión de una plantilla en un idioma.
+type VariantePlantilla struct {
+	Asunto string
+	Texto  string
+	HTML   string
+}
+
+type varianteCompilada struct {
+	asunto *texttemplate.Template
+	texto  *texttemplate.Template
+	html   *htmltemplate.Template
+}
+
+var funcionesPlantilla = map[string]interface{}{
+	"fecha": formatearFechaPlantilla,
+}
+
+// plantillasCompiladas se arma al iniciar: una plantilla mal escrita detiene el arranque.
+var plantillasCompiladas = compilarPlantillas(plantillasNotificacion)
+
+func compilarPlantillas(defs map[string]PlantillaNotificacion) map[string]map[string]varianteCompilada {
+	out := make(map[string]map[string]varianteCompilada, len(defs))
+	for id, def := range defs {
+		out[id] = make(map[string]varianteCompilada, len(def.Variantes))
+		for locale, v := range def.Variantes {
+			nombre := id + "." + locale
+			out[id][locale] = varianteCompilada{
+				asunto: texttemplate.Must(texttemplate.New(nombre + ".asunto").Funcs(funcionesPlantilla).Parse(v.Asunto)),
+				texto:  texttemplate.Must(texttemplate.New(nombre + ".texto").Funcs(funcionesPlantilla).Parse(v.Texto)),
+				html:   htmltemplate.Must(htmltemplate.New(nombre + ".html").Funcs(funcionesPlantilla).Parse(v.HTML)),
+			}
+		}
+	}
+	return out
+}
+
+// RenderPlantilla aplica los datos a la plantilla en el idioma pedido; si la plantilla no tiene
+// ese idioma se usa español.
+func RenderPlantilla(id, locale string, datos interface{}) (internaldto.PlantillaRenderizada, error) {
+	variantes, ok := plantillasCompiladas[id]
+	if !ok {
+		return internaldto.PlantillaRenderizada{}, roothelpers.NewAppError(http.StatusNotFound, "plantilla no encontrada", nil)
+	}
+	locale = normalizarLocale(locale)
+	v, ok := variantes[locale]
+	if !ok {
+		locale = localeDefault
+		v = variantes[locale]
+	}
+
+	out := internaldto.PlantillaRenderizada{ID: id, Locale: locale}
+	var buf bytes.Buffer
+	if err := v.asunto.Execute(&buf, datos); err != nil {
+		return out, fmt.Errorf("plantilla %s (%s), asunto: %w", id, locale, err)
+	}
+	out.Asunto = strings.Join(strings.Fields(buf.String()), " ")
+	buf.Reset()
+	if err := v.texto.Execute(&buf, datos); err != nil {
+		return out, fmt.Errorf("plantilla %s (%s), texto: %w", id, locale, err)
+	}
+	out.Texto = strings.TrimSpace(buf.String())
+	buf.Reset()
+	if err := v.html.Execute(&buf, datos); err != nil {
+		return out, fmt.Errorf("plantilla %s (%s), html: %w", id, locale, err)
+	}
+	out.HTML = strings.TrimSpace(buf.String())
+	return out, nil
+}
+
+// ObtenerPlantilla retorna la definición de la plantilla.
+func ObtenerPlantilla(id string) (PlantillaNotificacion, bool) {
+	p, ok := plantillasNotificacion[id]
+	return p, ok
+}
+
+// ListarPlantillas describe las plantillas registradas, ordenadas por id.
+func ListarPlantillas() []internaldto.PlantillaResumen {
+	out := make([]internaldto.PlantillaResumen, 0, len(plantillasNotificacion))
+	for id, p := range plantillasNotificacion {
+		locales := make([]string, 0, len(p.Variantes))
+		for l := range p.Variantes {
+			locales = append(locales, l)
+		}
+		sort.Strings(locales)
+		out = append(out, internaldto.PlantillaResumen{ID: id, Descripcion: p.Descripcion, Locales: locales, Variables: p.Variables})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// LocaleNotificacion toma el idioma de la clave "locale" de los datos o, en su defecto, de
+// notificaciones_locale.
+func LocaleNotificacion(datos interface{}) string {
+	if m, ok := datos.(map[string]interface{}); ok {
+		if l, ok := m["locale"].(string); ok && strings.TrimSpace(l) != "" {
+			return normalizarLocale(l)
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("NOTIFICACIONES_LOCALE")); v != "" {
+		return normalizarLocale(v)
+	}
+	if v, err := beego.AppConfig.String("notificaciones_locale"); err == nil && strings.TrimSpace(v) != "" {
+		return normalizarLocale(v)
+	}
+	return localeDefault
+}
+
+// normalizarLocale reduce "en-US" o "EN" a "en".
+func normalizarLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	if locale == "" {
+		return localeDefault
+	}
+	return locale
+}
+
+// formatearFechaPlantilla muestra una fecha RFC3339 en hora de Colombia; otros valores se
+// muestran tal cual.
+func formatearFechaPlantilla(v interface{}) string {
+	var t time.Time
+	switch x := v.(type) {
+	case time.Time:
+		t = x
+	case string:
+		parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(x))
+		if err != nil {
+			return x
+		}
+		t = parsed
+	default:
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprint(v)
+	}
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(zonaNotificaciones).Format("02/01/2006 15:04")
+}
+
+var plantillasNotificacion = map[string]PlantillaNotificacion{
+	PlantillaInvitacionRecibida: {
+		ID:          PlantillaInvitacionRecibida,
+		Descripcion: "Un tutor invitó al estudiante a postular a una oferta",
+		Variables:   []string{"nombre", "oferta_titulo", "empresa", "mensaje", "invitacion_id"},
+		Ejemplo: map[string]interface{}{
+			"nombre":        "Laura Gómez",
+			"oferta_titulo": "Practicante de analítica de datos",
+			"empresa":       "Acme SAS",
+			"mensaje":       "Tu perfil encaja con lo que buscamos.",
+			"invitacion_id": 5,
+		},
+		Variantes: map[string]VariantePlantilla{
+			LocaleES: {
+				Asunto: `Te invitaron a postular: {{.oferta_titulo}}`,
+				Texto: `Hola{{with .nombre}} {{.}}{{end}},
+
+{{with .empresa}}{{.}}{{else}}Un tutor{{end}} te invitó a postular a la oferta "{{.oferta_titulo}}".
+{{with .mensaje}}
+Mensaje del tutor:
+{{.}}
+{{end}}
+Revisa la invitación en tu bandeja del sistema de pasantías para aceptarla o rechazarla.`,
+				HTML: `<p>Hola{{with .nombre}} {{.}}{{end}},</p>
+<p>{{with .empresa}}{{.}}{{else}}Un tutor{{end}} te invitó a postular a la oferta <strong>{{.oferta_titulo}}</strong>.</p>
+{{with .mensaje}}<blockquote>{{.}}</blockquote>{{end}}
+<p>Revisa la invitación en tu bandeja del sistema de pasantías para aceptarla o rechazarla.</p>`,
+			},
+			LocaleEN: {
+				Asunto: `You have been invited to apply: {{.oferta_titulo}}`,
+				Texto: `Hello{{with .nombre}} {{.}}{{end}},
+
+{{with .empresa}}{{.}}{{else}}A tutor{{end}} invited you to apply to the internship "{{.oferta_titulo}}".
+{{with .mensaje}}
+Message from the tutor:
+{{.}}
+{{end}}
+Check the invitation in your internship inbox to accept or decline it.`,
+				HTML: `<p>Hello{{with .nombre}} {{.}}{{end}},</p>
+<p>{{with .empresa}}{{.}}{{else}}A tutor{{end}} invited you to apply to the internship <strong>{{.oferta_titulo}}</strong>.</p>
+{{with .mensaje}}<blockquote>{{.}}</blockquote>{{end}}
+<p>Check the invitation in your internship inbox to accept or decline it.</p>`,
+			},
+		},
+	},
+	PlantillaPostulacionEstado: {
+		ID:          PlantillaPostulacionEstado,
+		Descripcion: "La postulación del estudiante cambió de estado",
+		Variables:   []string{"nombre", "oferta_titulo", "estado", "estado_nombre", "postulacion_id"},
+		Ejemplo: map[string]interface{}{
+			"nombre":         "Laura Gómez",
+			"oferta_titulo":  "Practicante de analítica de datos",
+			"estado":         "PSRV_CTR",
+			"estado_nombre":  "En revisión",
+			"postulacion_id": 42,
+		},
+		Variantes: map[string]VariantePlantilla{
+			LocaleES: {
+				Asunto: `Tu postulación a {{.oferta_titulo}} cambió de estado`,
+				Texto: `Hola{{with .nombre}} {{.}}{{end}},
+
+Tu postulación a la oferta "{{.oferta_titulo}}" ahora está en estado: {{with .estado_nombre}}{{.}}{{else}}{{.estado}}{{end}}.
+
+Puedes ver el detalle en el sistema de pasantías.`,
+				HTML: `<p>Hola{{with .nombre}} {{.}}{{end}},</p>
+<p>Tu postulación a la oferta <strong>{{.oferta_titulo}}</strong> ahora está en estado: <strong>{{with .estado_nombre}}{{.}}{{else}}{{.estado}}{{end}}</strong>.</p>
+<p>Puedes ver el detalle en el sistema de pasantías.</p>`,
+			},
+			LocaleEN: {
+				Asunto: `Your application to {{.oferta_titulo}} has a new status`,
+				Texto: `Hello{{with .nombre}} {{.}}{{end}},
+
+Your application to the internship "{{.oferta_titulo}}" is now: {{with .estado_nombre}}{{.}}{{else}}{{.estado}}{{end}}.
+
+You can see the details in the internship system.`,
+				HTML: `<p>Hello{{with .nombre}} {{.}}{{end}},</p>
+<p>Your application to the internship <strong>{{.oferta_titulo}}</strong> is now: <strong>{{with .estado_nombre}}{{.}}{{else}}{{.estado}}{{end}}</strong>.</p>
+<p>You can see the details in the internship system.</p>`,
+			},
+		},
+	},
+	PlantillaPostulacionSeleccionada: {
+		ID:          PlantillaPostulacionSeleccionada,
+		Descripcion: "El tutor seleccionó la postulación del estudiante",
+		Variables:   []string{"nombre", "oferta_titulo", "empresa", "postulacion_id"},
+		Ejemplo: map[string]interface{}{
+			"nombre":         "Laura Gómez",
+			"oferta_titulo":  "Practicante de analítica de datos",
+			"empresa":        "Acme SAS",
+			"postulacion_id": 42,
+		},
+		Variantes: map[string]VariantePlantilla{
+			LocaleES: {
+				Asunto: `Tu postulación fue seleccionada: {{.oferta_titulo}}`,
+				Texto: `Hola{{with .nombre}} {{.}}{{end}},
+
+Tu postulación a la oferta "{{.oferta_titulo}}"{{with .empresa}} de {{.}}{{end}} fue seleccionada.
+
+Ingresa al sistema de pasantías para aceptar la selección. Si tienes varias selecciones, solo puedes aceptar una.`,
+				HTML: `<p>Hola{{with .nombre}} {{.}}{{end}},</p>
+<p>Tu postulación a la oferta <strong>{{.oferta_titulo}}</strong>{{with .empresa}} de {{.}}{{end}} fue seleccionada.</p>
+<p>Ingresa al sistema de pasantías para aceptar la selección. Si tienes varias selecciones, solo puedes aceptar una.</p>`,
+			},
+			LocaleEN: {
+				Asunto: `Your application was selected: {{.oferta_titulo}}`,
+				Texto: `Hello{{with .nombre}} {{.}}{{end}},
+
+Your application to the internship "{{.oferta_titulo}}"{{with .empresa}} at {{.}}{{end}} was selected.
+
+Sign in to the internship system to accept it. If you have several selections, you can accept only one.`,
+				HTML: `<p>Hello{{with .nombre}} {{.}}{{end}},</p>
+<p>Your application to the internship <strong>{{.oferta_titulo}}</strong>{{with .empresa}} at {{.}}{{end}} was selected.</p>
+<p>Sign in to the internship system to accept it. If you have several selections, you can accept only one.</p>`,
+			},
+		},
+	},
+	PlantillaEntrevistaProgramada: {
+		ID:          PlantillaEntrevistaProgramada,
+		Descripcion: "Una entrevista quedó confirmada con fecha y hora",
+		Variables:   []string{"nombre", "oferta_titulo", "inicio", "fin", "modalidad", "lugar", "enlace_video", "notas"},
+		Ejemplo: map[string]interface{}{
+			"nombre":        "Laura Gómez",
+			"oferta_titulo": "Practicante de analítica de datos",
+			"inicio":        "2026-10-20T15:00:00Z",
+			"fin":           "2026-10-20T15:30:00Z",
+			"modalidad":     "VIRTUAL",
+			"lugar":         "",
+			"enlace_video":  "https://meet.example.com/abc-defg-hij",
+			"notas":         "Ten a mano tu hoja de vida.",
+		},
+		Variantes: map[string]VariantePlantilla{
+			LocaleES: {
+				Asunto: `Entrevista confirmada{{with .oferta_titulo}}: {{.}}{{end}} ({{fecha .inicio}})`,
+				Texto: `Hola{{with .nombre}} {{.}}{{end}},
+
+Tu entrevista{{with .oferta_titulo}} para "{{.}}"{{end}} quedó confirmada.
+
+Inicio: {{fecha .inicio}} (hora de Colombia)
+Fin: {{fecha .fin}}
+{{if eq .modalidad "VIRTUAL"}}Enlace: {{.enlace_video}}{{else}}Lugar: {{.lugar}}{{end}}
+{{with .notas}}
+Notas: {{.}}
+{{end}}`,
+				HTML: `<p>Hola{{with .nombre}} {{.}}{{end}},</p>
+<p>Tu entrevista{{with .oferta_titulo}} para <strong>{{.}}</strong>{{end}} quedó confirmada.</p>
+<ul>
+<li>Inicio: {{fecha .inicio}} (hora de Colombia)</li>
+<li>Fin: {{fecha .fin}}</li>
+{{if eq .modalidad "VIRTUAL"}}<li>Enlace: <a href="{{.enlace_video}}">{{.enlace_video}}</a></li>{{else}}<li>Lugar: {{.lugar}}</li>{{end}}
+</ul>
+{{with .notas}}<p>Notas: {{.}}</p>{{end}}`,
+			},
+			LocaleEN: {
+				Asunto: `Interview confirmed{{with .oferta_titulo}}: {{.}}{{end}} ({{fecha .inicio}})`,
+				Texto: `Hello{{with .nombre}} {{.}}{{end}},
+
+Your interview{{with .oferta_titulo}} for "{{.}}"{{end}} is confirmed.
+
+Start: {{fecha .inicio}} (Colombia time)
+End: {{fecha .fin}}
+{{if eq .modalidad "VIRTUAL"}}Link: {{.enlace_video}}{{else}}Place: {{.lugar}}{{end}}
+{{with .notas}}
+Notes: {{.}}
+{{end}}`,
+				HTML: `<p>Hello{{with .nombre}} {{.}}{{end}},</p>
+<p>Your interview{{with .oferta_titulo}} for <strong>{{.}}</strong>{{end}} is confirmed.</p>
+<ul>
+<li>Start: {{fecha .inicio}} (Colombia time)</li>
+<li>End: {{fecha .fin}}</li>
+{{if eq .modalidad "VIRTUAL"}}<li>Link: <a href="{{.enlace_video}}">{{.enlace_video}}</a></li>{{else}}<li>Place: {{.lugar}}</li>{{end}}
+</ul>
+{{with .notas}}<p>Notes: {{.}}</p>{{end}}`,
+			},
+		},
+	},
+	PlantillaOfertaCancelada: {
+		ID:          PlantillaOfertaCancelada,
+		Descripcion: "La oferta a la que el estudiante postuló fue cancelada",
+		Variables:   []string{"nombre", "oferta_titulo", "empresa", "oferta_id"},
+		Ejemplo: map[string]interface{}{
+			"nombre":        "Laura Gómez",
+			"oferta_titulo": "Practicante de analítica de datos",
+			"empresa":       "Acme SAS",
+			"oferta_id":     21,
+		},
+		Variantes: map[string]VariantePlantilla{
+			LocaleES: {
+				Asunto: `La oferta {{.oferta_titulo}} fue cancelada`,
+				Texto: `Hola{{with .nombre}} {{.}}{{end}},
+
+La oferta "{{.oferta_titulo}}"{{with .empresa}} de {{.}}{{end}}, a la que postulaste, fue cancelada y no continuará el proceso de selección.
+
+Puedes buscar otras ofertas disponibles en el sistema de pasantías.`,
+				HTML: `<p>Hola{{with .nombre}} {{.}}{{end}},</p>
+<p>La oferta <strong>{{.oferta_titulo}}</strong>{{with .empresa}} de {{.}}{{end}}, a la que postulaste, fue cancelada y no continuará el proceso de selección.</p>
+<p>Puedes buscar otras ofertas disponibles en el sistema de pasantías.</p>`,
+			},
+			LocaleEN: {
+				Asunto: `The internship {{.oferta_titulo}} was cancelled`,
+				Texto: `Hello{{with .nombre}} {{.}}{{end}},
+
+The internship "{{.oferta_titulo}}"{{with .empresa}} at {{.}}{{end}}, which you applied to, was cancelled and its selection process will not continue.
+
+You can look for other open internships in the internship system.`,
+				HTML: `<p>Hello{{with .nombre}} {{.}}{{end}},</p>
+<p>The internship <strong>{{.oferta_titulo}}</strong>{{with .empresa}} at {{.}}{{end}}, which you applied to, was cancelled and its selection process will not continue.</p>
+<p>You can look for other open internships in the internship system.</p>`,
+			},
+		},
+	},
+	PlantillaResumenNotificaciones: {
+		ID:          PlantillaResumenNotificaciones,
+		Descripcion: "Resumen diario o semanal con las notificaciones acumuladas según las preferencias del tercero",
+		Variables:   []string{"frecuencia", "total", "items", "omitidas"},
+		Ejemplo: map[string]interface{}{
+			"frecuencia": "DIARIA",
+			"total":      3,
+			"items": []map[string]interface{}{
+				{"asunto": "Tu postulación a Practicante de analítica de datos cambió de estado", "plantilla": PlantillaPostulacionEstado, "fecha": "2026-10-17T14:10:00Z"},
+				{"asunto": "Te invitaron a postular: Desarrollo backend", "plantilla": PlantillaInvitacionRecibida, "fecha": "2026-10-17T19:45:00Z"},
+			},
+			"omitidas": 1,
+		},
+		Variantes: map[string]VariantePlantilla{
+			LocaleES: {
+				Asunto: `{{if eq .frecuencia "SEMANAL"}}Resumen semanal{{else}}Resumen diario{{end}} de notificaciones ({{.total}})`,
+				Texto: `Hola,
+
+Estas son las notificaciones acumuladas desde tu último resumen:
+{{range .items}}
+- {{fecha .fecha}}: {{.asunto}}{{end}}
+{{with .omitidas}}
+... y {{.}} más.
+{{end}}
+Puedes cambiar la frecuencia de los resúmenes en tus preferencias de notificación.`,
+				HTML: `<p>Hola,</p>
+<p>Estas son las notificaciones acumuladas desde tu último resumen:</p>
+<ul>
+{{range .items}}<li>{{fecha .fecha}}: {{.asunto}}</li>
+{{end}}</ul>
+{{with .omitidas}}<p>... y {{.}} más.</p>{{end}}
+<p>Puedes cambiar la frecuencia de los resúmenes en tus preferencias de notificación.</p>`,
+			},
+			LocaleEN: {
+				Asunto: `{{if eq .frecuencia "SEMANAL"}}Weekly{{else}}Daily{{end}} notification digest ({{.total}})`,
+				Texto: `Hello,
+
+These are the notifications gathered since your last digest:
+{{range .items}}
+- {{fecha .fecha}}: {{.asunto}}{{end}}
+{{with .omitidas}}
+... and {{.}} more.
+{{end}}
+You can change the digest frequency in your notification preferences.`,
+				HTML: `<p>Hello,</p>
+<p>These are the notifications gathered since your last digest:</p>
+<ul>
+{{range .items}}<li>{{fecha .fecha}}: {{.asunto}}</li>
+{{end}}</ul>
+{{with .omitidas}}<p>... and {{.}} more.</p>{{end}}
+<p>You can change the digest frequency in your notification preferences.</p>`,
+			},
+		},
+	},
+}
@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	roothelpers "github.com/udistrital/pasantia_mid/helpers"
+)
+
+func TestRenderPlantillaTodasLasPlantillas(t *testing.T) {
+	for _, resumen := range ListarPlantillas() {
+		def, ok := ObtenerPlantilla(resumen.ID)
+		if !ok {
+			t.Fatalf("%s: listada pero no registrada", resumen.ID)
+		}
+		for _, variable := range def.Variables {
+			if _, ok := def.Ejemplo[variable]; !ok {
+				t.Errorf("%s: el ejemplo no trae la variable %q", resumen.ID, variable)
+			}
+		}
+		for _, locale := range []string{LocaleES, LocaleEN} {
+			t.Run(resumen.ID+"/"+locale, func(t *testing.T) {
+				if _, ok := def.Variantes[locale]; !ok {
+					t.Fatalf("sin variante %s", locale)
+				}
+				r, err := RenderPlantilla(resumen.ID, locale, def.Ejemplo)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if r.Locale != locale {
+					t.Errorf("locale = %q, se esperaba %q", r.Locale, locale)
+				}
+				for parte, texto := range map[string]string{"asunto": r.Asunto, "texto": r.Texto, "html": r.HTML} {
+					if strings.TrimSpace(texto) == "" {
+						t.Errorf("%s vacío", parte)
+					}
+					for _, resto := range []string{"{{", "}}", "<no value>"} {
+						if strings.Contains(texto, resto) {
+							t.Errorf("%s conserva %q: %s", parte, resto, texto)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRenderPlantillaLocaleYErrores(t *testing.T) {
+	datos := map[string]interface{}{"nombre": "Ana", "oferta_titulo": "<b>Datos</b>", "oferta_id": 1}
+
+	tests := []struct {
+		nombre     string
+		id         string
+		locale     string
+		wantLocale string
+		wantStatus int
+	}{
+		{nombre: "region se reduce al idioma", id: PlantillaOfertaCancelada, locale: "en-US", wantLocale: LocaleEN},
+		{nombre: "idioma sin variante usa español", id: PlantillaOfertaCancelada, locale: "fr", wantLocale: LocaleES},
+		{nombre: "vacío usa español", id: PlantillaOfertaCancelada, locale: "", wantLocale: LocaleES},
+		{nombre: "plantilla inexistente", id: "no_existe", locale: LocaleES, wantStatus: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			r, err := RenderPlantilla(tt.id, tt.locale, datos)
+			if tt.wantStatus != 0 {
+				if appErr, ok := err.(*roothelpers.AppError); !ok || appErr.Status != tt.wantStatus {
+					t.Fatalf("error = %v, se esperaba status %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Locale != tt.wantLocale {
+				t.Errorf("locale = %q, se esperaba %q", r.Locale, tt.wantLocale)
+			}
+			if strings.Contains(r.HTML, "<b>Datos</b>") {
+				t.Errorf("el HTML no escapa los datos: %s", r.HTML)
+			}
+		})
+	}
+}
+
+func TestFormatearFechaPlantilla(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{"2026-03-10T15:00:00Z", "10/03/2026 10:00"},
+		{"no es fecha", "no es fecha"},
+		{nil, ""},
+		{42, "42"},
+	}
+	for _, tt := range tests {
+		if got := formatearFechaPlantilla(tt.in); got != tt.want {
+			t.Errorf("formatearFechaPlantilla(%v) = %q, se esperaba %q", tt.in, got, tt.want)
+		}
+	}
+}
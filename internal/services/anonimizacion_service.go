@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	"github.com/udistrital/pasantia_mid/internal/clients"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+// contactosTTL es la vigencia de los contactos calculados por tutor. Aceptar una invitación invalida
+// la entrada del tutor; una postulación nueva se refleja a más tardar al vencer el TTL.
+const contactosTTL = 5 * time.Minute
+
+// contactosTutor son los estudiantes que ya se relacionaron con el tutor: aceptaron una invitación
+// suya (por perfil) o se postularon a una de sus ofertas (por tercero).
+type contactosTutor struct {
+	perfiles map[int64]struct{}
+	terceros map[int64]struct{}
+}
+
+var cacheContactos = struct {
+	mu    sync.Mutex
+	items map[int]entradaContactos
+}{items: make(map[int]entradaContactos)}
+
+type entradaContactos struct {
+	contactos contactosTutor
+	expiresAt time.Time
+}
+
+// revela indica si el tutor puede ver la identidad del perfil anónimo.
+func (c contactosTutor) revela(perfilID, terceroID int64) bool {
+	if _, ok := c.perfiles[perfilID]; ok && perfilID > 0 {
+		return true
+	}
+	_, ok := c.terceros[terceroID]
+	return ok && terceroID > 0
+}
+
+// anonimizarCards oculta identidad y CV de los perfiles anónimos que el tutor aún no puede ver.
+// Sin tutor se anonimizan todos los perfiles en modo anónimo.
+func anonimizarCards(ctx context.Context, tutorID int, items []internaldto.EstudiantePerfilCard) {
+	cards := make([]*internaldto.EstudiantePerfilCard, len(items))
+	for i := range items {
+		cards[i] = &items[i]
+	}
+	anonimizarPerfiles(ctx, tutorID, cards)
+}
+
+// anonimizarPerfiles es la variante de anonimizarCards para tarjetas embebidas en otros resultados.
+func anonimizarPerfiles(ctx context.Context, tutorID int, cards []*internaldto.EstudiantePerfilCard) {
+	hayAnonimos := false
+	for _, card := range cards {
+		if card.Anonimo {
+			hayAnonimos = true
+			break
+		}
+	}
+	if !hayAnonimos {
+		return
+	}
+	contactos := contactosDeTutor(ctx, tutorID)
+	for _, card := range cards {
+		if card.Anonimo && !contactos.revela(card.PerfilID, card.TerceroID) {
+			anonimizarCard(card)
+		}
+	}
+}
+
+func anonimizarCard(card *internaldto.EstudiantePerfilCard) {
+	card.TerceroID = 0
+	card.CVDocumentoID = nil
+}
+
+// camposDetalleAnonimo son las claves del detalle que se muestran de un perfil anónimo. Es una
+// lista de permitidos para que un campo nuevo de estudiante_perfil no se exponga por omisión.
+var camposDetalleAnonimo = map[string]struct{}{
+	"id":                         {},
+	"proyectocurricularid":       {},
+	"resumen":                    {},
+	"habilidades":                {},
+	"modalidadpreferida":         {},
+	"visible":                    {},
+	"anonimo":                    {},
+	"tratamiento_datos_aceptado": {},
+	"guardado":                   {},
+	"fechacreacion":              {},
+	"fechamodificacion":          {},
+}
+
+// anonimizarDetalle deja en el detalle del perfil solo los campos de camposDetalleAnonimo.
+// Retorna true si el detalle quedó anonimizado.
+func anonimizarDetalle(ctx context.Context, tutorID int, perfil map[string]interface{}) bool {
+	if !normalizeToBool(perfil["anonimo"], false) {
+		return false
+	}
+	perfilID, _ := normalizeToInt64(perfil["id"])
+	terceroID, _ := normalizeToInt64(perfil["terceroid"])
+	if contactosDeTutor(ctx, tutorID).revela(perfilID, terceroID) {
+		return false
+	}
+	for key := range perfil {
+		if _, ok := camposDetalleAnonimo[key]; !ok {
+			delete(perfil, key)
+		}
+	}
+	return true
+}
+
+// contactosDeTutor calcula, con caché por tutor, los estudiantes relacionados con él. Si una fuente
+// falla se usa lo obtenido de las demás: ante la duda el perfil permanece anónimo.
+func contactosDeTutor(ctx context.Context, tutorID int) contactosTutor {
+	vacio := contactosTutor{perfiles: map[int64]struct{}{}, terceros: map[int64]struct{}{}}
+	if tutorID <= 0 {
+		return vacio
+	}
+
+	cacheContactos.mu.Lock()
+	if e, ok := cacheContactos.items[tutorID]; ok && time.Now().Before(e.expiresAt) {
+		cacheContactos.mu.Unlock()
+		return e.contactos
+	}
+	cacheContactos.mu.Unlock()
+
+	contactos := vacio
+	if perfiles, err := perfilesConInvitacionAceptada(tutorID); err != nil {
+		logs.Warn("contactos del tutor", tutorID, ":", err)
+	} else {
+		contactos.perfiles = perfiles
+	}
+
+	ofertas, err := rootservices.ListOfertas(map[string]string{"tutor_externo_id": fmt.Sprint(tutorID)})
+	if err != nil {
+		logs.Warn("contactos del tutor", tutorID, ":", err)
+	}
+	for _, o := range ofertas {
+		if int(o.TutorExternoId) != tutorID {
+			continue
+		}
+		postulaciones, err := clients.CastorCRUD().ListPostulaciones(ctx, map[string]string{
+			"oferta_id": fmt.Sprint(o.Id),
+			"limit":     "0",
+		})
+		if err != nil {
+			logs.Warn("contactos del tutor", tutorID, "oferta", o.Id, ":", err)
+			continue
+		}
+		for _, p := range postulaciones {
+			if p.EstudianteId > 0 {
+				contactos.terceros[p.EstudianteId] = struct{}{}
+			}
+		}
+	}
+
+	cacheContactos.mu.Lock()
+	cacheContactos.items[tutorID] = entradaContactos{contactos: contactos, expiresAt: time.Now().Add(contactosTTL)}
+	cacheContactos.mu.Unlock()
+	return contactos
+}
+
+// invalidarContactosTutor descarta los contactos en caché del tutor.
+func invalidarContactosTutor(tutorID int) {
+	cacheContactos.mu.Lock()
+	delete(cacheContactos.items, tutorID)
+	cacheContactos.mu.Unlock()
+}
+
+func perfilesConInvitacionAceptada(tutorID int) (map[int64]struct{}, error) {
+	set := make(map[int64]struct{})
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, invitacionesResource) + "?estado=" + InvitacionEstadoAceptada
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSONWithHeaders(
+		"GET",
+		endpoint,
+		map[string]string{"X-Tutor-Id": fmt.Sprint(tutorID)},
+		nil,
+		&raw,
+		cfg.RequestTimeout,
+		true,
+	); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return set, nil
+		}
+		return nil, err
+	}
+	for _, it := range raw {
+		inv := normalizeInvitacion(it)
+		if !strings.EqualFold(strings.TrimSpace(normalizeToString(inv["estado"])), InvitacionEstadoAceptada) {
+			continue
+		}
+		if perfilID, ok := normalizeToInt64(inv["perfil_estudiante_id"]); ok && perfilID > 0 {
+			set[perfilID] = struct{}{}
+		}
+	}
+	return set, nil
+}
+
+// ocultaEstudianteInvitacion indica si el tutor debe ver la invitación sin la identidad del
+// estudiante: el perfil es anónimo, la invitación no ha sido aceptada y no existe otro contacto.
+func ocultaEstudianteInvitacion(ctx context.Context, tutorID int, inv map[string]interface{}, perfil *clients.PerfilRecord) bool {
+	if perfil == nil || !normalizeToBool(perfil.Extra["Anonimo"], false) {
+		return false
+	}
+	if strings.EqualFold(strings.TrimSpace(normalizeToString(inv["estado"])), InvitacionEstadoAceptada) {
+		return false
+	}
+	return !contactosDeTutor(ctx, tutorID).revela(int64(perfil.Id), int64(perfil.TerceroId))
+}
+
+// anonimizarInvitacionDetalle retira la identidad y el CV del estudiante del detalle de una
+// invitación consultada por el tutor cuando aplica ocultaEstudianteInvitacion. perfil es el
+// registro ya obtenido al enriquecer la invitación; si es nil no se puede verificar el
+// consentimiento de anonimato y, ante la duda, el estudiante permanece anónimo.
+func anonimizarInvitacionDetalle(ctx context.Context, tutorID int, inv map[string]interface{}, perfil *clients.PerfilRecord) {
+	detalle, _ := inv["estudiante_detalle"].(map[string]any)
+	if tutorID <= 0 || detalle == nil {
+		return
+	}
+	if perfil != nil && !ocultaEstudianteInvitacion(ctx, tutorID, inv, perfil) {
+		return
+	}
+	for _, key := range []string{"tercero_id", "nombre_completo", "cv_documento_id"} {
+		delete(detalle, key)
+	}
+	detalle["anonimo"] = true
+}
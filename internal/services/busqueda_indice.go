@@ -0,0 +1,578 @@
+package services
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	"github.com/udistrital/pasantia_mid/models"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	defaultBusquedaReindexadoMin = 30
+
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	// Peso de los términos del índice que solo comparten prefijo con el término buscado.
+	pesoPrefijo = 0.5
+)
+
+// Pesos por campo: una coincidencia en el título o las habilidades cuenta más que en la descripción.
+const (
+	pesoCampoTitulo      = 3.0
+	pesoCampoHabilidades = 2.0
+	pesoCampoPC          = 1.5
+	pesoCampoTexto       = 1.0
+)
+
+// docBusqueda es un perfil u oferta indexado con sus términos ponderados y atributos de faceta.
+type docBusqueda struct {
+	ID          int64
+	Terminos    map[string]float64
+	Longitud    float64
+	PCIDs       []int
+	Facultades  []int
+	Modalidad   string
+	Estado      string
+	Habilidades map[string]struct{}
+	Fecha       time.Time
+
+	Perfil internaldto.EstudiantePerfilCard
+	Oferta models.Oferta
+}
+
+// filtroBusqueda restringe los documentos; los valores cero no filtran.
+type filtroBusqueda struct {
+	PCID        int
+	FacultadID  int
+	Modalidad   string
+	Estados     []string
+	Habilidades []string
+}
+
+type docPuntuado struct {
+	Doc     *docBusqueda
+	Puntaje float64
+}
+
+// indiceBusqueda es un índice invertido en memoria con ranking BM25.
+type indiceBusqueda struct {
+	mu            sync.RWMutex
+	docs          map[int64]*docBusqueda
+	df            map[string]int
+	longitudTotal float64
+	listo         bool
+	actualizado   time.Time
+}
+
+var (
+	indicePerfiles = newIndiceBusqueda()
+	indiceOfertas  = newIndiceBusqueda()
+
+	busquedaOnce sync.Once
+
+	// catalogoPCBusqueda resuelve nombre y facultad de cada proyecto curricular para las facetas.
+	catalogoPCBusqueda struct {
+		mu             sync.RWMutex
+		nombrePC       map[int]string
+		facultadPC     map[int]int
+		nombreFacultad map[int]string
+	}
+)
+
+func newIndiceBusqueda() *indiceBusqueda {
+	return &indiceBusqueda{docs: map[int64]*docBusqueda{}, df: map[string]int{}}
+}
+
+// IniciarIndiceBusqueda construye los índices de perfiles y ofertas en segundo plano y los
+// reconstruye periódicamente. Entre reconstrucciones se actualizan de forma incremental cuando
+// los perfiles u ofertas cambian a través del MID. Con busqueda_reindexado_intervalo_min = 0
+// los índices no se construyen y las búsquedas usan el CRUD.
+func IniciarIndiceBusqueda() {
+	busquedaOnce.Do(func() {
+		intervalo := configMinutos("BUSQUEDA_REINDEXADO_INTERVALO_MIN", "busqueda_reindexado_intervalo_min", defaultBusquedaReindexadoMin)
+		if intervalo <= 0 {
+			return
+		}
+		go func() {
+			reconstruirIndicesBusqueda()
+			ticker := time.NewTicker(intervalo)
+			defer ticker.Stop()
+			for range ticker.C {
+				reconstruirIndicesBusqueda()
+			}
+		}()
+	})
+}
+
+func reconstruirIndicesBusqueda() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("índice de búsqueda:", r)
+		}
+	}()
+
+	cargarCatalogoPCBusqueda()
+
+	if perfiles, err := listPerfilesVisibles(); err != nil {
+		logs.Warn("índice de búsqueda: perfiles:", err)
+	} else {
+		docs := make([]*docBusqueda, 0, len(perfiles))
+		for _, entry := range perfiles {
+			if doc := docDesdePerfil(entry); doc != nil {
+				docs = append(docs, doc)
+			}
+		}
+		indicePerfiles.reemplazar(docs)
+	}
+
+	ofertas, err := rootservices.ListOfertas(map[string]string{})
+	if err != nil {
+		logs.Warn("índice de búsqueda: ofertas:", err)
+		return
+	}
+	pcs := make([][]int, len(ofertas))
+	sem := make(chan struct{}, matchConcurrency)
+	var wg sync.WaitGroup
+	for i, o := range ofertas {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx, ofertaID int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			pcs[idx], _ = getPCIDsByOferta(ofertaID)
+		}(i, int(o.Id))
+	}
+	wg.Wait()
+
+	docs := make([]*docBusqueda, 0, len(ofertas))
+	for i, o := range ofertas {
+		docs = append(docs, docDesdeOferta(o, pcs[i]))
+	}
+	indiceOfertas.reemplazar(docs)
+}
+
+// reindexarPerfil actualiza el perfil en el índice sin bloquear la petición que lo modificó.
+func reindexarPerfil(perfilID int) {
+	if perfilID <= 0 || !indicePerfiles.estaListo() {
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logs.Error("índice de búsqueda: perfil", perfilID, ":", r)
+			}
+		}()
+		cfg := rootservices.GetConfig()
+		endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, perfilResource, strconv.Itoa(perfilID))
+		var record map[string]interface{}
+		if err := helpers.DoJSON("GET", endpoint, nil, &record, cfg.RequestTimeout); err != nil {
+			if helpers.IsHTTPError(err, http.StatusNotFound) {
+				indicePerfiles.eliminar(int64(perfilID))
+				return
+			}
+			logs.Warn("índice de búsqueda: perfil", perfilID, ":", err)
+			return
+		}
+		if doc := docDesdePerfil(record); doc != nil {
+			indicePerfiles.upsert(doc)
+		} else {
+			indicePerfiles.eliminar(int64(perfilID))
+		}
+	}()
+}
+
+// reindexarOferta actualiza la oferta en el índice sin bloquear la petición que la modificó.
+func reindexarOferta(ofertaID int) {
+	if ofertaID <= 0 || !indiceOfertas.estaListo() {
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logs.Error("índice de búsqueda: oferta", ofertaID, ":", r)
+			}
+		}()
+		oferta, err := rootservices.GetOferta(int64(ofertaID))
+		if err != nil && !helpers.IsHTTPError(err, http.StatusNotFound) {
+			logs.Warn("índice de búsqueda: oferta", ofertaID, ":", err)
+			return
+		}
+		if oferta == nil {
+			indiceOfertas.eliminar(int64(ofertaID))
+			return
+		}
+		pcIDs, _ := getPCIDsByOferta(ofertaID)
+		indiceOfertas.upsert(docDesdeOferta(*oferta, pcIDs))
+	}()
+}
+
+// docDesdePerfil indexa solo perfiles visibles con tratamiento de datos aceptado.
+func docDesdePerfil(entry map[string]interface{}) *docBusqueda {
+	card := mapToPerfilCardExplorar(entry, nil)
+	if card.PerfilID == 0 || !card.Visible || !card.TratamientoDatosAceptado {
+		return nil
+	}
+	card.Habilidades = habilidadesPerfil(entry["Habilidades"])
+
+	doc := &docBusqueda{
+		ID:        card.PerfilID,
+		Terminos:  map[string]float64{},
+		Modalidad: normalizarTexto(normalizeToString(entry["ModalidadPreferida"])),
+		Fecha:     parseTime(normalizeToString(entry["FechaModificacion"])),
+	}
+	if card.ProyectoCurricularID > 0 {
+		pcID := int(card.ProyectoCurricularID)
+		doc.PCIDs = []int{pcID}
+		if nombre := nombrePCBusqueda(pcID); nombre != "" {
+			card.ProyectoCurricularNombre = nombre
+			card.ProyectoCurricular = map[string]interface{}{"id": card.ProyectoCurricularID, "nombre": nombre}
+			doc.agregarCampo(nombre, pesoCampoPC)
+		}
+	}
+	doc.Facultades = facultadesDePCs(doc.PCIDs)
+	doc.agregarCampo(card.Resumen, pesoCampoTexto)
+	doc.agregarHabilidades(card.Habilidades)
+	doc.Perfil = card
+	return doc
+}
+
+func docDesdeOferta(oferta models.Oferta, pcIDs []int) *docBusqueda {
+	doc := &docBusqueda{
+		ID:         oferta.Id,
+		Terminos:   map[string]float64{},
+		PCIDs:      pcIDs,
+		Facultades: facultadesDePCs(pcIDs),
+		Modalidad:  normalizarTexto(oferta.Modalidad),
+		Estado:     strings.TrimSpace(oferta.Estado),
+		Fecha:      oferta.FechaPublicacion,
+		Oferta:     oferta,
+	}
+	doc.agregarCampo(oferta.Titulo, pesoCampoTitulo)
+	doc.agregarCampo(oferta.Descripcion, pesoCampoTexto)
+	doc.agregarHabilidades(oferta.Habilidades)
+	for _, pcID := range pcIDs {
+		doc.agregarCampo(nombrePCBusqueda(pcID), pesoCampoTexto)
+	}
+	return doc
+}
+
+func (d *docBusqueda) agregarCampo(texto string, peso float64) {
+	for _, t := range tokenizarES(texto) {
+		d.Terminos[t] += peso
+		d.Longitud += peso
+	}
+}
+
+func (d *docBusqueda) agregarHabilidades(habilidades []string) {
+	if d.Habilidades == nil {
+		d.Habilidades = map[string]struct{}{}
+	}
+	for _, h := range habilidades {
+		d.Habilidades[normalizarHabilidad(h)] = struct{}{}
+		d.agregarCampo(h, pesoCampoHabilidades)
+	}
+}
+
+func (idx *indiceBusqueda) estaListo() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.listo
+}
+
+func (idx *indiceBusqueda) fechaActualizacion() time.Time {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.actualizado
+}
+
+// documentos retorna los documentos indexados con los IDs dados, en el mismo orden; omite los ausentes.
+func (idx *indiceBusqueda) documentos(ids []int64) []*docBusqueda {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	out := make([]*docBusqueda, 0, len(ids))
+	for _, id := range ids {
+		if d, ok := idx.docs[id]; ok {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func (idx *indiceBusqueda) reemplazar(docs []*docBusqueda) {
+	nuevo := newIndiceBusqueda()
+	for _, d := range docs {
+		nuevo.agregarLocked(d)
+	}
+	idx.mu.Lock()
+	idx.docs, idx.df, idx.longitudTotal = nuevo.docs, nuevo.df, nuevo.longitudTotal
+	idx.listo = true
+	idx.actualizado = time.Now()
+	idx.mu.Unlock()
+}
+
+func (idx *indiceBusqueda) upsert(doc *docBusqueda) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.quitarLocked(doc.ID)
+	idx.agregarLocked(doc)
+	idx.actualizado = time.Now()
+}
+
+func (idx *indiceBusqueda) eliminar(id int64) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.quitarLocked(id)
+	idx.actualizado = time.Now()
+}
+
+func (idx *indiceBusqueda) agregarLocked(doc *docBusqueda) {
+	idx.docs[doc.ID] = doc
+	idx.longitudTotal += doc.Longitud
+	for t := range doc.Terminos {
+		idx.df[t]++
+	}
+}
+
+func (idx *indiceBusqueda) quitarLocked(id int64) {
+	prev, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	delete(idx.docs, id)
+	idx.longitudTotal -= prev.Longitud
+	for t := range prev.Terminos {
+		if idx.df[t] <= 1 {
+			delete(idx.df, t)
+		} else {
+			idx.df[t]--
+		}
+	}
+}
+
+// buscar retorna los documentos que cumplen el filtro ordenados por relevancia (o por fecha si
+// no hay consulta) y las facetas. Una consulta hecha solo de palabras vacías cuenta como sin
+// consulta. Cada faceta se cuenta aplicando los demás filtros, no el propio, para que el cliente
+// pueda mostrar las alternativas de la dimensión seleccionada. Los nombres de las facetas se
+// resuelven después de soltar el candado porque pueden consultar OIKOS.
+func (idx *indiceBusqueda) buscar(consulta string, filtro filtroBusqueda) ([]docPuntuado, map[string]interface{}) {
+	tokens := tokenizarES(consulta)
+	conConsulta := len(tokens) > 0
+
+	idx.mu.RLock()
+	terminos := idx.expandirConsultaLocked(tokens)
+	n := float64(len(idx.docs))
+	promedio := 1.0
+	if n > 0 && idx.longitudTotal > 0 {
+		promedio = idx.longitudTotal / n
+	}
+
+	conteoPC := map[int]int{}
+	conteoFacultad := map[int]int{}
+	conteoModalidad := map[string]int{}
+	resultados := make([]docPuntuado, 0)
+
+	for _, doc := range idx.docs {
+		var puntaje float64
+		if conConsulta {
+			for t, peso := range terminos {
+				tf := doc.Terminos[t]
+				if tf == 0 {
+					continue
+				}
+				df := float64(idx.df[t])
+				idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+				puntaje += peso * idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*doc.Longitud/promedio))
+			}
+			if puntaje == 0 {
+				continue
+			}
+		}
+
+		pasaPC := filtro.PCID <= 0 || hasInt(doc.PCIDs, filtro.PCID)
+		pasaFacultad := filtro.FacultadID <= 0 || hasInt(doc.Facultades, filtro.FacultadID)
+		pasaModalidad := filtro.Modalidad == "" || doc.Modalidad == normalizarTexto(filtro.Modalidad)
+		if !doc.cumpleEstadoYHabilidades(filtro) {
+			continue
+		}
+
+		if pasaFacultad && pasaModalidad {
+			for _, pc := range doc.PCIDs {
+				conteoPC[pc]++
+			}
+		}
+		if pasaPC && pasaModalidad {
+			for _, f := range doc.Facultades {
+				conteoFacultad[f]++
+			}
+		}
+		if pasaPC && pasaFacultad && doc.Modalidad != "" {
+			conteoModalidad[doc.Modalidad]++
+		}
+		if pasaPC && pasaFacultad && pasaModalidad {
+			resultados = append(resultados, docPuntuado{Doc: doc, Puntaje: math.Round(puntaje*1000) / 1000})
+		}
+	}
+	idx.mu.RUnlock()
+
+	sort.SliceStable(resultados, func(i, j int) bool {
+		if resultados[i].Puntaje != resultados[j].Puntaje {
+			return resultados[i].Puntaje > resultados[j].Puntaje
+		}
+		if !resultados[i].Doc.Fecha.Equal(resultados[j].Doc.Fecha) {
+			return resultados[i].Doc.Fecha.After(resultados[j].Doc.Fecha)
+		}
+		return resultados[i].Doc.ID < resultados[j].Doc.ID
+	})
+
+	facetas := map[string]interface{}{
+		"proyecto_curricular": facetaIDs(conteoPC, nombrePCBusqueda),
+		"facultad":            facetaIDs(conteoFacultad, nombreFacultadBusqueda),
+		"modalidad":           facetaValores(conteoModalidad),
+	}
+	return resultados, facetas
+}
+
+// expandirConsultaLocked agrega a cada término los del índice que empiezan igual, con menor peso,
+// para que búsquedas parciales como "progra" encuentren "programación".
+func (idx *indiceBusqueda) expandirConsultaLocked(tokens []string) map[string]float64 {
+	out := make(map[string]float64, len(tokens))
+	for _, t := range tokens {
+		out[t] = 1
+		if len([]rune(t)) < 3 {
+			continue
+		}
+		for termino := range idx.df {
+			if termino != t && strings.HasPrefix(termino, t) {
+				if out[termino] < pesoPrefijo {
+					out[termino] = pesoPrefijo
+				}
+			}
+		}
+	}
+	return out
+}
+
+func (d *docBusqueda) cumpleEstadoYHabilidades(filtro filtroBusqueda) bool {
+	if len(filtro.Estados) > 0 {
+		ok := false
+		for _, e := range filtro.Estados {
+			if strings.EqualFold(e, d.Estado) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	for _, h := range filtro.Habilidades {
+		if _, ok := d.Habilidades[normalizarHabilidad(h)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func facetaIDs(conteo map[int]int, nombre func(int) string) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(conteo))
+	for id, total := range conteo {
+		out = append(out, map[string]interface{}{"id": id, "nombre": nombre(id), "total": total})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i]["total"].(int) != out[j]["total"].(int) {
+			return out[i]["total"].(int) > out[j]["total"].(int)
+		}
+		return out[i]["id"].(int) < out[j]["id"].(int)
+	})
+	return out
+}
+
+func facetaValores(conteo map[string]int) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(conteo))
+	for valor, total := range conteo {
+		out = append(out, map[string]interface{}{"valor": strings.ToUpper(valor), "total": total})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i]["total"].(int) != out[j]["total"].(int) {
+			return out[i]["total"].(int) > out[j]["total"].(int)
+		}
+		return out[i]["valor"].(string) < out[j]["valor"].(string)
+	})
+	return out
+}
+
+// cargarCatalogoPCBusqueda toma de OIKOS la relación facultad -> proyectos curriculares.
+// Si OIKOS falla se conserva la última carga válida.
+func cargarCatalogoPCBusqueda() {
+	body, err := obtenerProyectosPorFacultad(nil)
+	if err != nil || len(body) == 0 {
+		if err != nil {
+			logs.Warn("índice de búsqueda: proyectos por facultad:", err)
+		}
+		return
+	}
+	nombrePC := map[int]string{}
+	facultadPC := map[int]int{}
+	nombreFacultad := map[int]string{}
+	for _, fac := range body {
+		nombreFacultad[fac.Id] = strings.TrimSpace(fac.Nombre)
+		for _, pc := range extraerHijosPrimerNivel(fac.Opciones, fac.Id) {
+			nombrePC[pc.Id] = strings.TrimSpace(pc.Nombre)
+			facultadPC[pc.Id] = fac.Id
+		}
+	}
+	catalogoPCBusqueda.mu.Lock()
+	catalogoPCBusqueda.nombrePC = nombrePC
+	catalogoPCBusqueda.facultadPC = facultadPC
+	catalogoPCBusqueda.nombreFacultad = nombreFacultad
+	catalogoPCBusqueda.mu.Unlock()
+}
+
+func nombrePCBusqueda(pcID int) string {
+	catalogoPCBusqueda.mu.RLock()
+	nombre, ok := catalogoPCBusqueda.nombrePC[pcID]
+	catalogoPCBusqueda.mu.RUnlock()
+	if ok {
+		return nombre
+	}
+	if detalle, err := rootservices.GetProyectoCurricular(pcID); err == nil && detalle != nil {
+		nombre = strings.TrimSpace(detalle.Nombre)
+	}
+	// También se guarda el vacío para no consultar de nuevo hasta la próxima reconstrucción.
+	catalogoPCBusqueda.mu.Lock()
+	if catalogoPCBusqueda.nombrePC == nil {
+		catalogoPCBusqueda.nombrePC = map[int]string{}
+	}
+	catalogoPCBusqueda.nombrePC[pcID] = nombre
+	catalogoPCBusqueda.mu.Unlock()
+	return nombre
+}
+
+func nombreFacultadBusqueda(facultadID int) string {
+	catalogoPCBusqueda.mu.RLock()
+	defer catalogoPCBusqueda.mu.RUnlock()
+	return catalogoPCBusqueda.nombreFacultad[facultadID]
+}
+
+func facultadesDePCs(pcIDs []int) []int {
+	catalogoPCBusqueda.mu.RLock()
+	defer catalogoPCBusqueda.mu.RUnlock()
+	out := make([]int, 0, len(pcIDs))
+	for _, pc := range pcIDs {
+		if f, ok := catalogoPCBusqueda.facultadPC[pc]; ok && !hasInt(out, f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
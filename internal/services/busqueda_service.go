@@ -0,0 +1,139 @@
+package services
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	"github.com/udistrital/pasantia_mid/models"
+)
+
+// FiltrosBusqueda agrupa los filtros de la búsqueda local; los valores vacíos no filtran.
+type FiltrosBusqueda struct {
+	Query       string
+	PCID        int
+	FacultadID  int
+	Modalidad   string
+	Habilidades string
+	Estados     string
+	Page        int
+	Size        int
+}
+
+// PerfilBusqueda es un perfil del catálogo con su relevancia para la consulta.
+type PerfilBusqueda struct {
+	internaldto.EstudiantePerfilCard
+	PuntajeBusqueda float64 `json:"puntaje_busqueda"`
+}
+
+// BuscarEstudiantes consulta el índice local de perfiles visibles: resumen, habilidades y
+// nombre del proyecto curricular, con facetas por proyecto curricular, facultad y modalidad.
+func BuscarEstudiantes(f FiltrosBusqueda) (map[string]interface{}, error) {
+	if !indicePerfiles.estaListo() {
+		return nil, helpers.NewAppError(http.StatusServiceUnavailable, "índice de búsqueda en construcción, intenta de nuevo en unos minutos", nil)
+	}
+	resultados, facetas := indicePerfiles.buscar(f.Query, f.filtro(nil))
+	items := make([]PerfilBusqueda, 0, f.Size)
+	for _, r := range paginarResultados(resultados, f.Page, f.Size) {
+		item := PerfilBusqueda{EstudiantePerfilCard: r.Doc.Perfil, PuntajeBusqueda: r.Puntaje}
+		// La búsqueda no identifica al tutor: los perfiles anónimos siempre se anonimizan.
+		if item.Anonimo {
+			anonimizarCard(&item.EstudiantePerfilCard)
+		}
+		items = append(items, item)
+	}
+	return respuestaBusqueda(items, len(resultados), f, facetas, indicePerfiles.fechaActualizacion()), nil
+}
+
+// BuscarOfertas consulta el índice local de ofertas por título, descripción, habilidades y
+// proyectos curriculares. Sin estados explícitos solo incluye ofertas abiertas.
+func BuscarOfertas(f FiltrosBusqueda) (map[string]interface{}, error) {
+	if !indiceOfertas.estaListo() {
+		return nil, helpers.NewAppError(http.StatusServiceUnavailable, "índice de búsqueda en construcción, intenta de nuevo en unos minutos", nil)
+	}
+	resultados, facetas := indiceOfertas.buscar(f.Query, f.filtro([]string{models.OfertaEstadoCreada}))
+	items := make([]map[string]interface{}, 0, f.Size)
+	for _, r := range paginarResultados(resultados, f.Page, f.Size) {
+		m := mapOferta(r.Doc.Oferta)
+		m["proyecto_curricular_ids"] = r.Doc.PCIDs
+		m["puntaje_busqueda"] = r.Puntaje
+		items = append(items, m)
+	}
+	return respuestaBusqueda(items, len(resultados), f, facetas, indiceOfertas.fechaActualizacion()), nil
+}
+
+// catalogoDesdeIndice resuelve el catálogo de Explorar con ranking por relevancia cuando hay texto libre.
+func catalogoDesdeIndice(filters CatalogoFilters) internaldto.PageDTO[internaldto.EstudiantePerfilCard] {
+	f := FiltrosBusqueda{Query: filters.Query, Habilidades: filters.Skills, Page: filters.Page, Size: filters.Size}
+	if filters.ProyectoCurricularID != nil {
+		f.PCID = *filters.ProyectoCurricularID
+	}
+	if f.Page <= 0 {
+		f.Page = 1
+	}
+	if f.Size <= 0 {
+		f.Size = 10
+	}
+	resultados, _ := indicePerfiles.buscar(f.Query, f.filtro(nil))
+	items := make([]internaldto.EstudiantePerfilCard, 0, f.Size)
+	for _, r := range paginarResultados(resultados, f.Page, f.Size) {
+		items = append(items, r.Doc.Perfil)
+	}
+	return internaldto.PageDTO[internaldto.EstudiantePerfilCard]{Items: items, Page: f.Page, Size: f.Size, Total: int64(len(resultados))}
+}
+
+// puntajesOfertas retorna la relevancia de cada oferta para la consulta, o nil si el índice no está listo.
+func puntajesOfertas(consulta string) map[int64]float64 {
+	if strings.TrimSpace(consulta) == "" || !indiceOfertas.estaListo() {
+		return nil
+	}
+	resultados, _ := indiceOfertas.buscar(consulta, filtroBusqueda{})
+	out := make(map[int64]float64, len(resultados))
+	for _, r := range resultados {
+		out[r.Doc.ID] = r.Puntaje
+	}
+	return out
+}
+
+func (f FiltrosBusqueda) filtro(estadosPorDefecto []string) filtroBusqueda {
+	estados := parseEstados(f.Estados)
+	if len(estados) == 0 {
+		estados = estadosPorDefecto
+	}
+	var habilidades []string
+	if csv := canonizarTerminosHabilidad(f.Habilidades); csv != "" {
+		habilidades = strings.Split(csv, ",")
+	}
+	return filtroBusqueda{
+		PCID:        f.PCID,
+		FacultadID:  f.FacultadID,
+		Modalidad:   f.Modalidad,
+		Estados:     estados,
+		Habilidades: habilidades,
+	}
+}
+
+func paginarResultados(resultados []docPuntuado, page, size int) []docPuntuado {
+	start := (page - 1) * size
+	if start < 0 || start >= len(resultados) {
+		return nil
+	}
+	end := start + size
+	if end > len(resultados) {
+		end = len(resultados)
+	}
+	return resultados[start:end]
+}
+
+func respuestaBusqueda(items interface{}, total int, f FiltrosBusqueda, facetas map[string]interface{}, actualizado time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"items":              items,
+		"total":              total,
+		"page":               f.Page,
+		"size":               f.Size,
+		"facetas":            facetas,
+		"indice_actualizado": actualizado.Format(time.RFC3339),
+	}
+}
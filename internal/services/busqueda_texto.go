@@ -0,0 +1,81 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopwordsES son palabras vacías que no aportan a la búsqueda (ya sin tildes).
+var stopwordsES = map[string]struct{}{
+	"a": {}, "al": {}, "algo": {}, "ante": {}, "bajo": {}, "como": {}, "con": {}, "contra": {},
+	"cual": {}, "de": {}, "del": {}, "desde": {}, "donde": {}, "durante": {}, "e": {}, "el": {},
+	"ella": {}, "en": {}, "entre": {}, "es": {}, "esta": {}, "este": {}, "esto": {}, "hacia": {},
+	"hasta": {}, "la": {}, "las": {}, "le": {}, "les": {}, "lo": {}, "los": {}, "mas": {}, "me": {},
+	"mi": {}, "mis": {}, "muy": {}, "ni": {}, "no": {}, "o": {}, "para": {}, "pero": {}, "por": {},
+	"que": {}, "se": {}, "segun": {}, "ser": {}, "si": {}, "sin": {}, "sobre": {}, "su": {}, "sus": {},
+	"tambien": {}, "te": {}, "tu": {}, "un": {}, "una": {}, "unas": {}, "uno": {}, "unos": {}, "y": {},
+	"ya": {}, "yo": {},
+}
+
+// tokenizarES separa el texto en términos sin tildes ni mayúsculas, descarta palabras vacías y
+// reduce cada término a su raíz, de modo que "Programación" y "programaciones" coincidan.
+func tokenizarES(texto string) []string {
+	campos := strings.FieldsFunc(normalizarTexto(texto), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := make([]string, 0, len(campos))
+	for _, c := range campos {
+		if _, vacia := stopwordsES[c]; vacia {
+			continue
+		}
+		if len([]rune(c)) < 2 && !unicode.IsDigit([]rune(c)[0]) {
+			continue
+		}
+		out = append(out, raizES(c))
+	}
+	return out
+}
+
+// raizES aplica un stemming ligero para español: plurales, adverbios en -mente y vocal final.
+// Es deliberadamente conservador para no unir palabras de significado distinto.
+func raizES(w string) string {
+	if len([]rune(w)) <= 4 {
+		return w
+	}
+	switch {
+	case strings.HasSuffix(w, "ciones"):
+		w = strings.TrimSuffix(w, "es")
+	case strings.HasSuffix(w, "mente") && len([]rune(w)) > 8:
+		w = strings.TrimSuffix(w, "mente")
+	case strings.HasSuffix(w, "ces"):
+		w = strings.TrimSuffix(w, "ces") + "z"
+	case strings.HasSuffix(w, "es") && !esVocal(penultima(w)):
+		w = strings.TrimSuffix(w, "es")
+	case strings.HasSuffix(w, "s"):
+		w = strings.TrimSuffix(w, "s")
+	}
+	if r := []rune(w); len(r) > 4 {
+		switch r[len(r)-1] {
+		case 'a', 'e', 'o':
+			w = string(r[:len(r)-1])
+		}
+	}
+	return w
+}
+
+// penultima retorna la letra anterior al sufijo "es".
+func penultima(w string) rune {
+	r := []rune(w)
+	if len(r) < 3 {
+		return 0
+	}
+	return r[len(r)-3]
+}
+
+func esVocal(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+)
+
+const (
+	defaultBusquedasAlertasIntervaloMin = 60
+	defaultBusquedasResumenHora         = 7
+
+	plantillaBusquedaAlerta  = "busqueda_guardada_alerta"
+	plantillaBusquedaResumen = "busqueda_guardada_resumen"
+
+	// maxItemsAlertaBusqueda limita los resultados incluidos en cada notificación.
+	maxItemsAlertaBusqueda = 10
+)
+
+var alertasBusquedasOnce sync.Once
+
+// IniciarAlertasBusquedas arranca el proceso periódico que vuelve a ejecutar las búsquedas guardadas
+// y notifica las coincidencias nuevas: al momento (INSTANTANEA) o en un resumen diario (DIARIA)
+// enviado a partir de busquedas_resumen_hora. Se desactiva con busquedas_alertas_intervalo_min = 0.
+func IniciarAlertasBusquedas() {
+	alertasBusquedasOnce.Do(func() {
+		intervalo := configMinutos("BUSQUEDAS_ALERTAS_INTERVALO_MIN", "busquedas_alertas_intervalo_min", defaultBusquedasAlertasIntervaloMin)
+		if intervalo <= 0 {
+			return
+		}
+		horaResumen := configEntero("BUSQUEDAS_RESUMEN_HORA", "busquedas_resumen_hora", defaultBusquedasResumenHora)
+		if horaResumen < 0 || horaResumen > 23 {
+			horaResumen = defaultBusquedasResumenHora
+		}
+		go func() {
+			ticker := time.NewTicker(intervalo)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				ejecutarAlertasBusquedas(now, horaResumen)
+			}
+		}()
+	})
+}
+
+// ejecutarAlertasBusquedas compara los resultados actuales de cada búsqueda activa con los de la
+// ejecución anterior. Espera a que el índice local esté listo para no reportar falsos nuevos.
+func ejecutarAlertasBusquedas(now time.Time, horaResumen int) {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("alertas de búsquedas guardadas:", r)
+		}
+	}()
+
+	if !indicePerfiles.estaListo() || !indiceOfertas.estaListo() {
+		return
+	}
+	busquedas, err := listBusquedasGuardadasCRUD("Activa:true")
+	if err != nil {
+		logs.Warn("alertas de búsquedas guardadas:", err)
+		return
+	}
+	for _, b := range busquedas {
+		if !b.Activa {
+			continue
+		}
+		if cambio := procesarBusquedaGuardada(&b, now, horaResumen); cambio {
+			if err := updateBusquedaGuardadaCRUD(b); err != nil {
+				logs.Warn("búsqueda guardada", b.ID, ":", err)
+			}
+		}
+	}
+}
+
+// procesarBusquedaGuardada ejecuta la búsqueda, envía la alerta o acumula el resumen y reporta si
+// el registro cambió y debe guardarse.
+func procesarBusquedaGuardada(b *busquedaGuardada, now time.Time, horaResumen int) bool {
+	actuales := b.ejecutar()
+	if b.UltimaEjecucion.IsZero() {
+		b.UltimosResultados = actuales
+		b.UltimaEjecucion = now
+		return true
+	}
+
+	previos := make(map[int64]struct{}, len(b.UltimosResultados))
+	for _, id := range b.UltimosResultados {
+		previos[id] = struct{}{}
+	}
+	var nuevos []int64
+	for _, id := range actuales {
+		if _, ok := previos[id]; !ok {
+			nuevos = append(nuevos, id)
+		}
+	}
+	cambio := len(nuevos) > 0 || len(actuales) != len(b.UltimosResultados)
+	b.UltimosResultados = actuales
+	b.UltimaEjecucion = now
+
+	switch b.Frecuencia {
+	case BusquedaFrecuenciaDiaria:
+		if len(nuevos) > 0 {
+			b.PendientesResumen = unirIDs(b.PendientesResumen, nuevos)
+		}
+		if len(b.PendientesResumen) > 0 && now.Hour() >= horaResumen && !mismoDia(b.UltimoEnvio, now) {
+			if notificarBusquedaGuardada(*b, b.PendientesResumen, "Resumen diario: "+b.Nombre, plantillaBusquedaResumen) {
+				b.PendientesResumen = nil
+				b.UltimoEnvio = now
+			}
+			cambio = true
+		}
+	default:
+		// Los nuevos ya quedan en la línea base: si la alerta falla se conservan como pendientes y
+		// se reintentan junto con los de la siguiente ejecución.
+		pendientes := unirIDs(b.PendientesResumen, nuevos)
+		if len(pendientes) == 0 {
+			break
+		}
+		if notificarBusquedaGuardada(*b, pendientes, "Nuevos resultados: "+b.Nombre, plantillaBusquedaAlerta) {
+			b.PendientesResumen = nil
+			b.UltimoEnvio = now
+		} else {
+			b.PendientesResumen = pendientes
+		}
+		cambio = true
+	}
+	return cambio
+}
+
+// notificarBusquedaGuardada envía al dueño las coincidencias nuevas; retorna false si el envío falló.
+func notificarBusquedaGuardada(b busquedaGuardada, ids []int64, asunto, plantilla string) bool {
+	items := make([]map[string]interface{}, 0, maxItemsAlertaBusqueda)
+	for _, d := range b.indice().documentos(ids) {
+		if len(items) == maxItemsAlertaBusqueda {
+			break
+		}
+		items = append(items, itemAlertaBusqueda(b.Tipo, d))
+	}
+	if len(items) == 0 {
+		return true
+	}
+	data := map[string]interface{}{
+		"busqueda_id":  b.ID,
+		"nombre":       b.Nombre,
+		"tipo":         b.Tipo,
+		"total_nuevos": len(ids),
+		"items":        items,
+	}
+	if err := internalhelpers.Notificaciones.Send(nil, b.TerceroID, asunto, plantilla, data); err != nil {
+		logs.Warn("búsqueda guardada", b.ID, "tercero", b.TerceroID, ":", err)
+		return false
+	}
+	return true
+}
+
+func itemAlertaBusqueda(tipo string, d *docBusqueda) map[string]interface{} {
+	if tipo == BusquedaTipoEstudiantes {
+		return map[string]interface{}{
+			"perfil_id":                  d.Perfil.PerfilID,
+			"resumen":                    d.Perfil.Resumen,
+			"proyecto_curricular_nombre": d.Perfil.ProyectoCurricularNombre,
+			"habilidades":                d.Perfil.Habilidades,
+		}
+	}
+	return map[string]interface{}{
+		"oferta_id": d.Oferta.Id,
+		"titulo":    d.Oferta.Titulo,
+		"modalidad": d.Modalidad,
+	}
+}
+
+func unirIDs(base, extra []int64) []int64 {
+	vistos := make(map[int64]struct{}, len(base)+len(extra))
+	out := make([]int64, 0, len(base)+len(extra))
+	for _, lista := range [][]int64{base, extra} {
+		for _, id := range lista {
+			if _, ok := vistos[id]; ok {
+				continue
+			}
+			vistos[id] = struct{}{}
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func mismoDia(a, b time.Time) bool {
+	if a.IsZero() {
+		return false
+	}
+	a, b = a.Local(), b.Local()
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
@@ -0,0 +1,441 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	"github.com/udistrital/pasantia_mid/models"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	busquedaGuardadaResource = "busqueda_guardada"
+
+	// BusquedaTipoEstudiantes son búsquedas de tutores sobre el catálogo de perfiles.
+	BusquedaTipoEstudiantes = "ESTUDIANTES"
+	// BusquedaTipoOfertas son búsquedas de estudiantes sobre las ofertas abiertas.
+	BusquedaTipoOfertas = "OFERTAS"
+
+	BusquedaFrecuenciaInstantanea = "INSTANTANEA"
+	BusquedaFrecuenciaDiaria      = "DIARIA"
+
+	maxBusquedasPorTercero    = 20
+	maxNombreBusquedaGuardada = 120
+)
+
+// busquedaGuardada es la representación interna del registro busqueda_guardada del CRUD.
+// UltimosResultados guarda los IDs que coincidían en la última ejecución para detectar los nuevos,
+// y PendientesResumen los nuevos aún no enviados: los que esperan el resumen diario o los de una
+// alerta instantánea que falló.
+type busquedaGuardada struct {
+	ID                int
+	TerceroID         int
+	Rol               string
+	Tipo              string
+	Nombre            string
+	Filtros           internaldto.BusquedaGuardadaFiltros
+	Frecuencia        string
+	Activa            bool
+	UltimosResultados []int64
+	PendientesResumen []int64
+	UltimaEjecucion   time.Time
+	UltimoEnvio       time.Time
+	FechaCreacion     string
+}
+
+// ListarBusquedasGuardadas retorna las búsquedas guardadas del tutor o estudiante.
+func ListarBusquedasGuardadas(tutorID, estudianteID int) ([]internaldto.BusquedaGuardada, error) {
+	terceroID, rol, err := propietarioBusqueda(tutorID, estudianteID)
+	if err != nil {
+		return nil, err
+	}
+	busquedas, err := listBusquedasGuardadasCRUD("TerceroId:" + strconv.Itoa(terceroID) + ",Rol:" + rol)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(busquedas, func(i, j int) bool { return busquedas[i].ID > busquedas[j].ID })
+	out := make([]internaldto.BusquedaGuardada, 0, len(busquedas))
+	for _, b := range busquedas {
+		out = append(out, b.toDTO())
+	}
+	return out, nil
+}
+
+// CrearBusquedaGuardada registra una búsqueda con alertas. Los resultados actuales quedan como
+// línea base para que solo se notifiquen las coincidencias que aparezcan después.
+func CrearBusquedaGuardada(tutorID, estudianteID int, payload internaldto.BusquedaGuardadaUpsert) (internaldto.BusquedaGuardada, error) {
+	terceroID, rol, err := propietarioBusqueda(tutorID, estudianteID)
+	if err != nil {
+		return internaldto.BusquedaGuardada{}, err
+	}
+	existentes, err := listBusquedasGuardadasCRUD("TerceroId:" + strconv.Itoa(terceroID) + ",Rol:" + rol)
+	if err != nil {
+		return internaldto.BusquedaGuardada{}, err
+	}
+	if len(existentes) >= maxBusquedasPorTercero {
+		return internaldto.BusquedaGuardada{}, helpers.NewAppError(http.StatusConflict, "se alcanzó el máximo de "+strconv.Itoa(maxBusquedasPorTercero)+" búsquedas guardadas", nil)
+	}
+
+	b := busquedaGuardada{
+		TerceroID:     terceroID,
+		Rol:           rol,
+		Tipo:          BusquedaTipoOfertas,
+		Frecuencia:    BusquedaFrecuenciaInstantanea,
+		Activa:        true,
+		FechaCreacion: nowISO(),
+	}
+	if rol == rolTutor {
+		b.Tipo = BusquedaTipoEstudiantes
+	}
+	if payload.Filtros == nil {
+		return internaldto.BusquedaGuardada{}, helpers.NewAppError(http.StatusBadRequest, "filtros requeridos", nil)
+	}
+	if err := b.aplicar(payload); err != nil {
+		return internaldto.BusquedaGuardada{}, err
+	}
+	b.fijarLineaBase(time.Now())
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, busquedaGuardadaResource)
+	var raw map[string]interface{}
+	if err := helpers.DoJSON("POST", endpoint, b.toCRUD(), &raw, cfg.RequestTimeout); err != nil {
+		return internaldto.BusquedaGuardada{}, helpers.AsAppError(err, "error guardando búsqueda")
+	}
+	if id, ok := normalizeToInt(raw["Id"]); ok {
+		b.ID = id
+	}
+	return b.toDTO(), nil
+}
+
+// GetBusquedaGuardada retorna una búsqueda guardada de su dueño.
+func GetBusquedaGuardada(id, tutorID, estudianteID int) (internaldto.BusquedaGuardada, error) {
+	b, err := autorizarBusquedaGuardada(id, tutorID, estudianteID)
+	if err != nil {
+		return internaldto.BusquedaGuardada{}, err
+	}
+	return b.toDTO(), nil
+}
+
+// ActualizarBusquedaGuardada cambia nombre, filtros, frecuencia o estado de la búsqueda. Si cambian
+// los filtros o se reactiva, se recalcula la línea base y se descartan los pendientes del resumen.
+func ActualizarBusquedaGuardada(id, tutorID, estudianteID int, payload internaldto.BusquedaGuardadaUpsert) (internaldto.BusquedaGuardada, error) {
+	b, err := autorizarBusquedaGuardada(id, tutorID, estudianteID)
+	if err != nil {
+		return internaldto.BusquedaGuardada{}, err
+	}
+	if strings.TrimSpace(payload.Nombre) == "" {
+		payload.Nombre = b.Nombre
+	}
+	if strings.TrimSpace(payload.Frecuencia) == "" {
+		payload.Frecuencia = b.Frecuencia
+	}
+	filtrosPrevios, activaPrevia := b.Filtros, b.Activa
+	if err := b.aplicar(payload); err != nil {
+		return internaldto.BusquedaGuardada{}, err
+	}
+	if b.Filtros != filtrosPrevios || (b.Activa && !activaPrevia) {
+		b.fijarLineaBase(time.Now())
+	}
+	if err := updateBusquedaGuardadaCRUD(b); err != nil {
+		return internaldto.BusquedaGuardada{}, err
+	}
+	return b.toDTO(), nil
+}
+
+// EliminarBusquedaGuardada borra la búsqueda y sus alertas.
+func EliminarBusquedaGuardada(id, tutorID, estudianteID int) error {
+	b, err := autorizarBusquedaGuardada(id, tutorID, estudianteID)
+	if err != nil {
+		return err
+	}
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, busquedaGuardadaResource, strconv.Itoa(b.ID))
+	var out interface{}
+	if err := helpers.DoJSON("DELETE", endpoint, nil, &out, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil
+		}
+		return helpers.AsAppError(err, "error eliminando búsqueda guardada")
+	}
+	return nil
+}
+
+// ResultadosBusquedaGuardada ejecuta la búsqueda guardada sobre el índice local.
+func ResultadosBusquedaGuardada(id, tutorID, estudianteID, page, size int) (map[string]interface{}, error) {
+	b, err := autorizarBusquedaGuardada(id, tutorID, estudianteID)
+	if err != nil {
+		return nil, err
+	}
+	f := b.filtrosBusqueda()
+	f.Page, f.Size = page, size
+	if b.Tipo == BusquedaTipoEstudiantes {
+		return BuscarEstudiantes(f)
+	}
+	return BuscarOfertas(f)
+}
+
+// propietarioBusqueda resuelve el dueño de las búsquedas: los tutores guardan búsquedas de
+// perfiles y los estudiantes búsquedas de ofertas.
+func propietarioBusqueda(tutorID, estudianteID int) (int, string, error) {
+	switch {
+	case tutorID > 0 && estudianteID > 0:
+		return 0, "", helpers.NewAppError(http.StatusBadRequest, "indica tutor_id o estudiante_id, no ambos", nil)
+	case tutorID > 0:
+		return tutorID, rolTutor, nil
+	case estudianteID > 0:
+		return estudianteID, rolEstudiante, nil
+	default:
+		return 0, "", helpers.NewAppError(http.StatusBadRequest, "tutor_id o estudiante_id requerido", nil)
+	}
+}
+
+func autorizarBusquedaGuardada(id, tutorID, estudianteID int) (busquedaGuardada, error) {
+	if id <= 0 {
+		return busquedaGuardada{}, helpers.NewAppError(http.StatusBadRequest, "id inválido", nil)
+	}
+	terceroID, rol, err := propietarioBusqueda(tutorID, estudianteID)
+	if err != nil {
+		return busquedaGuardada{}, err
+	}
+	b, err := getBusquedaGuardadaCRUD(id)
+	if err != nil {
+		return busquedaGuardada{}, err
+	}
+	if b.TerceroID != terceroID || b.Rol != rol {
+		return busquedaGuardada{}, helpers.NewAppError(http.StatusForbidden, "no autorizado para esta búsqueda", nil)
+	}
+	return b, nil
+}
+
+// aplicar valida el cuerpo y lo copia sobre la búsqueda; los filtros ausentes conservan los actuales.
+func (b *busquedaGuardada) aplicar(payload internaldto.BusquedaGuardadaUpsert) error {
+	nombre := strings.TrimSpace(payload.Nombre)
+	if nombre == "" {
+		return helpers.NewAppError(http.StatusBadRequest, "nombre requerido", nil)
+	}
+	if len([]rune(nombre)) > maxNombreBusquedaGuardada {
+		return helpers.NewAppError(http.StatusBadRequest, "nombre supera "+strconv.Itoa(maxNombreBusquedaGuardada)+" caracteres", nil)
+	}
+	frecuencia := strings.ToUpper(strings.TrimSpace(payload.Frecuencia))
+	if frecuencia == "" {
+		frecuencia = BusquedaFrecuenciaInstantanea
+	}
+	if frecuencia != BusquedaFrecuenciaInstantanea && frecuencia != BusquedaFrecuenciaDiaria {
+		return helpers.NewAppError(http.StatusBadRequest, "frecuencia inválida (INSTANTANEA o DIARIA)", nil)
+	}
+
+	if payload.Filtros != nil {
+		f := *payload.Filtros
+		f.Query = strings.TrimSpace(f.Query)
+		f.Modalidad = strings.ToUpper(strings.TrimSpace(f.Modalidad))
+		f.Skills = canonizarTerminosHabilidad(f.Skills)
+		if f.PCID < 0 || f.FacultadID < 0 {
+			return helpers.NewAppError(http.StatusBadRequest, "filtros inválidos", nil)
+		}
+		if f.Query == "" && f.PCID == 0 && f.FacultadID == 0 && f.Modalidad == "" && f.Skills == "" {
+			return helpers.NewAppError(http.StatusBadRequest, "la búsqueda debe tener al menos un filtro", nil)
+		}
+		b.Filtros = f
+	}
+
+	b.Nombre = nombre
+	b.Frecuencia = frecuencia
+	if payload.Activa != nil {
+		b.Activa = *payload.Activa
+	}
+	return nil
+}
+
+func (b busquedaGuardada) filtrosBusqueda() FiltrosBusqueda {
+	return FiltrosBusqueda{
+		Query:       b.Filtros.Query,
+		PCID:        b.Filtros.PCID,
+		FacultadID:  b.Filtros.FacultadID,
+		Modalidad:   b.Filtros.Modalidad,
+		Habilidades: b.Filtros.Skills,
+	}
+}
+
+// indice retorna el índice sobre el que se ejecuta la búsqueda según su tipo.
+func (b busquedaGuardada) indice() *indiceBusqueda {
+	if b.Tipo == BusquedaTipoEstudiantes {
+		return indicePerfiles
+	}
+	return indiceOfertas
+}
+
+// ejecutar retorna los IDs que coinciden con la búsqueda ordenados por relevancia.
+// Las búsquedas de ofertas solo consideran ofertas abiertas.
+func (b busquedaGuardada) ejecutar() []int64 {
+	var estados []string
+	if b.Tipo == BusquedaTipoOfertas {
+		estados = []string{models.OfertaEstadoCreada}
+	}
+	resultados, _ := b.indice().buscar(b.Filtros.Query, b.filtrosBusqueda().filtro(estados))
+	ids := make([]int64, 0, len(resultados))
+	for _, r := range resultados {
+		ids = append(ids, r.Doc.ID)
+	}
+	return ids
+}
+
+// fijarLineaBase toma los resultados actuales como ya conocidos. Si el índice aún no está listo la
+// línea base queda pendiente y la fija la primera ejecución del proceso de alertas.
+func (b *busquedaGuardada) fijarLineaBase(now time.Time) {
+	b.PendientesResumen = nil
+	if !b.indice().estaListo() {
+		b.UltimosResultados = nil
+		b.UltimaEjecucion = time.Time{}
+		return
+	}
+	b.UltimosResultados = b.ejecutar()
+	b.UltimaEjecucion = now
+}
+
+func getBusquedaGuardadaCRUD(id int) (busquedaGuardada, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, busquedaGuardadaResource, strconv.Itoa(id))
+	var raw map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint, nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return busquedaGuardada{}, helpers.NewAppError(http.StatusNotFound, "búsqueda guardada no encontrada", err)
+		}
+		return busquedaGuardada{}, helpers.AsAppError(err, "error consultando búsqueda guardada")
+	}
+	b := busquedaGuardadaFromCRUD(raw)
+	if b.ID == 0 {
+		return busquedaGuardada{}, helpers.NewAppError(http.StatusNotFound, "búsqueda guardada no encontrada", nil)
+	}
+	return b, nil
+}
+
+func listBusquedasGuardadasCRUD(query string) ([]busquedaGuardada, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, busquedaGuardadaResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+	values.Set("query", query)
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando búsquedas guardadas")
+	}
+	out := make([]busquedaGuardada, 0, len(raw))
+	for _, r := range raw {
+		if b := busquedaGuardadaFromCRUD(r); b.ID > 0 {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func updateBusquedaGuardadaCRUD(b busquedaGuardada) error {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, busquedaGuardadaResource, strconv.Itoa(b.ID))
+	body := b.toCRUD()
+	body["Id"] = b.ID
+	var out map[string]interface{}
+	if err := helpers.DoJSON("PUT", endpoint, body, &out, cfg.RequestTimeout); err != nil {
+		return helpers.AsAppError(err, "error actualizando búsqueda guardada")
+	}
+	return nil
+}
+
+func busquedaGuardadaFromCRUD(raw map[string]interface{}) busquedaGuardada {
+	var b busquedaGuardada
+	if len(raw) == 0 {
+		return b
+	}
+	b.ID, _ = normalizeToInt(raw["Id"])
+	b.TerceroID, _ = normalizeToInt(raw["TerceroId"])
+	b.Rol = strings.ToUpper(strings.TrimSpace(normalizeToString(raw["Rol"])))
+	b.Tipo = strings.ToUpper(strings.TrimSpace(normalizeToString(raw["Tipo"])))
+	b.Nombre = strings.TrimSpace(normalizeToString(raw["Nombre"]))
+	b.Frecuencia = strings.ToUpper(strings.TrimSpace(normalizeToString(raw["Frecuencia"])))
+	b.Activa = normalizeToBool(raw["Activa"], true)
+	b.UltimaEjecucion = parseTime(normalizeToString(raw["FechaUltimaEjecucion"]))
+	b.UltimoEnvio = parseTime(normalizeToString(raw["FechaUltimoEnvio"]))
+	b.FechaCreacion = strings.TrimSpace(normalizeToString(raw["FechaCreacion"]))
+	if s := strings.TrimSpace(normalizeToString(raw["Filtros"])); s != "" {
+		_ = json.Unmarshal([]byte(s), &b.Filtros)
+	}
+	if s := strings.TrimSpace(normalizeToString(raw["UltimosResultados"])); s != "" {
+		_ = json.Unmarshal([]byte(s), &b.UltimosResultados)
+	}
+	if s := strings.TrimSpace(normalizeToString(raw["PendientesResumen"])); s != "" {
+		_ = json.Unmarshal([]byte(s), &b.PendientesResumen)
+	}
+	return b
+}
+
+func (b busquedaGuardada) toCRUD() map[string]interface{} {
+	filtros, _ := json.Marshal(b.Filtros)
+	ultimos, _ := json.Marshal(idsOVacio(b.UltimosResultados))
+	pendientes, _ := json.Marshal(idsOVacio(b.PendientesResumen))
+	body := map[string]interface{}{
+		"TerceroId":            b.TerceroID,
+		"Rol":                  b.Rol,
+		"Tipo":                 b.Tipo,
+		"Nombre":               b.Nombre,
+		"Filtros":              string(filtros),
+		"Frecuencia":           b.Frecuencia,
+		"Activa":               b.Activa,
+		"UltimosResultados":    string(ultimos),
+		"PendientesResumen":    string(pendientes),
+		"FechaUltimaEjecucion": nil,
+		"FechaUltimoEnvio":     nil,
+		"FechaModificacion":    nowISO(),
+	}
+	if b.FechaCreacion != "" {
+		body["FechaCreacion"] = b.FechaCreacion
+	}
+	if !b.UltimaEjecucion.IsZero() {
+		body["FechaUltimaEjecucion"] = b.UltimaEjecucion.UTC().Format(time.RFC3339)
+	}
+	if !b.UltimoEnvio.IsZero() {
+		body["FechaUltimoEnvio"] = b.UltimoEnvio.UTC().Format(time.RFC3339)
+	}
+	return body
+}
+
+func (b busquedaGuardada) toDTO() internaldto.BusquedaGuardada {
+	out := internaldto.BusquedaGuardada{
+		ID:              b.ID,
+		Nombre:          b.Nombre,
+		Tipo:            b.Tipo,
+		Filtros:         b.Filtros,
+		Frecuencia:      b.Frecuencia,
+		Activa:          b.Activa,
+		TotalResultados: len(b.UltimosResultados),
+		Pendientes:      len(b.PendientesResumen),
+	}
+	if !b.UltimaEjecucion.IsZero() {
+		t := b.UltimaEjecucion
+		out.UltimaEjecucion = &t
+	}
+	if !b.UltimoEnvio.IsZero() {
+		t := b.UltimoEnvio
+		out.UltimoEnvio = &t
+	}
+	if t := parseTime(b.FechaCreacion); !t.IsZero() {
+		out.FechaCreacion = &t
+	}
+	return out
+}
+
+func idsOVacio(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+	return ids
+}
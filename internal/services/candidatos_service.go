@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	"github.com/udistrital/pasantia_mid/internal/clients"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	"github.com/udistrital/pasantia_mid/models"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+// CandidatoSugerido es un perfil de estudiante puntuado contra una oferta.
+type CandidatoSugerido struct {
+	internaldto.EstudiantePerfilCard
+	PuntajeMatch float64  `json:"puntaje_match"`
+	Razones      []string `json:"razones"`
+}
+
+// CandidatosSugeridos ordena los perfiles visibles y con tratamiento de datos aceptado por afinidad
+// con la oferta. Excluye a quienes ya se postularon o ya fueron invitados por el tutor.
+func CandidatosSugeridos(ctx context.Context, tutorID, ofertaID, page, size int) (internaldto.PageDTO[CandidatoSugerido], error) {
+	out := internaldto.PageDTO[CandidatoSugerido]{Items: []CandidatoSugerido{}, Page: page, Size: size}
+
+	oferta, err := autorizarOfertaEvaluacion(tutorID, ofertaID, nil)
+	if err != nil {
+		return out, err
+	}
+	pcIDs, err := getPCIDsByOferta(ofertaID)
+	if err != nil {
+		return out, helpers.NewAppError(http.StatusBadGateway, "error consultando proyectos curriculares de la oferta", err)
+	}
+
+	perfiles, err := listPerfilesVisibles()
+	if err != nil {
+		return out, err
+	}
+
+	postulados := make(map[int64]struct{})
+	list, err := clients.CastorCRUD().ListPostulaciones(ctx, map[string]string{"oferta_id": fmt.Sprint(ofertaID)})
+	if err != nil {
+		return out, helpers.AsAppError(err, "error consultando postulaciones")
+	}
+	for _, p := range list {
+		postulados[p.EstudianteId] = struct{}{}
+	}
+	invitados, err := perfilesInvitadosOferta(tutorID, int64(ofertaID))
+	if err != nil {
+		return out, err
+	}
+
+	now := time.Now()
+	candidatos := make([]CandidatoSugerido, 0, len(perfiles))
+	for _, entry := range perfiles {
+		card := mapToPerfilCardExplorar(entry, nil)
+		if !card.Visible || !card.TratamientoDatosAceptado {
+			continue
+		}
+		if _, ok := postulados[card.TerceroID]; ok {
+			continue
+		}
+		if _, ok := invitados[card.PerfilID]; ok {
+			continue
+		}
+		puntaje, razones, ok := puntuarCandidato(perfilMatchingDesdeEntry(entry), *oferta, pcIDs, parseTime(normalizeToString(entry["FechaModificacion"])), now)
+		if !ok {
+			continue
+		}
+		card.Habilidades = habilidadesPerfil(entry["Habilidades"])
+		candidatos = append(candidatos, CandidatoSugerido{EstudiantePerfilCard: card, PuntajeMatch: puntaje, Razones: razones})
+	}
+
+	sort.SliceStable(candidatos, func(i, j int) bool {
+		if candidatos[i].PuntajeMatch != candidatos[j].PuntajeMatch {
+			return candidatos[i].PuntajeMatch > candidatos[j].PuntajeMatch
+		}
+		return candidatos[i].PerfilID < candidatos[j].PerfilID
+	})
+
+	out.Total = int64(len(candidatos))
+	start := (page - 1) * size
+	if start >= len(candidatos) {
+		return out, nil
+	}
+	end := start + size
+	if end > len(candidatos) {
+		end = len(candidatos)
+	}
+	out.Items = candidatos[start:end]
+
+	pcNames := make(map[int64]string)
+	for i := range out.Items {
+		pcID := out.Items[i].ProyectoCurricularID
+		if pcID <= 0 {
+			continue
+		}
+		nombre, ok := pcNames[pcID]
+		if !ok {
+			if detalle, err := rootservices.GetProyectoCurricular(int(pcID)); err == nil && detalle != nil {
+				nombre = strings.TrimSpace(detalle.Nombre)
+			}
+			pcNames[pcID] = nombre
+		}
+		if nombre != "" {
+			out.Items[i].ProyectoCurricularNombre = nombre
+			out.Items[i].ProyectoCurricular = map[string]interface{}{"id": pcID, "nombre": nombre}
+		}
+	}
+	cards := make([]*internaldto.EstudiantePerfilCard, len(out.Items))
+	for i := range out.Items {
+		cards[i] = &out.Items[i].EstudiantePerfilCard
+	}
+	anonimizarPerfiles(ctx, tutorID, cards)
+	return out, nil
+}
+
+// puntuarCandidato calcula la afinidad (0-100) del perfil con la oferta con la misma regla que la
+// recomendación de ofertas; la recencia se mide sobre la última actualización del perfil.
+func puntuarCandidato(perfil perfilMatching, oferta models.Oferta, pcIDs []int, actualizado, now time.Time) (float64, []string, bool) {
+	return puntuarCoincidencia(perfil, oferta, pcIDs, actualizado, now, razonesParaTutor)
+}
+
+func listPerfilesVisibles() ([]map[string]interface{}, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, perfilResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+	query := "Visible:true,TratamientoDatosAceptado:true"
+	if version := versionConsentimientoVigente(); version != "" {
+		query += ",ConsentimientoVersion:" + version
+	}
+	values.Set("query", query)
+
+	var records []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &records, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando perfiles")
+	}
+	return records, nil
+}
+
+// perfilesInvitadosOferta retorna los perfiles que el tutor ya invitó a la oferta.
+func perfilesInvitadosOferta(tutorID int, ofertaID int64) (map[int64]struct{}, error) {
+	set := make(map[int64]struct{})
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, invitacionesResource)
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSONWithHeaders(
+		"GET",
+		endpoint,
+		map[string]string{"X-Tutor-Id": fmt.Sprint(tutorID)},
+		nil,
+		&raw,
+		cfg.RequestTimeout,
+		true,
+	); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return set, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando invitaciones")
+	}
+	for _, it := range raw {
+		inv := normalizeInvitacion(it)
+		if extractOfertaID(inv) != ofertaID {
+			continue
+		}
+		if perfilID, ok := normalizeToInt64(inv["perfil_estudiante_id"]); ok && perfilID > 0 {
+			set[perfilID] = struct{}{}
+		}
+	}
+	return set, nil
+}
+
+func perfilMatchingDesdeEntry(entry map[string]interface{}) perfilMatching {
+	pcID, _ := normalizeToInt(entry["ProyectoCurricularId"])
+	return perfilMatching{
+		PCID:        pcID,
+		Habilidades: habilidadesPerfil(entry["Habilidades"]),
+		Modalidad:   normalizarTexto(normalizeToString(entry["ModalidadPreferida"])),
+		Ciudad:      normalizarTexto(normalizeToString(entry["CiudadPreferida"])),
+	}
+}
+
+// habilidadesPerfil acepta las habilidades tal como las devuelve el CRUD (texto o arreglo).
+func habilidadesPerfil(v interface{}) []string {
+	if s, ok := v.(string); ok {
+		return extraerHabilidades(s)
+	}
+	return parseHabilidades(v)
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// colaJSON es una cola local de elementos con id consecutivo: en memoria o, con ruta, persistida
+// en un archivo JSON que se reescribe de forma atómica en cada cambio. La usan el outbox y los
+// resúmenes de notificaciones. Es local a la instancia: no se comparte entre réplicas.
+type colaJSON[T any] struct {
+	mu        sync.Mutex
+	ruta      string
+	siguiente int64
+	items     map[int64]T
+	// id da acceso al campo id del elemento.
+	id func(*T) *int64
+}
+
+type colaJSONArchivo[T any] struct {
+	Siguiente int64 `json:"siguiente"`
+	Items     []T   `json:"items"`
+}
+
+func newColaJSONMemoria[T any](id func(*T) *int64) *colaJSON[T] {
+	return &colaJSON[T]{items: map[int64]T{}, id: id}
+}
+
+// newColaJSONArchivo carga la cola desde la ruta o la crea si el archivo no existe.
+func newColaJSONArchivo[T any](ruta string, id func(*T) *int64) (*colaJSON[T], error) {
+	c := newColaJSONMemoria(id)
+	c.ruta = ruta
+	contenido, err := os.ReadFile(ruta)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, c.persistir()
+	}
+	if err != nil {
+		return nil, err
+	}
+	var archivo colaJSONArchivo[T]
+	if err := json.Unmarshal(contenido, &archivo); err != nil {
+		return nil, err
+	}
+	c.siguiente = archivo.Siguiente
+	for i := range archivo.Items {
+		v := archivo.Items[i]
+		n := *id(&v)
+		c.items[n] = v
+		if n > c.siguiente {
+			c.siguiente = n
+		}
+	}
+	return c, nil
+}
+
+// Agregar asigna el siguiente id a v y lo guarda.
+func (c *colaJSON[T]) Agregar(v *T) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.siguiente++
+	n := c.siguiente
+	*c.id(v) = n
+	c.items[n] = *v
+	if err := c.persistir(); err != nil {
+		delete(c.items, n)
+		return err
+	}
+	return nil
+}
+
+func (c *colaJSON[T]) Obtener(id int64) (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.items[id]
+	return v, ok
+}
+
+// Reemplazar guarda v sobre el elemento con su mismo id; retorna false si no existe.
+func (c *colaJSON[T]) Reemplazar(v T) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := *c.id(&v)
+	anterior, ok := c.items[n]
+	if !ok {
+		return false, nil
+	}
+	c.items[n] = v
+	if err := c.persistir(); err != nil {
+		c.items[n] = anterior
+		return true, err
+	}
+	return true, nil
+}
+
+// Filtrar retorna los elementos que cumplen fn (todos si fn es nil), ordenados por id.
+func (c *colaJSON[T]) Filtrar(fn func(T) bool) []T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ordenados(fn)
+}
+
+// Eliminar borra los elementos que cumplen fn y retorna cuántos borró.
+func (c *colaJSON[T]) Eliminar(fn func(T) bool) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for id, v := range c.items {
+		if fn(v) {
+			delete(c.items, id)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, c.persistir()
+}
+
+func (c *colaJSON[T]) ordenados(fn func(T) bool) []T {
+	ids := make([]int64, 0, len(c.items))
+	for id, v := range c.items {
+		if fn == nil || fn(v) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	out := make([]T, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, c.items[id])
+	}
+	return out
+}
+
+// persistir escribe la cola en el archivo; se llama con el candado tomado.
+func (c *colaJSON[T]) persistir() error {
+	if c.ruta == "" {
+		return nil
+	}
+	return escribirJSONAtomico(c.ruta, colaJSONArchivo[T]{Siguiente: c.siguiente, Items: c.ordenados(nil)})
+}
+
+// escribirJSONAtomico guarda v en un archivo temporal y lo renombra sobre la ruta, para no dejar
+// el archivo a medio escribir si el proceso se detiene.
+func escribirJSONAtomico(ruta string, v interface{}) error {
+	contenido, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(ruta); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp := ruta + ".tmp"
+	if err := os.WriteFile(tmp, contenido, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, ruta)
+}
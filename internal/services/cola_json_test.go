@@ -0,0 +1,101 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type elementoCola struct {
+	ID    int64  `json:"id"`
+	Valor string `json:"valor"`
+}
+
+func idElementoCola(e *elementoCola) *int64 { return &e.ID }
+
+func TestColaJSONArchivo(t *testing.T) {
+	ruta := filepath.Join(t.TempDir(), "sub", "cola.json")
+
+	cola, err := newColaJSONArchivo(ruta, idElementoCola)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(ruta); err != nil {
+		t.Fatalf("no se creó el archivo: %v", err)
+	}
+	for _, v := range []string{"a", "b", "c"} {
+		e := elementoCola{Valor: v}
+		if err := cola.Agregar(&e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ok, err := cola.Reemplazar(elementoCola{ID: 2, Valor: "B"}); !ok || err != nil {
+		t.Fatalf("Reemplazar = %v, %v", ok, err)
+	}
+	if ok, _ := cola.Reemplazar(elementoCola{ID: 99}); ok {
+		t.Error("Reemplazar aceptó un id inexistente")
+	}
+	if n, err := cola.Eliminar(func(e elementoCola) bool { return e.ID == 3 }); n != 1 || err != nil {
+		t.Fatalf("Eliminar = %d, %v", n, err)
+	}
+
+	// Al recargar se conservan los elementos y el contador: el id 3 eliminado no se reutiliza.
+	recargada, err := newColaJSONArchivo(ruta, idElementoCola)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := recargada.Filtrar(nil)
+	if len(got) != 2 || got[0] != (elementoCola{ID: 1, Valor: "a"}) || got[1] != (elementoCola{ID: 2, Valor: "B"}) {
+		t.Fatalf("elementos recargados = %v", got)
+	}
+	e := elementoCola{Valor: "d"}
+	if err := recargada.Agregar(&e); err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != 4 {
+		t.Errorf("id asignado = %d, se esperaba 4", e.ID)
+	}
+}
+
+func TestColaJSONArchivoInvalido(t *testing.T) {
+	ruta := filepath.Join(t.TempDir(), "cola.json")
+	if err := os.WriteFile(ruta, []byte("{no es json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newColaJSONArchivo(ruta, idElementoCola); err == nil {
+		t.Error("se aceptó un archivo corrupto")
+	}
+}
+
+func TestColaJSONFiltrar(t *testing.T) {
+	cola := newColaJSONMemoria(idElementoCola)
+	for _, v := range []string{"x", "y", "x", "z", "x"} {
+		e := elementoCola{Valor: v}
+		if err := cola.Agregar(&e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		nombre string
+		fn     func(elementoCola) bool
+		want   []int64
+	}{
+		{nombre: "sin filtro retorna todo ordenado", want: []int64{1, 2, 3, 4, 5}},
+		{nombre: "filtra por valor", fn: func(e elementoCola) bool { return e.Valor == "x" }, want: []int64{1, 3, 5}},
+		{nombre: "sin coincidencias", fn: func(e elementoCola) bool { return e.Valor == "w" }, want: []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			got := cola.Filtrar(tt.fn)
+			if len(got) != len(tt.want) {
+				t.Fatalf("%d elementos, se esperaban %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].ID != tt.want[i] {
+					t.Errorf("posición %d: id %d, se esperaba %d", i, got[i].ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
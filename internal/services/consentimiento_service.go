@@ -0,0 +1,267 @@
+package services
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	beego "github.com/beego/beego/v2/server/web"
+	"github.com/beego/beego/v2/server/web/context"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	consentimientoResource = "consentimiento_tratamiento_datos"
+
+	// ConsentimientoAccionAceptacion registra la aceptación de una versión de los términos.
+	ConsentimientoAccionAceptacion = "ACEPTACION"
+	// ConsentimientoAccionRevocacion registra el retiro del consentimiento (Ley 1581 de 2012).
+	ConsentimientoAccionRevocacion = "REVOCACION"
+
+	maxMotivoRevocacion = 500
+)
+
+// versionConsentimientoVigente retorna la versión vigente de los términos de tratamiento de datos.
+// Vacía conserva el comportamiento anterior: basta con TratamientoDatosAceptado.
+func versionConsentimientoVigente() string {
+	return strings.TrimSpace(internalhelpers.Env("CONSENTIMIENTO_VERSION", beego.AppConfig.DefaultString("consentimiento_version", "")))
+}
+
+func terminosConsentimientoURL() string {
+	return strings.TrimSpace(internalhelpers.Env("CONSENTIMIENTO_TERMINOS_URL", beego.AppConfig.DefaultString("consentimiento_terminos_url", "")))
+}
+
+func versionConsentimientoValida(version string) bool {
+	vigente := versionConsentimientoVigente()
+	return vigente == "" || strings.TrimSpace(version) == vigente
+}
+
+// consentimientoVigente indica si un registro de estudiante_perfil del CRUD tiene el tratamiento de
+// datos aceptado sobre la versión vigente de los términos. Acepta llaves del CRUD o ya normalizadas.
+func consentimientoVigente(entry map[string]interface{}) bool {
+	aceptado := normalizeToBool(entry["TratamientoDatosAceptado"], false) ||
+		normalizeToBool(entry["tratamiento_datos_aceptado"], false)
+	if !aceptado {
+		return false
+	}
+	version := normalizeToString(entry["ConsentimientoVersion"])
+	if version == "" {
+		version = normalizeToString(entry["consentimiento_version"])
+	}
+	return versionConsentimientoValida(version)
+}
+
+func (r perfilRecord) consentimientoVigente() bool {
+	return r.TratamientoDatosAceptado && versionConsentimientoValida(r.ConsentimientoVersion)
+}
+
+// EstadoConsentimiento retorna el consentimiento del estudiante frente a los términos vigentes.
+func EstadoConsentimiento(terceroID int) (internaldto.ConsentimientoEstado, error) {
+	record, err := findPerfil(terceroID)
+	if err != nil {
+		return internaldto.ConsentimientoEstado{}, err
+	}
+	if record == nil {
+		return internaldto.ConsentimientoEstado{}, helpers.NewAppError(http.StatusNotFound, "perfil no encontrado", nil)
+	}
+	return estadoConsentimiento(*record), nil
+}
+
+// AceptarConsentimiento registra la aceptación de la versión vigente de los términos con la IP y el
+// user agent de la petición. No cambia la visibilidad del perfil. Sin versión vigente configurada
+// la petición no debe indicar versión, de modo que la auditoría y el perfil coincidan.
+func AceptarConsentimiento(ctx *context.Context, terceroID int, version string) (internaldto.ConsentimientoEstado, error) {
+	version = strings.TrimSpace(version)
+	vigente := versionConsentimientoVigente()
+	if vigente != "" && version == "" {
+		return internaldto.ConsentimientoEstado{}, helpers.NewAppError(http.StatusBadRequest, "version es requerida", nil)
+	}
+	if version != vigente {
+		return internaldto.ConsentimientoEstado{}, helpers.NewAppError(http.StatusConflict, "la versión aceptada no corresponde a los términos vigentes", nil)
+	}
+
+	record, err := findPerfil(terceroID)
+	if err != nil {
+		return internaldto.ConsentimientoEstado{}, err
+	}
+	if record == nil {
+		return internaldto.ConsentimientoEstado{}, helpers.NewAppError(http.StatusNotFound, "perfil no encontrado", nil)
+	}
+	if record.consentimientoVigente() {
+		return estadoConsentimiento(*record), nil
+	}
+
+	if err := registrarEventoConsentimiento(ctx, terceroID, record.Id, ConsentimientoAccionAceptacion, vigente, ""); err != nil {
+		return internaldto.ConsentimientoEstado{}, err
+	}
+	aceptado := true
+	updated, err := actualizarPerfilRecord(record.Id, internaldto.EstudiantePerfilUpsert{TratamientoDatosAceptado: &aceptado})
+	if err != nil {
+		return internaldto.ConsentimientoEstado{}, err
+	}
+	return estadoConsentimiento(updated), nil
+}
+
+// RevocarConsentimiento retira el consentimiento de tratamiento de datos y oculta el perfil.
+func RevocarConsentimiento(ctx *context.Context, terceroID int, motivo string) (internaldto.ConsentimientoEstado, error) {
+	motivo = strings.TrimSpace(motivo)
+	if len([]rune(motivo)) > maxMotivoRevocacion {
+		return internaldto.ConsentimientoEstado{}, helpers.NewAppError(http.StatusBadRequest, "motivo supera 500 caracteres", nil)
+	}
+
+	record, err := findPerfil(terceroID)
+	if err != nil {
+		return internaldto.ConsentimientoEstado{}, err
+	}
+	if record == nil {
+		return internaldto.ConsentimientoEstado{}, helpers.NewAppError(http.StatusNotFound, "perfil no encontrado", nil)
+	}
+	if !record.TratamientoDatosAceptado && !record.Visible {
+		return estadoConsentimiento(*record), nil
+	}
+
+	if err := registrarEventoConsentimiento(ctx, terceroID, record.Id, ConsentimientoAccionRevocacion, record.ConsentimientoVersion, motivo); err != nil {
+		return internaldto.ConsentimientoEstado{}, err
+	}
+	revocado := false
+	updated, err := actualizarPerfilRecord(record.Id, internaldto.EstudiantePerfilUpsert{TratamientoDatosAceptado: &revocado})
+	if err != nil {
+		return internaldto.ConsentimientoEstado{}, err
+	}
+	return estadoConsentimiento(updated), nil
+}
+
+// HistorialConsentimiento retorna la auditoría de aceptaciones y revocaciones del estudiante, de la
+// más reciente a la más antigua.
+func HistorialConsentimiento(terceroID, page, size int) (internaldto.PageDTO[internaldto.ConsentimientoEvento], error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, consentimientoResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+	values.Set("query", "TerceroId:"+strconv.Itoa(terceroID))
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil &&
+		!helpers.IsHTTPError(err, http.StatusNotFound) {
+		return internaldto.PageDTO[internaldto.ConsentimientoEvento]{}, helpers.AsAppError(err, "error consultando historial de consentimiento")
+	}
+
+	eventos := make([]internaldto.ConsentimientoEvento, 0, len(raw))
+	for _, r := range raw {
+		if e := consentimientoEventoFromCRUD(r); e.ID > 0 {
+			eventos = append(eventos, e)
+		}
+	}
+	sort.SliceStable(eventos, func(i, j int) bool {
+		return eventos[i].ID > eventos[j].ID
+	})
+
+	start := (page - 1) * size
+	if start > len(eventos) {
+		start = len(eventos)
+	}
+	end := start + size
+	if end > len(eventos) {
+		end = len(eventos)
+	}
+	return internaldto.PageDTO[internaldto.ConsentimientoEvento]{
+		Items: eventos[start:end],
+		Page:  page,
+		Size:  size,
+		Total: int64(len(eventos)),
+	}, nil
+}
+
+// prepararConsentimientoPerfil audita el cambio de TratamientoDatosAceptado pedido por los endpoints
+// de perfil. Si no cambia nada frente al registro actual lo retira del payload.
+func prepararConsentimientoPerfil(ctx *context.Context, terceroID int, record *perfilRecord, payload *internaldto.EstudiantePerfilUpsert) error {
+	if payload.TratamientoDatosAceptado == nil {
+		return nil
+	}
+	perfilID := 0
+	if record != nil {
+		perfilID = record.Id
+	}
+	aceptar := *payload.TratamientoDatosAceptado
+	switch {
+	case aceptar && (record == nil || !record.consentimientoVigente()):
+		return registrarEventoConsentimiento(ctx, terceroID, perfilID, ConsentimientoAccionAceptacion, versionConsentimientoVigente(), "")
+	case !aceptar && record != nil && record.TratamientoDatosAceptado:
+		return registrarEventoConsentimiento(ctx, terceroID, perfilID, ConsentimientoAccionRevocacion, record.ConsentimientoVersion, "")
+	}
+	if record != nil {
+		payload.TratamientoDatosAceptado = nil
+	}
+	return nil
+}
+
+// sellarConsentimiento agrega al cuerpo del perfil la versión y la fecha del consentimiento.
+func sellarConsentimiento(body map[string]interface{}) {
+	body["ConsentimientoVersion"] = versionConsentimientoVigente()
+	body["FechaConsentimiento"] = nowISO()
+}
+
+// registrarEventoConsentimiento guarda la evidencia del consentimiento antes de modificar el perfil.
+func registrarEventoConsentimiento(ctx *context.Context, terceroID, perfilID int, accion, version, motivo string) error {
+	ip, userAgent := "", ""
+	if ctx != nil && ctx.Input != nil {
+		ip = ctx.Input.IP()
+		userAgent = strings.TrimSpace(ctx.Input.Header("User-Agent"))
+	}
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, consentimientoResource)
+	body := map[string]interface{}{
+		"TerceroId":     terceroID,
+		"PerfilId":      perfilID,
+		"Accion":        accion,
+		"Version":       strings.TrimSpace(version),
+		"Ip":            ip,
+		"UserAgent":     userAgent,
+		"Motivo":        motivo,
+		"FechaCreacion": nowISO(),
+	}
+	if err := helpers.DoJSON("POST", endpoint, body, nil, cfg.RequestTimeout); err != nil {
+		return helpers.AsAppError(err, "error registrando consentimiento")
+	}
+	return nil
+}
+
+func estadoConsentimiento(record perfilRecord) internaldto.ConsentimientoEstado {
+	estado := internaldto.ConsentimientoEstado{
+		TerceroID:       record.TerceroId,
+		PerfilID:        record.Id,
+		VersionVigente:  versionConsentimientoVigente(),
+		TerminosURL:     terminosConsentimientoURL(),
+		VersionAceptada: strings.TrimSpace(record.ConsentimientoVersion),
+		Aceptado:        record.TratamientoDatosAceptado,
+		Vigente:         record.consentimientoVigente(),
+	}
+	estado.RequiereAceptacion = !estado.Vigente
+	if t := parseTime(record.FechaConsentimiento); !t.IsZero() && record.TratamientoDatosAceptado {
+		estado.FechaAceptacion = &t
+	}
+	return estado
+}
+
+func consentimientoEventoFromCRUD(raw map[string]interface{}) internaldto.ConsentimientoEvento {
+	var e internaldto.ConsentimientoEvento
+	e.ID, _ = normalizeToInt(raw["Id"])
+	e.TerceroID, _ = normalizeToInt(raw["TerceroId"])
+	e.PerfilID, _ = normalizeToInt(raw["PerfilId"])
+	e.Accion = strings.ToUpper(strings.TrimSpace(normalizeToString(raw["Accion"])))
+	e.Version = strings.TrimSpace(normalizeToString(raw["Version"]))
+	e.IP = strings.TrimSpace(normalizeToString(raw["Ip"]))
+	e.UserAgent = strings.TrimSpace(normalizeToString(raw["UserAgent"]))
+	e.Motivo = strings.TrimSpace(normalizeToString(raw["Motivo"]))
+	if t := parseTime(normalizeToString(raw["FechaCreacion"])); !t.IsZero() {
+		e.Fecha = &t
+	}
+	return e
+}
@@ -0,0 +1,429 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	beegocontext "github.com/beego/beego/v2/server/web/context"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	"github.com/udistrital/pasantia_mid/internal/clients"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	"github.com/udistrital/pasantia_mid/models"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	topEmpresasCoordinacion      = 5
+	maxEstudiantesSinPostulacion = 20
+)
+
+var periodoAcademicoRe = regexp.MustCompile(`^(\d{4})-([12])$`)
+
+// GetDashboardCoordinacion agrega ofertas, postulaciones y estudiantes de los proyectos curriculares
+// del alcance (facultad o PC según la jerarquía de Oikos, limitado a la asignación del usuario)
+// dentro del periodo académico pedido.
+func GetDashboardCoordinacion(ctx *beegocontext.Context, filtro internaldto.CoordinacionDashboardFiltro) (internaldto.CoordinacionDashboard, error) {
+	desde, hasta, err := parsePeriodoAcademico(filtro.Periodo)
+	if err != nil {
+		return internaldto.CoordinacionDashboard{}, err
+	}
+	if !desde.IsZero() {
+		filtro.Desde = desde.Format("2006-01-02")
+		filtro.Hasta = hasta.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	enPeriodo := func(t time.Time) bool {
+		if desde.IsZero() {
+			return true
+		}
+		return !t.IsZero() && !t.Before(desde) && t.Before(hasta)
+	}
+
+	alcance, err := resolverAlcanceCoordinacion(ctx, filtro)
+	if err != nil {
+		return internaldto.CoordinacionDashboard{}, err
+	}
+	if alcance != nil {
+		filtro.ProyectosCurriculares = make([]int, 0, len(alcance))
+		for id := range alcance {
+			filtro.ProyectosCurriculares = append(filtro.ProyectosCurriculares, id)
+		}
+		sort.Ints(filtro.ProyectosCurriculares)
+	}
+	enAlcance := func(pcID int) bool {
+		if alcance == nil {
+			return true
+		}
+		_, ok := alcance[pcID]
+		return ok
+	}
+
+	// Ofertas del alcance publicadas en el periodo
+	todas, err := rootservices.ListOfertas(map[string]string{"limit": "0"})
+	if err != nil && !helpers.IsHTTPError(err, http.StatusNotFound) {
+		return internaldto.CoordinacionDashboard{}, helpers.AsAppError(err, "error consultando ofertas")
+	}
+	stdCtx := requestContext(ctx)
+	var pcsPorOferta map[int][]int
+	if alcance != nil {
+		ids := make([]int, 0, len(todas))
+		for _, oferta := range todas {
+			if enPeriodo(oferta.FechaPublicacion) {
+				ids = append(ids, int(oferta.Id))
+			}
+		}
+		pcsPorOferta = cargarEnParalelo(stdCtx, ids, func(_ context.Context, id int) ([]int, error) {
+			return getPCIDsByOferta(id)
+		})
+	}
+	ofertas := make(map[int64]models.Oferta)
+	for _, oferta := range todas {
+		if !enPeriodo(oferta.FechaPublicacion) {
+			continue
+		}
+		if alcance != nil {
+			pcIDs, ok := pcsPorOferta[int(oferta.Id)]
+			if !ok {
+				continue
+			}
+			incluida := false
+			for _, id := range pcIDs {
+				if enAlcance(id) {
+					incluida = true
+					break
+				}
+			}
+			if !incluida {
+				continue
+			}
+		}
+		ofertas[oferta.Id] = oferta
+	}
+
+	postulaciones, err := clients.CastorCRUD().ListPostulaciones(stdCtx, nil)
+	if err != nil && !helpers.IsHTTPError(err, http.StatusNotFound) {
+		return internaldto.CoordinacionDashboard{}, helpers.AsAppError(err, "error consultando postulaciones")
+	}
+
+	perfiles, err := listPerfilesCoordinacion()
+	if err != nil {
+		return internaldto.CoordinacionDashboard{}, err
+	}
+
+	result := internaldto.CoordinacionDashboard{
+		Filtro:       filtro,
+		TotalOfertas: len(ofertas),
+	}
+
+	// Ofertas por estado y postulantes por oferta
+	porEstado := map[string]int{}
+	porOferta := map[int64]*internaldto.OfertaPostulantes{}
+	for id, oferta := range ofertas {
+		porEstado[strings.ToUpper(strings.TrimSpace(oferta.Estado))]++
+		porOferta[id] = &internaldto.OfertaPostulantes{
+			OfertaID:  id,
+			Titulo:    strings.TrimSpace(oferta.Titulo),
+			Estado:    strings.TrimSpace(oferta.Estado),
+			EmpresaID: oferta.EmpresaId,
+		}
+	}
+
+	dias := make([]float64, 0)
+	postuladosPeriodo := map[int64]struct{}{}
+	seleccionadosPeriodo := map[int64]struct{}{}
+	for _, p := range postulaciones {
+		fecha := parseTime(p.FechaPostulacion)
+		if !enPeriodo(fecha) {
+			continue
+		}
+		seleccionada := postulacionSeleccionada(p.EstadoPostulacion)
+		if p.EstudianteId > 0 {
+			postuladosPeriodo[p.EstudianteId] = struct{}{}
+			if seleccionada {
+				seleccionadosPeriodo[p.EstudianteId] = struct{}{}
+			}
+		}
+
+		item := porOferta[p.OfertaId]
+		if item == nil {
+			continue
+		}
+		result.TotalPostulaciones++
+		item.Postulantes++
+		if seleccionada {
+			item.Seleccionados++
+			if estado := parseTime(p.FechaEstado); !estado.IsZero() && !fecha.IsZero() && !estado.Before(fecha) {
+				dias = append(dias, estado.Sub(fecha).Hours()/24)
+			}
+		}
+	}
+
+	result.OfertasPorEstado = make([]internaldto.EstadoConteo, 0, len(porEstado))
+	for code, total := range porEstado {
+		result.OfertasPorEstado = append(result.OfertasPorEstado, internaldto.EstadoConteo{
+			Codigo: code,
+			Estado: resolveParametroNombre(code, code),
+			Total:  total,
+		})
+	}
+	sort.SliceStable(result.OfertasPorEstado, func(i, j int) bool {
+		return result.OfertasPorEstado[i].Codigo < result.OfertasPorEstado[j].Codigo
+	})
+
+	result.PostulantesPorOferta = make([]internaldto.OfertaPostulantes, 0, len(porOferta))
+	for _, item := range porOferta {
+		result.PostulantesPorOferta = append(result.PostulantesPorOferta, *item)
+	}
+	sort.SliceStable(result.PostulantesPorOferta, func(i, j int) bool {
+		a, b := result.PostulantesPorOferta[i], result.PostulantesPorOferta[j]
+		if a.Postulantes != b.Postulantes {
+			return a.Postulantes > b.Postulantes
+		}
+		return a.OfertaID < b.OfertaID
+	})
+	if len(ofertas) > 0 {
+		result.PromedioPostulantes = redondear1(float64(result.TotalPostulaciones) / float64(len(ofertas)))
+	}
+	result.TiempoSeleccion = tiempoSeleccion(dias)
+	result.TopEmpresas = topEmpresasCoordinacionResumen(ctx, result.PostulantesPorOferta)
+
+	// Colocación y estudiantes sin postulación (estudiantes de los PC del alcance)
+	sinPostulacion := make([]internaldto.EstudianteSinPostulacion, 0)
+	for _, perfil := range perfiles {
+		if !enAlcance(perfil.ProyectoCurricularId) || perfil.TerceroId <= 0 {
+			continue
+		}
+		result.Colocacion.Estudiantes++
+		tercero := int64(perfil.TerceroId)
+		if _, ok := postuladosPeriodo[tercero]; ok {
+			result.Colocacion.Postulados++
+		} else {
+			sinPostulacion = append(sinPostulacion, internaldto.EstudianteSinPostulacion{
+				TerceroID:            perfil.TerceroId,
+				PerfilID:             perfil.Id,
+				ProyectoCurricularID: perfil.ProyectoCurricularId,
+			})
+		}
+		if _, ok := seleccionadosPeriodo[tercero]; ok {
+			result.Colocacion.Seleccionados++
+		}
+	}
+	if result.Colocacion.Estudiantes > 0 {
+		result.Colocacion.Tasa = redondear1(float64(result.Colocacion.Seleccionados) * 100 / float64(result.Colocacion.Estudiantes))
+	}
+
+	sort.SliceStable(sinPostulacion, func(i, j int) bool {
+		return sinPostulacion[i].TerceroID < sinPostulacion[j].TerceroID
+	})
+	result.EstudiantesSinPostulacion.Total = len(sinPostulacion)
+	if len(sinPostulacion) > maxEstudiantesSinPostulacion {
+		sinPostulacion = sinPostulacion[:maxEstudiantesSinPostulacion]
+	}
+	terceros := make([]int, 0, len(sinPostulacion))
+	for _, e := range sinPostulacion {
+		terceros = append(terceros, e.TerceroID)
+	}
+	nombres := cargarEnParalelo(stdCtx, terceros, func(ctx context.Context, id int) (string, error) {
+		return strings.TrimSpace(NombreCompletoPorIDCoreStd(ctx, id)), nil
+	})
+	for i := range sinPostulacion {
+		sinPostulacion[i].Nombre = nombres[sinPostulacion[i].TerceroID]
+	}
+	result.EstudiantesSinPostulacion.Items = sinPostulacion
+
+	return result, nil
+}
+
+// parsePeriodoAcademico convierte YYYY-1 (enero a junio) o YYYY-2 (julio a diciembre) en el rango
+// [desde, hasta). Vacío no filtra por fechas.
+func parsePeriodoAcademico(raw string) (time.Time, time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, time.Time{}, nil
+	}
+	m := periodoAcademicoRe.FindStringSubmatch(raw)
+	if m == nil {
+		return time.Time{}, time.Time{}, helpers.NewAppError(http.StatusBadRequest, "periodo inválido (YYYY-1 o YYYY-2)", nil)
+	}
+	anio, _ := strconv.Atoi(m[1])
+	mes := time.January
+	if m[2] == "2" {
+		mes = time.July
+	}
+	desde := time.Date(anio, mes, 1, 0, 0, 0, 0, time.Local)
+	return desde, desde.AddDate(0, 6, 0), nil
+}
+
+// resolverAlcanceCoordinacion retorna los PC del alcance, siempre dentro de la asignación del
+// usuario: los PC de sus facultades y sus PC asignados. Sin filtros el alcance es toda la
+// asignación; un filtro fuera de ella es 403.
+func resolverAlcanceCoordinacion(ctx *beegocontext.Context, filtro internaldto.CoordinacionDashboardFiltro) (map[int]struct{}, error) {
+	facultadID, pcID := filtro.FacultadID, filtro.ProyectoCurricularID
+	permitidos, err := pcsAsignados(ctx, filtro.FacultadesAsignadas, filtro.ProyectosAsignados)
+	if err != nil {
+		return nil, err
+	}
+	facultadAsignada := false
+	for _, id := range filtro.FacultadesAsignadas {
+		facultadAsignada = facultadAsignada || id == facultadID
+	}
+
+	alcance := permitidos
+	if facultadID > 0 {
+		if !facultadAsignada {
+			return nil, helpers.NewAppError(http.StatusForbidden, "la facultad no está dentro de la asignación del usuario", nil)
+		}
+		pcs, err := pcsFacultad(ctx, facultadID)
+		if err != nil {
+			return nil, err
+		}
+		alcance = pcs
+	}
+	if pcID > 0 {
+		if _, ok := permitidos[pcID]; !ok {
+			return nil, helpers.NewAppError(http.StatusForbidden, "el proyecto curricular no está dentro de la asignación del usuario", nil)
+		}
+		if _, ok := alcance[pcID]; !ok {
+			return nil, helpers.NewAppError(http.StatusBadRequest, "el proyecto curricular no pertenece a la facultad", nil)
+		}
+		return map[int]struct{}{pcID: {}}, nil
+	}
+	return alcance, nil
+}
+
+// pcsAsignados retorna los PC de la asignación del usuario: los de sus facultades y sus PC
+// asignados. Sin asignación responde 403.
+func pcsAsignados(ctx *beegocontext.Context, facultades, proyectos []int) (map[int]struct{}, error) {
+	if len(facultades) == 0 && len(proyectos) == 0 {
+		return nil, helpers.NewAppError(http.StatusForbidden, "el usuario no tiene facultad ni proyecto curricular asignado", nil)
+	}
+	permitidos := make(map[int]struct{}, len(proyectos))
+	for _, id := range proyectos {
+		permitidos[id] = struct{}{}
+	}
+	for _, id := range facultades {
+		pcs, err := pcsFacultad(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for pc := range pcs {
+			permitidos[pc] = struct{}{}
+		}
+	}
+	return permitidos, nil
+}
+
+func pcsFacultad(ctx *beegocontext.Context, facultadID int) (map[int]struct{}, error) {
+	pcs, err := ListarPCPorFacultad(ctx, facultadID, "", "1", "500")
+	if err != nil {
+		return nil, helpers.NewAppError(http.StatusBadGateway, "error consultando proyectos curriculares de la facultad", err)
+	}
+	out := make(map[int]struct{}, len(pcs))
+	for _, pc := range pcs {
+		out[pc.Id] = struct{}{}
+	}
+	return out, nil
+}
+
+func listPerfilesCoordinacion() ([]perfilRecord, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, estudiantePerfilResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+
+	var records []perfilRecord
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &records, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando perfiles")
+	}
+	return records, nil
+}
+
+// postulacionSeleccionada cuenta como colocación la selección del tutor y la aceptación del estudiante.
+func postulacionSeleccionada(estado string) bool {
+	switch strings.ToUpper(strings.TrimSpace(estado)) {
+	case models.PostEstadoSeleccionada, models.PostEstadoAceptada:
+		return true
+	}
+	return false
+}
+
+func tiempoSeleccion(dias []float64) internaldto.TiempoSeleccion {
+	result := internaldto.TiempoSeleccion{Muestras: len(dias)}
+	if len(dias) == 0 {
+		return result
+	}
+	sort.Float64s(dias)
+	suma := 0.0
+	for _, d := range dias {
+		suma += d
+	}
+	result.PromedioDias = redondear1(suma / float64(len(dias)))
+	mitad := len(dias) / 2
+	if len(dias)%2 == 0 {
+		result.MedianaDias = redondear1((dias[mitad-1] + dias[mitad]) / 2)
+	} else {
+		result.MedianaDias = redondear1(dias[mitad])
+	}
+	return result
+}
+
+func topEmpresasCoordinacionResumen(ctx *beegocontext.Context, ofertas []internaldto.OfertaPostulantes) []internaldto.EmpresaResumen {
+	porEmpresa := map[int64]*internaldto.EmpresaResumen{}
+	for _, o := range ofertas {
+		if o.EmpresaID <= 0 {
+			continue
+		}
+		e := porEmpresa[o.EmpresaID]
+		if e == nil {
+			e = &internaldto.EmpresaResumen{EmpresaID: o.EmpresaID}
+			porEmpresa[o.EmpresaID] = e
+		}
+		e.Ofertas++
+		e.Postulaciones += o.Postulantes
+		e.Seleccionados += o.Seleccionados
+	}
+
+	result := make([]internaldto.EmpresaResumen, 0, len(porEmpresa))
+	for _, e := range porEmpresa {
+		result = append(result, *e)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Seleccionados != b.Seleccionados {
+			return a.Seleccionados > b.Seleccionados
+		}
+		if a.Ofertas != b.Ofertas {
+			return a.Ofertas > b.Ofertas
+		}
+		return a.EmpresaID < b.EmpresaID
+	})
+	if len(result) > topEmpresasCoordinacion {
+		result = result[:topEmpresasCoordinacion]
+	}
+
+	stdCtx := requestContext(ctx)
+	for i := range result {
+		if data, err := getTerceroByID(stdCtx, int(result[i].EmpresaID)); err == nil && data != nil {
+			result[i].Empresa = strings.TrimSpace(normalizeToString(data["NombreCompleto"]))
+		}
+		if result[i].Empresa == "" {
+			result[i].Empresa = fmt.Sprintf("Empresa #%d", result[i].EmpresaID)
+		}
+	}
+	return result
+}
+
+func redondear1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
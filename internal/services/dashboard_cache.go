@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultDashboardCacheTTLSeg = 60
+
+// indiceDashboard es lo que el tablero calculado declara para poder invalidarlo por eventos.
+type indiceDashboard struct {
+	perfilID int64
+	ofertas  map[int64]struct{}
+	// completo es falso si alguna sección falló; esos tableros no se guardan en caché.
+	completo bool
+}
+
+type entradaDashboard struct {
+	data      map[string]interface{}
+	indice    indiceDashboard
+	expiresAt time.Time
+}
+
+// cacheDashboard guarda el último tablero por estudiante y por tutor. generacion aumenta con cada
+// evento: un tablero que empezó a calcularse antes de un evento no se guarda, porque pudo leer
+// datos anteriores al cambio.
+var (
+	cacheDashboard = struct {
+		mu         sync.Mutex
+		activo     bool
+		generacion uint64
+		items      map[string]entradaDashboard
+	}{items: map[string]entradaDashboard{}}
+	cacheDashboardOnce sync.Once
+)
+
+// IniciarCacheDashboard activa la caché de tableros y la suscribe al bus de eventos. Sin esta
+// llamada, o con dashboard_cache_ttl_seg = 0, los tableros se calculan en cada consulta.
+func IniciarCacheDashboard() {
+	cacheDashboardOnce.Do(func() {
+		if dashboardCacheTTL() <= 0 {
+			return
+		}
+		SuscribirEventos(invalidarCacheDashboard)
+		cacheDashboard.mu.Lock()
+		cacheDashboard.activo = true
+		cacheDashboard.mu.Unlock()
+	})
+}
+
+func dashboardCacheTTL() time.Duration {
+	seg := configEntero("DASHBOARD_CACHE_TTL_SEG", "dashboard_cache_ttl_seg", defaultDashboardCacheTTLSeg)
+	return time.Duration(seg) * time.Second
+}
+
+func claveDashboardEstudiante(estudianteID int64) string {
+	return fmt.Sprintf("estudiante:%d", estudianteID)
+}
+
+func claveDashboardTutor(tutorID int64) string {
+	return fmt.Sprintf("tutor:%d", tutorID)
+}
+
+// obtenerDashboard retorna el tablero en caché o lo calcula. El mapa retornado se comparte entre
+// consultas y no debe modificarse.
+func obtenerDashboard(clave string, calcular func() (map[string]interface{}, indiceDashboard, error)) (map[string]interface{}, error) {
+	cacheDashboard.mu.Lock()
+	if !cacheDashboard.activo {
+		cacheDashboard.mu.Unlock()
+		data, _, err := calcular()
+		return data, err
+	}
+	if e, ok := cacheDashboard.items[clave]; ok && time.Now().Before(e.expiresAt) {
+		cacheDashboard.mu.Unlock()
+		return e.data, nil
+	}
+	generacion := cacheDashboard.generacion
+	cacheDashboard.mu.Unlock()
+
+	data, indice, err := calcular()
+	if err != nil || !indice.completo {
+		return data, err
+	}
+
+	cacheDashboard.mu.Lock()
+	if cacheDashboard.generacion == generacion {
+		cacheDashboard.items[clave] = entradaDashboard{
+			data:      data,
+			indice:    indice,
+			expiresAt: time.Now().Add(dashboardCacheTTL()),
+		}
+	}
+	cacheDashboard.mu.Unlock()
+	return data, nil
+}
+
+// invalidarCacheDashboard descarta los tableros afectados por el evento: los del estudiante y el
+// tutor involucrados, los que incluyen el perfil o la oferta del evento (perfil.actualizado cubre
+// visibilidad y completitud del perfil) y, si cambió el estado de una oferta, todos los de
+// estudiantes porque sus ofertas disponibles y recomendadas cambian.
+func invalidarCacheDashboard(e Evento) {
+	cacheDashboard.mu.Lock()
+	defer cacheDashboard.mu.Unlock()
+	cacheDashboard.generacion++
+
+	if e.EstudianteID > 0 {
+		delete(cacheDashboard.items, claveDashboardEstudiante(e.EstudianteID))
+	}
+	if e.TutorID > 0 {
+		delete(cacheDashboard.items, claveDashboardTutor(e.TutorID))
+	}
+	for clave, entrada := range cacheDashboard.items {
+		switch {
+		case e.Tipo == EventoOfertaEstado && strings.HasPrefix(clave, "estudiante:"):
+		case e.PerfilID > 0 && entrada.indice.perfilID == e.PerfilID:
+		case e.OfertaID > 0 && incluyeOferta(entrada.indice.ofertas, e.OfertaID):
+		default:
+			continue
+		}
+		delete(cacheDashboard.items, clave)
+	}
+}
+
+func incluyeOferta(ofertas map[int64]struct{}, ofertaID int64) bool {
+	_, ok := ofertas[ofertaID]
+	return ok
+}
@@ -22,89 +22,220 @@ const (
 	recommendedOffersLimit = 5
 )
 
-// GetDashboardEstudiante retorna la información consolidada del home del estudiante.
+// GetDashboardEstudiante retorna la información consolidada del home del estudiante. El resultado
+// se sirve desde caché mientras no venza ni llegue un evento que lo afecte.
 func GetDashboardEstudiante(ctx context.Context, estudianteID int) (map[string]interface{}, error) {
+	return obtenerDashboard(claveDashboardEstudiante(int64(estudianteID)), func() (map[string]interface{}, indiceDashboard, error) {
+		return calcularDashboardEstudiante(ctx, estudianteID)
+	})
+}
+
+// calcularDashboardEstudiante consulta las secciones en paralelo; las que fallan quedan vacías y se
+// reportan en "warnings".
+func calcularDashboardEstudiante(ctx context.Context, estudianteID int) (map[string]interface{}, indiceDashboard, error) {
 	crud := clients.CastorCRUD()
+	fan := newDashboardFanOut(ctx)
+
+	// Fase 1: perfil y postulaciones, de los que dependen las demás secciones
+	perfilF := internalhelpers.Submit(fan, "perfil", func(ctx context.Context) (*clients.PerfilRecord, error) {
+		return crud.GetPerfilByTerceroID(ctx, estudianteID)
+	})
+	postsF := internalhelpers.Submit(fan, "postulaciones", func(ctx context.Context) ([]models.Postulacion, error) {
+		return crud.ListPostulaciones(ctx, map[string]string{
+			"EstudianteId": fmt.Sprint(estudianteID),
+		})
+	})
+	fan.Wait()
+	perfil := perfilF.Value()
+	posts := postsF.Value()
 
 	resumen := map[string]interface{}{}
 
 	// Postulaciones por estado
-	postCounts, _ := crud.CountPostulacionesByEstado(ctx, map[string]string{
-		"EstudianteId": fmt.Sprint(estudianteID),
-	})
+	postCounts := map[string]int{}
+	for _, p := range posts {
+		postCounts[strings.ToUpper(strings.TrimSpace(p.EstadoPostulacion))]++
+	}
 	resumen["postulaciones"] = postCounts
 	resumen["postulaciones_por_estado"] = mapPostCountsToNamedChips(postCounts)
 
-	// Invitaciones + visitas
-	invitCounts := map[string]int{}
-
-	visitasPerfil := map[string]interface{}{
-		"total": 0,
-		"items": []map[string]interface{}{},
-	}
-
+	// Fase 2: secciones independientes entre sí
 	var pcID int
 	var perfilVisible interface{}
-	if perfil, err := crud.GetPerfilByTerceroID(ctx, estudianteID); err == nil && perfil != nil {
+	var invitF *internalhelpers.Future[map[string]int]
+	var visitasF *internalhelpers.Future[map[string]interface{}]
+	if perfil != nil {
 		pcID = perfil.ProyectoCurricularId
 		perfilVisible = perfil.Visible
 
-		invitCounts, _ = crud.CountInvitacionesByEstado(ctx, map[string]string{
-			"PerfilEstudianteId": fmt.Sprint(perfil.Id),
+		invitF = internalhelpers.Submit(fan, "invitaciones", func(ctx context.Context) (map[string]int, error) {
+			return crud.CountInvitacionesByEstado(ctx, map[string]string{
+				"PerfilEstudianteId": fmt.Sprint(perfil.Id),
+			})
 		})
+		visitasF = internalhelpers.Submit(fan, "visitas_perfil", func(ctx context.Context) (map[string]interface{}, error) {
+			return buildVisitasPerfilResumen(ctx, perfil.Id)
+		})
+	}
 
-		// 1) Intentar resumen desde CRUD
-		if v, err := crud.ResumenVisitasPerfil(ctx, perfil.Id, 5 /* top */); err == nil && v != nil {
-			visitasPerfil = v
-		}
+	type ofertasDisponibles struct {
+		total int
+		chips []map[string]interface{}
+	}
+	ofertasF := internalhelpers.Submit(fan, "ofertas_disponibles", func(ctx context.Context) (ofertasDisponibles, error) {
+		total, chips, err := buildOfertasDisponiblesResumen(ctx, pcID, posts)
+		return ofertasDisponibles{total: total, chips: chips}, err
+	})
 
-		// 2) Fallback: si resumen viene vacío o con tutor_id inválido (ej. 0), arma resumen desde visitas crudas
-		if needsVisitasFallback(visitasPerfil) {
-			if list, err := crud.ListPerfilVisitas(ctx, perfil.Id); err == nil && len(list) > 0 {
-				visitasPerfil = buildResumenVisitasFromList(list, 5)
-			}
-		}
+	type pasantia struct {
+		activo bool
+		data   map[string]interface{}
+	}
+	pasantiaF := internalhelpers.Submit(fan, "pasantia_activa", func(ctx context.Context) (pasantia, error) {
+		activo, data, err := resolvePasantiaActiva(ctx, posts)
+		return pasantia{activo: activo, data: data}, err
+	})
+	recientesF := internalhelpers.Submit(fan, "mis_postulaciones_recientes", func(ctx context.Context) ([]map[string]interface{}, error) {
+		return buildPostulacionesRecientes(ctx, posts), nil
+	})
+	recomendadasF := internalhelpers.Submit(fan, "ofertas_recomendadas", func(ctx context.Context) ([]map[string]interface{}, error) {
+		return buildOfertasRecomendadas(ctx, estudianteID, perfil)
+	})
+	warnings := fan.Wait()
 
-		// 3) Enriquecer (tutor nombre + empresa nombre) sin romper si falla
-		enrichVisitasPerfil(ctx, visitasPerfil)
+	invitCounts := map[string]int{}
+	if invitF != nil && invitF.Value() != nil {
+		invitCounts = invitF.Value()
+	}
+	visitasPerfil := map[string]interface{}{
+		"total": 0,
+		"items": []map[string]interface{}{},
+	}
+	if visitasF != nil && visitasF.OK() {
+		visitasPerfil = visitasF.Value()
 	}
 
 	resumen["invitaciones"] = invitCounts
 	resumen["invitaciones_por_estado"] = mapInvCountsToNamedChips(invitCounts)
 	resumen["visitas_perfil"] = visitasPerfil
 	resumen["perfil_visible"] = perfilVisible
+	resumen["perfil_completitud"] = completitudPerfilCRUD(perfil)
 
 	// Ofertas disponibles (KPI + chips)
-	ofTotal, ofChips := buildOfertasDisponiblesResumen(ctx, estudianteID, pcID)
-	resumen["ofertas_disponibles"] = ofTotal
-	resumen["ofertas_disponibles_por_estado"] = ofChips
+	ofertas := ofertasF.Value()
+	if ofertas.chips == nil {
+		ofertas.chips = []map[string]interface{}{}
+	}
+	resumen["ofertas_disponibles"] = ofertas.total
+	resumen["ofertas_disponibles_por_estado"] = ofertas.chips
+
+	resumen["pasante_activo"] = pasantiaF.Value().activo
+	if pasantiaF.Value().activo {
+		resumen["pasantia_activa"] = pasantiaF.Value().data
+	}
 
-	pasanteActivo, pasantiaActiva := resolvePasantiaActiva(ctx, estudianteID)
-	resumen["pasante_activo"] = pasanteActivo
-	if pasanteActivo {
-		resumen["pasantia_activa"] = pasantiaActiva
+	postulacionesRecientes := recientesF.Value()
+	if postulacionesRecientes == nil {
+		postulacionesRecientes = []map[string]interface{}{}
+	}
+	ofertasRecomendadas := recomendadasF.Value()
+	if ofertasRecomendadas == nil {
+		ofertasRecomendadas = []map[string]interface{}{}
 	}
 
-	postulacionesRecientes := buildPostulacionesRecientes(ctx, estudianteID)
-	ofertasRecomendadas := buildOfertasRecomendadas(ctx, estudianteID, pcID)
+	indice := indiceDashboard{ofertas: map[int64]struct{}{}, completo: len(warnings) == 0}
+	if perfil != nil {
+		indice.perfilID = int64(perfil.Id)
+	}
+	for _, p := range posts {
+		indice.ofertas[p.OfertaId] = struct{}{}
+	}
 
 	return map[string]interface{}{
 		"resumen":                     resumen,
 		"mis_postulaciones_recientes": postulacionesRecientes,
 		"ofertas_recomendadas":        ofertasRecomendadas,
-	}, nil
+		"warnings":                    warnings,
+	}, indice, nil
+}
+
+// newDashboardFanOut crea el ejecutor de secciones de los tableros con la concurrencia y el timeout
+// por sección configurados.
+func newDashboardFanOut(ctx context.Context) *internalhelpers.FanOut {
+	limite := configEntero("DASHBOARD_CONCURRENCIA", "dashboard_concurrencia", 6)
+	timeoutMs := configEntero("DASHBOARD_TIMEOUT_SECCION_MS", "dashboard_timeout_seccion_ms", 5000)
+	return internalhelpers.NewFanOut(ctx, limite, time.Duration(timeoutMs)*time.Millisecond)
+}
+
+// cargarEnParalelo ejecuta fn para cada id con la concurrencia de los tableros. Es para
+// enriquecimientos: los ids que fallan simplemente no quedan en el resultado.
+func cargarEnParalelo[K comparable, V any](ctx context.Context, ids []K, fn func(ctx context.Context, id K) (V, error)) map[K]V {
+	limite := configEntero("DASHBOARD_CONCURRENCIA", "dashboard_concurrencia", 6)
+	fan := internalhelpers.NewFanOut(ctx, limite, 0)
+	futures := make(map[K]*internalhelpers.Future[V], len(ids))
+	for _, id := range ids {
+		if _, ok := futures[id]; ok {
+			continue
+		}
+		id := id
+		futures[id] = internalhelpers.Submit(fan, fmt.Sprint(id), func(ctx context.Context) (V, error) {
+			return fn(ctx, id)
+		})
+	}
+	fan.Wait()
+
+	out := make(map[K]V, len(futures))
+	for id, f := range futures {
+		if f.OK() {
+			out[id] = f.Value()
+		}
+	}
+	return out
 }
 
-func resolvePasantiaActiva(ctx context.Context, estudianteID int) (bool, map[string]interface{}) {
-	if estudianteID <= 0 {
-		return false, nil
+// buildVisitasPerfilResumen arma el resumen de visitas del perfil: primero el del CRUD y, si viene
+// vacío o con tutor_id inválido (ej. 0), desde las visitas crudas deduplicadas.
+func buildVisitasPerfilResumen(ctx context.Context, perfilID int) (map[string]interface{}, error) {
+	crud := clients.CastorCRUD()
+	visitasPerfil, err := crud.ResumenVisitasPerfil(ctx, perfilID, 5 /* top */)
+	if err != nil || visitasPerfil == nil {
+		visitasPerfil = map[string]interface{}{
+			"total": 0,
+			"items": []map[string]interface{}{},
+		}
 	}
 
-	list, err := clients.CastorCRUD().ListPostulaciones(ctx, map[string]string{
-		"EstudianteId": fmt.Sprint(estudianteID),
+	if needsVisitasFallback(visitasPerfil) {
+		list, errList := crud.ListPerfilVisitas(ctx, perfilID)
+		if errList != nil && err != nil {
+			return nil, errList
+		}
+		if len(list) > 0 {
+			visitasPerfil = buildResumenVisitasFromList(deduplicarVisitas(list, ventanaDeduplicacionVisitas()), 5)
+		}
+	}
+
+	// Enriquecer (tutor nombre + empresa nombre) sin romper si falla
+	enrichVisitasPerfil(ctx, visitasPerfil)
+	return visitasPerfil, nil
+}
+
+func resolvePasantiaActiva(ctx context.Context, list []models.Postulacion) (bool, map[string]interface{}, error) {
+	aceptadas := make([]int64, 0)
+	for _, post := range list {
+		if strings.ToUpper(strings.TrimSpace(post.EstadoPostulacion)) == "PSAC_CTR" {
+			aceptadas = append(aceptadas, post.OfertaId)
+		}
+	}
+	if len(aceptadas) == 0 {
+		return false, nil, nil
+	}
+
+	ofertas := cargarEnParalelo(ctx, aceptadas, func(_ context.Context, id int64) (*models.Oferta, error) {
+		return rootservices.GetOferta(id)
 	})
-	if err != nil || len(list) == 0 {
-		return false, nil
+	if len(ofertas) == 0 {
+		return false, nil, fmt.Errorf("no se pudieron consultar las ofertas aceptadas")
 	}
 
 	for _, post := range list {
@@ -113,8 +244,8 @@ func resolvePasantiaActiva(ctx context.Context, estudianteID int) (bool, map[str
 			continue
 		}
 
-		oferta, err := rootservices.GetOferta(post.OfertaId)
-		if err != nil || oferta == nil {
+		oferta := ofertas[post.OfertaId]
+		if oferta == nil {
 			continue
 		}
 
@@ -145,50 +276,63 @@ func resolvePasantiaActiva(ctx context.Context, estudianteID int) (bool, map[str
 		out["estado_postulacion_det"] = translateEstado(post.EstadoPostulacion)
 		out["estado_oferta_det"] = translateEstado(oferta.Estado)
 
-		return true, out
+		return true, out, nil
 	}
 
-	return false, nil
+	return false, nil, nil
 }
 
-// GetDashboardTutor retorna contadores básicos para el tutor.
+// GetDashboardTutor retorna contadores básicos para el tutor, servidos desde caché mientras no
+// venzan ni llegue un evento sobre sus ofertas.
 func GetDashboardTutor(ctx context.Context, tutorID int) (map[string]interface{}, error) {
+	return obtenerDashboard(claveDashboardTutor(int64(tutorID)), func() (map[string]interface{}, indiceDashboard, error) {
+		return calcularDashboardTutor(ctx, tutorID)
+	})
+}
+
+// calcularDashboardTutor consulta las postulaciones de cada oferta en paralelo; las ofertas que
+// fallan se reportan en "warnings".
+func calcularDashboardTutor(ctx context.Context, tutorID int) (map[string]interface{}, indiceDashboard, error) {
 	crud := clients.CastorCRUD()
+	fan := newDashboardFanOut(ctx)
 
-	// Ofertas del tutor por estado
-	ofertas, errResumen := crud.ResumenOfertasTutor(ctx, tutorID)
-	if errResumen != nil {
-		return nil, errResumen
-	}
+	// Ofertas del tutor por estado y listado de ofertas para las postulaciones
+	ofertasF := internalhelpers.Submit(fan, "ofertas", func(ctx context.Context) (map[string]int, error) {
+		return crud.ResumenOfertasTutor(ctx, tutorID)
+	})
+	listF := internalhelpers.Submit(fan, "postulaciones", func(ctx context.Context) ([]clients.OfertaTutorMin, error) {
+		return crud.ListOfertasTutor(ctx, tutorID)
+	})
+	fan.Wait()
 
-	invitaciones := map[string]int{}
-	if invitaciones == nil {
-		invitaciones = map[string]int{}
+	// Postulaciones por estado + por oferta (solo de ofertas del tutor)
+	porOferta := make(map[int64]*internalhelpers.Future[[]models.Postulacion])
+	for _, oferta := range listF.Value() {
+		if oferta.Id <= 0 {
+			continue
+		}
+		ofertaID := oferta.Id
+		porOferta[ofertaID] = internalhelpers.Submit(fan, fmt.Sprintf("postulaciones.oferta_%d", ofertaID), func(ctx context.Context) ([]models.Postulacion, error) {
+			return crud.ListPostulaciones(ctx, map[string]string{
+				"oferta_id": fmt.Sprint(ofertaID),
+			})
+		})
 	}
+	warnings := fan.Wait()
 
+	ofertas := ofertasF.Value()
 	if ofertas == nil {
 		ofertas = map[string]int{}
 	}
 
-	// Postulaciones por estado + por oferta (solo de ofertas del tutor)
 	postByEstado := map[string]int{}
 	postByOferta := map[string]int{}
-	offersList, err := crud.ListOfertasTutor(ctx, tutorID)
-	if err != nil {
-		return nil, err
-	}
-	for _, oferta := range offersList {
-		if oferta.Id <= 0 {
+	for ofertaID, f := range porOferta {
+		if !f.OK() {
 			continue
 		}
-		list, err := crud.ListPostulaciones(ctx, map[string]string{
-			"oferta_id": fmt.Sprint(oferta.Id),
-		})
-		if err != nil {
-			fmt.Println("WARN dashboard tutor: error postulaciones por oferta", "oferta_id", oferta.Id, "err", err)
-			continue
-		}
-		postByOferta[strconv.FormatInt(oferta.Id, 10)] = len(list)
+		list := f.Value()
+		postByOferta[strconv.FormatInt(ofertaID, 10)] = len(list)
 		for _, p := range list {
 			code := strings.ToUpper(strings.TrimSpace(p.EstadoPostulacion))
 			if code == "" {
@@ -202,22 +346,26 @@ func GetDashboardTutor(ctx context.Context, tutorID int) (map[string]interface{}
 		"por_oferta": postByOferta,
 	}
 
+	indice := indiceDashboard{ofertas: map[int64]struct{}{}, completo: len(warnings) == 0}
+	for _, oferta := range listF.Value() {
+		indice.ofertas[oferta.Id] = struct{}{}
+	}
+
 	return map[string]interface{}{
 		"ofertas":       ofertas,
-		"invitaciones":  invitaciones,
+		"invitaciones":  map[string]int{},
 		"postulaciones": postulaciones,
-	}, nil
+		"warnings":      warnings,
+	}, indice, nil
 }
 
-func buildPostulacionesRecientes(ctx context.Context, estudianteID int) []map[string]interface{} {
-	crud := clients.CastorCRUD()
-	list, err := crud.ListPostulaciones(ctx, map[string]string{
-		"EstudianteId": fmt.Sprint(estudianteID),
-	})
-	if err != nil || len(list) == 0 {
+func buildPostulacionesRecientes(ctx context.Context, posts []models.Postulacion) []map[string]interface{} {
+	if len(posts) == 0 {
 		return []map[string]interface{}{}
 	}
 
+	list := make([]models.Postulacion, len(posts))
+	copy(list, posts)
 	sort.Slice(list, func(i, j int) bool {
 		ti := parseTime(list[i].FechaPostulacion)
 		tj := parseTime(list[j].FechaPostulacion)
@@ -229,8 +377,15 @@ func buildPostulacionesRecientes(ctx context.Context, estudianteID int) []map[st
 		limit = len(list)
 	}
 
+	ofertaIDs := make([]int64, 0, limit)
+	for _, post := range list[:limit] {
+		ofertaIDs = append(ofertaIDs, post.OfertaId)
+	}
+	ofertaCache := cargarEnParalelo(ctx, ofertaIDs, func(_ context.Context, id int64) (*models.Oferta, error) {
+		return rootservices.GetOferta(id)
+	})
+
 	result := make([]map[string]interface{}, 0, limit)
-	ofertaCache := make(map[int64]*models.Oferta)
 	for _, post := range list[:limit] {
 		entry := map[string]interface{}{
 			"id":                post.Id,
@@ -239,11 +394,6 @@ func buildPostulacionesRecientes(ctx context.Context, estudianteID int) []map[st
 			"estado_det":        translateEstado(post.EstadoPostulacion),
 			"fecha_postulacion": strings.TrimSpace(post.FechaPostulacion),
 		}
-		if ofertaCache[post.OfertaId] == nil {
-			if det, err := rootservices.GetOferta(post.OfertaId); err == nil && det != nil {
-				ofertaCache[post.OfertaId] = det
-			}
-		}
 		if det := ofertaCache[post.OfertaId]; det != nil {
 			entry["titulo_oferta"] = strings.TrimSpace(det.Titulo)
 		}
@@ -340,11 +490,25 @@ func enrichVisitasPerfil(ctx context.Context, visitas map[string]interface{}) {
 		return
 	}
 
-	// cache de terceros (tutor y empresa) para no pedirlos repetido
-	terceroCache := map[int]map[string]interface{}{}
+	tutorIDs := make([]int, 0, len(items))
+	for _, it := range items {
+		if tutorID, _ := normalizeToInt(it["tutor_id"]); tutorID > 0 {
+			tutorIDs = append(tutorIDs, tutorID)
+		}
+	}
 
-	// cache de tutor->empresa_id (vía CRUD)
-	empresaIdCache := map[int]int{}
+	// Tutor (tercero) y empresa activa del tutor (CRUD) en paralelo
+	tutores := cargarEnParalelo(ctx, tutorIDs, getTerceroByID)
+	empresaPorTutor := cargarEnParalelo(ctx, tutorIDs, getEmpresaIDActivaByTutor)
+
+	empresaIDs := make([]int, 0, len(empresaPorTutor))
+	for _, eid := range empresaPorTutor {
+		if eid > 0 {
+			empresaIDs = append(empresaIDs, eid)
+		}
+	}
+	// Empresa: nombre (mismo endpoint de terceros/tutor/:id)
+	empresas := cargarEnParalelo(ctx, empresaIDs, getTerceroByID)
 
 	for _, it := range items {
 		tutorID, _ := normalizeToInt(it["tutor_id"])
@@ -353,12 +517,7 @@ func enrichVisitasPerfil(ctx context.Context, visitas map[string]interface{}) {
 		}
 
 		// --- Tutor: nombre ---
-		if terceroCache[tutorID] == nil {
-			if data, err := getTerceroByID(ctx, tutorID); err == nil && data != nil {
-				terceroCache[tutorID] = data
-			}
-		}
-		if t := terceroCache[tutorID]; t != nil {
+		if t := tutores[tutorID]; t != nil {
 			it["tercero_id"] = tutorID
 			nombre := strings.TrimSpace(fmt.Sprint(t["NombreCompleto"]))
 			if nombre == "" {
@@ -368,24 +527,10 @@ func enrichVisitasPerfil(ctx context.Context, visitas map[string]interface{}) {
 
 		}
 
-		// --- Empresa: obtener empresa_id activa del tutor (CRUD) ---
-		empresaID := empresaIdCache[tutorID]
-		if empresaID == 0 {
-			if eid, err := getEmpresaIDActivaByTutor(ctx, tutorID); err == nil && eid > 0 {
-				empresaID = eid
-				empresaIdCache[tutorID] = eid
-			}
-		}
-		if empresaID > 0 {
+		// --- Empresa ---
+		if empresaID := empresaPorTutor[tutorID]; empresaID > 0 {
 			it["empresa_id"] = empresaID
-
-			// Empresa: nombre (mismo endpoint de terceros/tutor/:id)
-			if terceroCache[empresaID] == nil {
-				if data, err := getTerceroByID(ctx, empresaID); err == nil && data != nil {
-					terceroCache[empresaID] = data
-				}
-			}
-			if e := terceroCache[empresaID]; e != nil {
+			if e := empresas[empresaID]; e != nil {
 				it["empresa"] = strings.TrimSpace(fmt.Sprint(e["NombreCompleto"]))
 			} else {
 				it["empresa"] = fmt.Sprintf("Empresa #%d", empresaID)
@@ -521,55 +666,28 @@ func mapPostCountsToNamedChips(counts map[string]int) []map[string]interface{} {
 	return items
 }
 
-func buildOfertasRecomendadas(ctx context.Context, estudianteID int, pcID int) []map[string]interface{} {
-	if pcID <= 0 || estudianteID <= 0 {
-		return []map[string]interface{}{}
+// buildOfertasRecomendadas retorna las ofertas con mayor afinidad según el motor de matching.
+func buildOfertasRecomendadas(ctx context.Context, estudianteID int, perfil *clients.PerfilRecord) ([]map[string]interface{}, error) {
+	if perfil == nil || perfil.ProyectoCurricularId <= 0 || estudianteID <= 0 {
+		return []map[string]interface{}{}, nil
 	}
 
-	// set de oferta_id ya postuladas
-	postuladas := make(map[int64]struct{})
-	if list, err := clients.CastorCRUD().ListPostulaciones(ctx, map[string]string{
-		"EstudianteId": fmt.Sprint(estudianteID),
-	}); err == nil {
-		for _, p := range list {
-			if p.OfertaId > 0 {
-				postuladas[p.OfertaId] = struct{}{}
-			}
-		}
+	matches, err := rankOfertasParaEstudiante(ctx, estudianteID, perfilMatchingDesde(perfil))
+	if err != nil {
+		return nil, err
 	}
-
-	filters := map[string]string{
-		"estado": models.OfertaEstadoCreada, // "abierta" según el alias
+	if len(matches) == 0 {
+		return []map[string]interface{}{}, nil
 	}
-	ofertas, err := rootservices.ListOfertas(filters)
-	if err != nil || len(ofertas) == 0 {
-		return []map[string]interface{}{}
+	if len(matches) > recommendedOffersLimit {
+		matches = matches[:recommendedOffersLimit]
 	}
 
-	result := make([]map[string]interface{}, 0, recommendedOffersLimit)
-	for _, oferta := range ofertas {
-		// excluir si ya postuló
-		if _, ok := postuladas[oferta.Id]; ok {
-			continue
-		}
-
-		pcIDs, err := getPCIDsByOferta(int(oferta.Id))
-		if err != nil {
-			continue
-		}
-		if len(pcIDs) > 0 && !hasInt(pcIDs, pcID) {
-			continue
-		}
-
-		entry := mapOferta(oferta)
-		entry["proyecto_curricular_ids"] = pcIDs
-		result = append(result, entry)
-
-		if len(result) >= recommendedOffersLimit {
-			break
-		}
+	result := make([]map[string]interface{}, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.toMap())
 	}
-	return result
+	return result, nil
 }
 
 func translateEstado(code string) map[string]string {
@@ -685,22 +803,16 @@ func mapInvEstadoToParamCode(raw string) string {
 		return r
 	}
 }
-func buildOfertasDisponiblesResumen(ctx context.Context, estudianteID int, pcID int) (int, []map[string]interface{}) {
-	if pcID <= 0 || estudianteID <= 0 {
-		return 0, []map[string]interface{}{}
+func buildOfertasDisponiblesResumen(ctx context.Context, pcID int, posts []models.Postulacion) (int, []map[string]interface{}, error) {
+	if pcID <= 0 {
+		return 0, []map[string]interface{}{}, nil
 	}
 
-	// 1) Cargar postulaciones del estudiante para excluir ofertas ya postuladas
-	crud := clients.CastorCRUD()
-	posts, err := crud.ListPostulaciones(ctx, map[string]string{
-		"EstudianteId": fmt.Sprint(estudianteID),
-	})
+	// 1) Postulaciones del estudiante para excluir ofertas ya postuladas
 	postuladas := make(map[int64]struct{}, len(posts))
-	if err == nil {
-		for _, p := range posts {
-			if p.OfertaId > 0 {
-				postuladas[p.OfertaId] = struct{}{}
-			}
+	for _, p := range posts {
+		if p.OfertaId > 0 {
+			postuladas[p.OfertaId] = struct{}{}
 		}
 	}
 
@@ -709,9 +821,23 @@ func buildOfertasDisponiblesResumen(ctx context.Context, estudianteID int, pcID
 		"estado": models.OfertaEstadoCreada,
 	}
 	ofertas, err := rootservices.ListOfertas(filters)
-	if err != nil || len(ofertas) == 0 {
-		return 0, []map[string]interface{}{}
+	if err != nil {
+		return 0, []map[string]interface{}{}, err
+	}
+	if len(ofertas) == 0 {
+		return 0, []map[string]interface{}{}, nil
+	}
+
+	// Proyectos curriculares de cada oferta no postulada (oferta -> oferta_proyecto_curricular)
+	candidatas := make([]int, 0, len(ofertas))
+	for _, oferta := range ofertas {
+		if _, ok := postuladas[oferta.Id]; !ok {
+			candidatas = append(candidatas, int(oferta.Id))
+		}
 	}
+	pcsPorOferta := cargarEnParalelo(ctx, candidatas, func(_ context.Context, id int) ([]int, error) {
+		return getPCIDsByOferta(id)
+	})
 
 	// 3) Filtrar por PC y excluir postuladas
 	countsByEstado := map[string]int{}
@@ -723,9 +849,9 @@ func buildOfertasDisponiblesResumen(ctx context.Context, estudianteID int, pcID
 			continue
 		}
 
-		// Filtro por proyecto curricular (oferta -> oferta_proyecto_curricular)
-		pcIDs, err := getPCIDsByOferta(int(oferta.Id))
-		if err != nil {
+		// Filtro por proyecto curricular; si no se pudieron consultar se excluye
+		pcIDs, ok := pcsPorOferta[int(oferta.Id)]
+		if !ok {
 			continue
 		}
 		if len(pcIDs) > 0 && !hasInt(pcIDs, pcID) {
@@ -756,5 +882,5 @@ func buildOfertasDisponiblesResumen(ctx context.Context, estudianteID int, pcID
 		})
 	}
 
-	return total, chips
+	return total, chips, nil
 }
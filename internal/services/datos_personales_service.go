@@ -0,0 +1,816 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	stdctx "context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/beego/beego/v2/server/web/context"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	"github.com/udistrital/pasantia_mid/internal/clients"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	solicitudEliminacionResource       = "solicitud_eliminacion_datos"
+	solicitudEliminacionEventoResource = "solicitud_eliminacion_evento"
+
+	SolicitudEliminacionPendiente = "PENDIENTE"
+	SolicitudEliminacionRechazada = "RECHAZADA"
+	SolicitudEliminacionEjecutada = "EJECUTADA"
+	// SolicitudEliminacionError queda cuando la ejecución falló a mitad de camino; coordinación
+	// puede volver a confirmarla porque cada paso es repetible.
+	SolicitudEliminacionError = "ERROR"
+
+	rolCoordinacion = "COORDINACION"
+
+	contentTypeZIP    = "application/zip"
+	maxTextoSolicitud = 500
+	textoEliminado    = "[eliminado por solicitud del titular]"
+	limiteExportacion = 10000
+
+	// Acciones de la bitácora de la solicitud.
+	eventoSolicitada   = "SOLICITADA"
+	eventoConfirmada   = "CONFIRMADA"
+	eventoRechazada    = "RECHAZADA"
+	eventoPaso         = "PASO"
+	eventoEjecutada    = "EJECUTADA"
+	eventoErrorEjecuta = "ERROR"
+)
+
+// solicitudEliminacion es la representación interna del registro solicitud_eliminacion_datos.
+type solicitudEliminacion struct {
+	ID              int
+	TerceroID       int
+	PerfilID        int
+	Estado          string
+	Motivo          string
+	Observacion     string
+	CoordinadorID   int
+	Resultado       map[string]int
+	FechaSolicitud  string
+	FechaResolucion string
+}
+
+// ExportarMisDatos genera un ZIP con archivos JSON del perfil, postulaciones, invitaciones, visitas
+// recibidas, consentimientos y solicitudes de eliminación del estudiante.
+func ExportarMisDatos(ctx *context.Context, estudianteID int) (*ArchivoExportado, error) {
+	if estudianteID <= 0 {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "estudiante_id inválido", nil)
+	}
+	stdCtx := requestContext(ctx)
+
+	record, err := findPerfil(estudianteID)
+	if err != nil {
+		return nil, err
+	}
+	var perfil map[string]interface{}
+	var visitas []map[string]interface{}
+	if record != nil {
+		perfil = mapPerfil(*record)
+		if nombre := strings.TrimSpace(NombreCompletoPorIDCoreStd(stdCtx, estudianteID)); nombre != "" {
+			perfil["nombre_completo"] = nombre
+		}
+		list, err := clients.CastorCRUD().ListPerfilVisitas(stdCtx, record.Id)
+		if err != nil {
+			return nil, helpers.AsAppError(err, "error consultando visitas")
+		}
+		visitas = make([]map[string]interface{}, 0, len(list))
+		for _, v := range list {
+			visitas = append(visitas, map[string]interface{}{
+				"tutor_id":     v.TutorId,
+				"fecha_visita": v.FechaVisita.Format(time.RFC3339),
+			})
+		}
+	}
+
+	postulaciones, err := exportPostulacionesEstudiante(stdCtx, estudianteID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitaciones, err := ListarInvitacionesDeEstudiante(stdCtx, estudianteID, "", 1, limiteExportacion)
+	if err != nil {
+		return nil, err
+	}
+
+	consentimientos, err := HistorialConsentimiento(estudianteID, 1, limiteExportacion)
+	if err != nil {
+		return nil, err
+	}
+
+	solicitudes, err := ListarSolicitudesEliminacionEstudiante(estudianteID)
+	if err != nil {
+		return nil, err
+	}
+
+	generado := time.Now().UTC()
+	archivos := []struct {
+		nombre    string
+		contenido interface{}
+	}{
+		{"perfil.json", perfil},
+		{"postulaciones.json", postulaciones},
+		{"invitaciones.json", invitaciones["items"]},
+		{"visitas_recibidas.json", visitas},
+		{"consentimientos.json", consentimientos.Items},
+		{"solicitudes_eliminacion.json", solicitudes},
+	}
+	nombres := make([]string, 0, len(archivos))
+	for _, a := range archivos {
+		nombres = append(nombres, a.nombre)
+	}
+	archivos = append(archivos, struct {
+		nombre    string
+		contenido interface{}
+	}{"exportacion.json", map[string]interface{}{
+		"tercero_id":       estudianteID,
+		"fecha_generacion": generado.Format(time.RFC3339),
+		"archivos":         nombres,
+	}})
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, a := range archivos {
+		data, err := json.MarshalIndent(a.contenido, "", "  ")
+		if err != nil {
+			return nil, helpers.NewAppError(http.StatusInternalServerError, "error generando exportación", err)
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: a.nombre, Method: zip.Deflate, Modified: generado})
+		if err != nil {
+			return nil, helpers.NewAppError(http.StatusInternalServerError, "error generando exportación", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, helpers.NewAppError(http.StatusInternalServerError, "error generando exportación", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, helpers.NewAppError(http.StatusInternalServerError, "error generando exportación", err)
+	}
+
+	return &ArchivoExportado{
+		Nombre:      fmt.Sprintf("mis_datos_%d_%s.zip", estudianteID, generado.Format("20060102")),
+		ContentType: contentTypeZIP,
+		Contenido:   buf.Bytes(),
+	}, nil
+}
+
+// exportPostulacionesEstudiante lista las postulaciones con los comentarios visibles para el estudiante.
+func exportPostulacionesEstudiante(ctx stdctx.Context, estudianteID int) ([]map[string]interface{}, error) {
+	list, err := clients.CastorCRUD().ListPostulaciones(ctx, map[string]string{"EstudianteId": strconv.Itoa(estudianteID)})
+	if err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return []map[string]interface{}{}, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando postulaciones")
+	}
+	out := make([]map[string]interface{}, 0, len(list))
+	for _, p := range list {
+		item := map[string]interface{}{
+			"id":                 p.Id,
+			"oferta_id":          p.OfertaId,
+			"estado_postulacion": p.EstadoPostulacion,
+			"fecha_postulacion":  p.FechaPostulacion,
+			"enlace_doc_hv":      p.EnlaceDocHv,
+		}
+		if oferta, err := rootservices.GetOferta(p.OfertaId); err == nil && oferta != nil {
+			item["oferta_titulo"] = strings.TrimSpace(oferta.Titulo)
+		}
+		if comentarios, err := listComentariosCRUD(p.Id); err == nil {
+			visibles := make([]map[string]interface{}, 0, len(comentarios))
+			for _, raw := range comentarios {
+				c := normalizeComentario(raw)
+				if c != nil && c["visibilidad"] != ComentarioVisibilidadInterna {
+					visibles = append(visibles, c)
+				}
+			}
+			item["comentarios"] = visibles
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// SolicitarEliminacion registra la solicitud del estudiante para eliminar sus datos. Queda pendiente
+// hasta que coordinación la confirme; solo puede haber una abierta a la vez.
+func SolicitarEliminacion(estudianteID int, motivo string) (internaldto.SolicitudEliminacion, error) {
+	motivo = strings.TrimSpace(motivo)
+	if len([]rune(motivo)) > maxTextoSolicitud {
+		return internaldto.SolicitudEliminacion{}, helpers.NewAppError(http.StatusBadRequest, "motivo supera 500 caracteres", nil)
+	}
+
+	existentes, err := listSolicitudesEliminacionCRUD("TerceroId:" + strconv.Itoa(estudianteID))
+	if err != nil {
+		return internaldto.SolicitudEliminacion{}, err
+	}
+	for _, s := range existentes {
+		if s.Estado == SolicitudEliminacionPendiente || s.Estado == SolicitudEliminacionError {
+			return internaldto.SolicitudEliminacion{}, helpers.NewAppError(http.StatusConflict, "ya existe una solicitud de eliminación en trámite", nil)
+		}
+	}
+
+	perfilID := 0
+	record, err := findPerfil(estudianteID)
+	if err != nil {
+		return internaldto.SolicitudEliminacion{}, err
+	}
+	if record != nil {
+		perfilID = record.Id
+	}
+
+	s := solicitudEliminacion{
+		TerceroID:      estudianteID,
+		PerfilID:       perfilID,
+		Estado:         SolicitudEliminacionPendiente,
+		Motivo:         motivo,
+		FechaSolicitud: nowISO(),
+	}
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, solicitudEliminacionResource)
+	var created map[string]interface{}
+	if err := helpers.DoJSON("POST", endpoint, s.toCRUD(), &created, cfg.RequestTimeout); err != nil {
+		return internaldto.SolicitudEliminacion{}, helpers.AsAppError(err, "error registrando solicitud de eliminación")
+	}
+	s.ID, _ = normalizeToInt(created["Id"])
+	if s.ID == 0 {
+		return internaldto.SolicitudEliminacion{}, helpers.NewAppError(http.StatusBadGateway, "el CRUD no retornó la solicitud creada", nil)
+	}
+
+	// La solicitud ya existe; sin esta entrada la bitácora arranca en la confirmación o el rechazo.
+	if err := registrarEventoEliminacion(s.ID, eventoSolicitada, estudianteID, rolEstudiante, motivo); err != nil {
+		logs.Error(err)
+	}
+	return s.toDTO(), nil
+}
+
+// ListarSolicitudesEliminacionEstudiante retorna las solicitudes del estudiante, la más reciente primero.
+func ListarSolicitudesEliminacionEstudiante(estudianteID int) ([]internaldto.SolicitudEliminacion, error) {
+	solicitudes, err := listSolicitudesEliminacionCRUD("TerceroId:" + strconv.Itoa(estudianteID))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]internaldto.SolicitudEliminacion, 0, len(solicitudes))
+	for _, s := range solicitudes {
+		out = append(out, s.toDTO())
+	}
+	return out, nil
+}
+
+// ListarSolicitudesEliminacion lista para coordinación las solicitudes, opcionalmente por estado.
+func ListarSolicitudesEliminacion(estado string, page, size int) (internaldto.PageDTO[internaldto.SolicitudEliminacion], error) {
+	query := ""
+	if estado = strings.ToUpper(strings.TrimSpace(estado)); estado != "" {
+		query = "Estado:" + estado
+	}
+	solicitudes, err := listSolicitudesEliminacionCRUD(query)
+	if err != nil {
+		return internaldto.PageDTO[internaldto.SolicitudEliminacion]{}, err
+	}
+
+	start := (page - 1) * size
+	if start > len(solicitudes) {
+		start = len(solicitudes)
+	}
+	end := start + size
+	if end > len(solicitudes) {
+		end = len(solicitudes)
+	}
+	items := make([]internaldto.SolicitudEliminacion, 0, end-start)
+	for _, s := range solicitudes[start:end] {
+		items = append(items, s.toDTO())
+	}
+	return internaldto.PageDTO[internaldto.SolicitudEliminacion]{
+		Items: items,
+		Page:  page,
+		Size:  size,
+		Total: int64(len(solicitudes)),
+	}, nil
+}
+
+// GetSolicitudEliminacion retorna la solicitud con su bitácora completa.
+func GetSolicitudEliminacion(id int) (internaldto.SolicitudEliminacion, error) {
+	s, err := getSolicitudEliminacionCRUD(id)
+	if err != nil {
+		return internaldto.SolicitudEliminacion{}, err
+	}
+	out := s.toDTO()
+	eventos, err := listEventosEliminacion(id)
+	if err != nil {
+		return internaldto.SolicitudEliminacion{}, err
+	}
+	out.Eventos = eventos
+	return out, nil
+}
+
+// RechazarSolicitudEliminacion cierra una solicitud pendiente sin tocar los datos. La observación
+// es obligatoria porque se comunica al titular.
+func RechazarSolicitudEliminacion(id, coordinadorID int, observacion string) (internaldto.SolicitudEliminacion, error) {
+	observacion = strings.TrimSpace(observacion)
+	if observacion == "" {
+		return internaldto.SolicitudEliminacion{}, helpers.NewAppError(http.StatusBadRequest, "observacion es requerida para rechazar", nil)
+	}
+	if len([]rune(observacion)) > maxTextoSolicitud {
+		return internaldto.SolicitudEliminacion{}, helpers.NewAppError(http.StatusBadRequest, "observacion supera 500 caracteres", nil)
+	}
+
+	s, err := getSolicitudEliminacionCRUD(id)
+	if err != nil {
+		return internaldto.SolicitudEliminacion{}, err
+	}
+	if s.Estado != SolicitudEliminacionPendiente {
+		return internaldto.SolicitudEliminacion{}, helpers.NewAppError(http.StatusConflict, "solo se pueden rechazar solicitudes pendientes", nil)
+	}
+
+	if err := registrarEventoEliminacion(s.ID, eventoRechazada, coordinadorID, rolCoordinacion, observacion); err != nil {
+		return internaldto.SolicitudEliminacion{}, err
+	}
+	s.Estado = SolicitudEliminacionRechazada
+	s.Observacion = observacion
+	s.CoordinadorID = coordinadorID
+	s.FechaResolucion = nowISO()
+	if err := updateSolicitudEliminacionCRUD(s); err != nil {
+		return internaldto.SolicitudEliminacion{}, err
+	}
+	return GetSolicitudEliminacion(s.ID)
+}
+
+// ConfirmarSolicitudEliminacion ejecuta la eliminación: anonimiza el perfil, las postulaciones y
+// las invitaciones conservando las cifras agregadas (proyecto curricular, ofertas, estados y
+// fechas), borra los comentarios del estudiante, las notas de tutores sobre el perfil, las visitas
+// al perfil y sus búsquedas guardadas. La bitácora de consentimientos se conserva como prueba
+// exigida por la Ley 1581.
+func ConfirmarSolicitudEliminacion(ctx stdctx.Context, id, coordinadorID int, observacion string) (internaldto.SolicitudEliminacion, error) {
+	observacion = strings.TrimSpace(observacion)
+	if len([]rune(observacion)) > maxTextoSolicitud {
+		return internaldto.SolicitudEliminacion{}, helpers.NewAppError(http.StatusBadRequest, "observacion supera 500 caracteres", nil)
+	}
+
+	s, err := getSolicitudEliminacionCRUD(id)
+	if err != nil {
+		return internaldto.SolicitudEliminacion{}, err
+	}
+	if s.Estado != SolicitudEliminacionPendiente && s.Estado != SolicitudEliminacionError {
+		return internaldto.SolicitudEliminacion{}, helpers.NewAppError(http.StatusConflict, "la solicitud ya fue resuelta", nil)
+	}
+
+	s.CoordinadorID = coordinadorID
+	s.Observacion = observacion
+	// Sin constancia de la confirmación no se toca ningún dato.
+	if err := registrarEventoEliminacion(s.ID, eventoConfirmada, coordinadorID, rolCoordinacion, observacion); err != nil {
+		return internaldto.SolicitudEliminacion{}, err
+	}
+
+	resultado, errEjecucion := ejecutarEliminacion(ctx, s)
+	// Aun si falló a mitad, algunos pasos ya modificaron el perfil.
+	publicarPerfilActualizado(s.TerceroID, s.PerfilID)
+	s.Resultado = resultado
+	s.FechaResolucion = nowISO()
+	if errEjecucion == nil {
+		// Si no queda constancia del cierre la solicitud pasa a ERROR y se puede confirmar de nuevo.
+		errEjecucion = registrarEventoEliminacion(s.ID, eventoEjecutada, coordinadorID, rolCoordinacion, resumenResultado(resultado))
+	}
+	if errEjecucion != nil {
+		s.Estado = SolicitudEliminacionError
+		if err := registrarEventoEliminacion(s.ID, eventoErrorEjecuta, coordinadorID, rolCoordinacion, errEjecucion.Error()); err != nil {
+			logs.Error(err)
+		}
+	} else {
+		s.Estado = SolicitudEliminacionEjecutada
+	}
+	if err := updateSolicitudEliminacionCRUD(s); err != nil {
+		return internaldto.SolicitudEliminacion{}, err
+	}
+	if errEjecucion != nil {
+		return internaldto.SolicitudEliminacion{}, helpers.AsAppError(errEjecucion, "error ejecutando la eliminación; la solicitud puede confirmarse de nuevo")
+	}
+	return GetSolicitudEliminacion(s.ID)
+}
+
+// ejecutarEliminacion aplica cada paso y deja constancia de él en la bitácora. Los pasos son
+// repetibles: lo ya anonimizado deja de coincidir con las consultas.
+func ejecutarEliminacion(ctx stdctx.Context, s solicitudEliminacion) (map[string]int, error) {
+	resultado := map[string]int{}
+	pasos := []struct {
+		nombre string
+		fn     func() (int, error)
+	}{
+		{"busquedas_guardadas", func() (int, error) { return eliminarBusquedasEstudiante(s.TerceroID) }},
+		{"comentarios", func() (int, error) { return anonimizarComentariosEstudiante(ctx, s.TerceroID) }},
+		{"postulaciones", func() (int, error) { return anonimizarPostulacionesEstudiante(ctx, s.TerceroID) }},
+		{"guardados", func() (int, error) { return eliminarBookmarksPerfil(s.PerfilID) }},
+		{"invitaciones", func() (int, error) { return anonimizarInvitacionesEstudiante(s.TerceroID) }},
+		{"visitas", func() (int, error) { return eliminarVisitasPerfil(ctx, s.PerfilID) }},
+		{"perfil", func() (int, error) { return anonimizarPerfilEstudiante(s.PerfilID) }},
+	}
+	for _, paso := range pasos {
+		n, err := paso.fn()
+		resultado[paso.nombre] = n
+		if err != nil {
+			logs.Error("eliminación de datos: solicitud", s.ID, "paso", paso.nombre, ":", err)
+			return resultado, fmt.Errorf("%s: %w", paso.nombre, err)
+		}
+		logs.Info("eliminación de datos: solicitud", s.ID, "paso", paso.nombre, "registros", n)
+		if err := registrarEventoEliminacion(s.ID, eventoPaso, s.CoordinadorID, rolCoordinacion, fmt.Sprintf("%s: %d", paso.nombre, n)); err != nil {
+			return resultado, err
+		}
+	}
+	return resultado, nil
+}
+
+func eliminarBusquedasEstudiante(terceroID int) (int, error) {
+	busquedas, err := listBusquedasGuardadasCRUD("TerceroId:" + strconv.Itoa(terceroID) + ",Rol:" + rolEstudiante)
+	if err != nil {
+		return 0, err
+	}
+	cfg := rootservices.GetConfig()
+	n := 0
+	for _, b := range busquedas {
+		endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, busquedaGuardadaResource, strconv.Itoa(b.ID))
+		var out interface{}
+		if err := helpers.DoJSON("DELETE", endpoint, nil, &out, cfg.RequestTimeout); err != nil && !helpers.IsHTTPError(err, http.StatusNotFound) {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// anonimizarComentariosEstudiante reemplaza el texto de los comentarios escritos por el estudiante.
+func anonimizarComentariosEstudiante(ctx stdctx.Context, terceroID int) (int, error) {
+	postulaciones, err := clients.CastorCRUD().ListPostulaciones(ctx, map[string]string{"EstudianteId": strconv.Itoa(terceroID)})
+	if err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	cfg := rootservices.GetConfig()
+	n := 0
+	for _, p := range postulaciones {
+		comentarios, err := listComentariosCRUD(p.Id)
+		if err != nil {
+			return n, err
+		}
+		for _, c := range comentarios {
+			autorID, _ := normalizeToInt(c["AutorId"])
+			rol := strings.ToUpper(strings.TrimSpace(normalizeToString(c["AutorRol"])))
+			if autorID != terceroID || rol != rolEstudiante {
+				continue
+			}
+			id, _ := normalizeToInt(c["Id"])
+			if id <= 0 {
+				continue
+			}
+			c["AutorId"] = 0
+			c["Mensaje"] = textoEliminado
+			endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, postulacionComentarioResource, strconv.Itoa(id))
+			var out map[string]interface{}
+			if err := helpers.DoJSON("PUT", endpoint, c, &out, cfg.RequestTimeout); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// anonimizarPostulacionesEstudiante desvincula las postulaciones del estudiante y retira la hoja de
+// vida; oferta, estado y fechas se conservan para las estadísticas.
+func anonimizarPostulacionesEstudiante(ctx stdctx.Context, terceroID int) (int, error) {
+	postulaciones, err := clients.CastorCRUD().ListPostulaciones(ctx, map[string]string{"EstudianteId": strconv.Itoa(terceroID)})
+	if err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	cfg := rootservices.GetConfig()
+	n := 0
+	for _, p := range postulaciones {
+		endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, postulacionResource, strconv.FormatInt(p.Id, 10))
+		var current map[string]interface{}
+		if err := helpers.DoJSON("GET", endpoint, nil, &current, cfg.RequestTimeout); err != nil {
+			if helpers.IsHTTPError(err, http.StatusNotFound) {
+				continue
+			}
+			return n, err
+		}
+		current["EstudianteId"] = 0
+		current["EnlaceDocHv"] = ""
+		var out map[string]interface{}
+		if err := helpers.DoJSON("PUT", endpoint, current, &out, cfg.RequestTimeout); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// anonimizarInvitacionesEstudiante desvincula las invitaciones del perfil y reemplaza el mensaje del
+// tutor; tutor, oferta, estado y fechas se conservan para las estadísticas. Debe correr antes de
+// anonimizar el perfil porque el CRUD resuelve el estudiante a través de él.
+func anonimizarInvitacionesEstudiante(terceroID int) (int, error) {
+	cfg := rootservices.GetConfig()
+	values := url.Values{}
+	values.Set("estudiante_id", strconv.Itoa(terceroID))
+	values.Set("limit", "0")
+	var raw []map[string]interface{}
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, invitacionesResource)
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	n := 0
+	for _, inv := range raw {
+		id, _ := normalizeToInt(inv["Id"])
+		if id <= 0 {
+			continue
+		}
+		inv["Mensaje"] = textoEliminado
+		inv["PerfilEstudianteId"] = 0
+		if _, ok := inv["PerfilId"]; ok {
+			inv["PerfilId"] = 0
+		}
+		var out map[string]interface{}
+		if err := helpers.DoJSON("PUT", rootservices.BuildURL(cfg.CastorCRUDBaseURL, invitacionesResource, strconv.Itoa(id)), inv, &out, cfg.RequestTimeout); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// eliminarVisitasPerfil borra el registro de tutores que vieron el perfil.
+func eliminarVisitasPerfil(ctx stdctx.Context, perfilID int) (int, error) {
+	if perfilID <= 0 {
+		return 0, nil
+	}
+	visitas, err := clients.CastorCRUD().ListPerfilVisitas(ctx, perfilID)
+	if err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	cfg := rootservices.GetConfig()
+	n := 0
+	for _, v := range visitas {
+		endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, visitaResource, strconv.FormatInt(v.Id, 10))
+		if err := helpers.DoJSON("DELETE", endpoint, nil, nil, cfg.RequestTimeout); err != nil && !helpers.IsHTTPError(err, http.StatusNotFound) {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// eliminarBookmarksPerfil borra los guardados y notas privadas de los tutores sobre el perfil.
+func eliminarBookmarksPerfil(perfilID int) (int, error) {
+	if perfilID <= 0 {
+		return 0, nil
+	}
+	bookmarks, err := listBookmarksCRUD(fmt.Sprintf("PerfilEstudianteId:%d", perfilID))
+	if err != nil {
+		return 0, err
+	}
+	cfg := rootservices.GetConfig()
+	n := 0
+	for _, b := range bookmarks {
+		endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, bookmarkResource, strconv.Itoa(b.ID))
+		if err := helpers.DoJSON("DELETE", endpoint, nil, nil, cfg.RequestTimeout); err != nil && !helpers.IsHTTPError(err, http.StatusNotFound) {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// anonimizarPerfilEstudiante vacía el contenido del perfil, lo desvincula del tercero y lo oculta.
+// Se conserva el proyecto curricular y las fechas para las estadísticas.
+func anonimizarPerfilEstudiante(perfilID int) (int, error) {
+	if perfilID <= 0 {
+		return 0, nil
+	}
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, estudiantePerfilResource, strconv.Itoa(perfilID))
+	body := map[string]interface{}{
+		"TerceroId":                0,
+		"Resumen":                  "",
+		"Habilidades":              "",
+		"CvDocumentoId":            nil,
+		"ModalidadPreferida":       "",
+		"CiudadPreferida":          "",
+		"Visible":                  false,
+		"TratamientoDatosAceptado": false,
+		"Anonimo":                  true,
+		"FechaModificacion":        nowISO(),
+	}
+	var out map[string]interface{}
+	if err := helpers.DoJSON("PUT", endpoint, body, &out, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	reindexarPerfil(perfilID)
+	return 1, nil
+}
+
+// registrarEventoEliminacion deja constancia en la bitácora de la solicitud. La confirmación y el
+// rechazo no avanzan si la entrada no se pudo escribir.
+func registrarEventoEliminacion(solicitudID int, accion string, actorID int, rol, detalle string) error {
+	logs.Info("solicitud de eliminación", solicitudID, accion, "actor", actorID, rol, detalle)
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, solicitudEliminacionEventoResource)
+	body := map[string]interface{}{
+		"SolicitudId": solicitudID,
+		"Accion":      accion,
+		"ActorId":     actorID,
+		"Rol":         rol,
+		"Detalle":     detalle,
+		"Fecha":       nowISO(),
+	}
+	var out map[string]interface{}
+	if err := helpers.DoJSON("POST", endpoint, body, &out, cfg.RequestTimeout); err != nil {
+		return helpers.NewAppError(http.StatusBadGateway, fmt.Sprintf("solicitud de eliminación %d: error registrando %s en la bitácora", solicitudID, accion), err)
+	}
+	return nil
+}
+
+func listEventosEliminacion(solicitudID int) ([]internaldto.SolicitudEliminacionEvento, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, solicitudEliminacionEventoResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+	values.Set("query", "SolicitudId:"+strconv.Itoa(solicitudID))
+	values.Set("sortby", "Id")
+	values.Set("order", "asc")
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return []internaldto.SolicitudEliminacionEvento{}, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando bitácora de la solicitud")
+	}
+	out := make([]internaldto.SolicitudEliminacionEvento, 0, len(raw))
+	for _, r := range raw {
+		e := internaldto.SolicitudEliminacionEvento{
+			Accion:  strings.ToUpper(strings.TrimSpace(normalizeToString(r["Accion"]))),
+			Rol:     strings.TrimSpace(normalizeToString(r["Rol"])),
+			Detalle: strings.TrimSpace(normalizeToString(r["Detalle"])),
+		}
+		e.ActorID, _ = normalizeToInt(r["ActorId"])
+		if t := parseTime(normalizeToString(r["Fecha"])); !t.IsZero() {
+			e.Fecha = &t
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func resumenResultado(resultado map[string]int) string {
+	keys := make([]string, 0, len(resultado))
+	for k := range resultado {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, resultado[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func getSolicitudEliminacionCRUD(id int) (solicitudEliminacion, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, solicitudEliminacionResource, strconv.Itoa(id))
+	var raw map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint, nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return solicitudEliminacion{}, helpers.NewAppError(http.StatusNotFound, "solicitud no encontrada", err)
+		}
+		return solicitudEliminacion{}, helpers.AsAppError(err, "error consultando solicitud de eliminación")
+	}
+	s := solicitudEliminacionFromCRUD(raw)
+	if s.ID == 0 {
+		return solicitudEliminacion{}, helpers.NewAppError(http.StatusNotFound, "solicitud no encontrada", nil)
+	}
+	return s, nil
+}
+
+// listSolicitudesEliminacionCRUD retorna las solicitudes que cumplen query, la más reciente primero.
+func listSolicitudesEliminacionCRUD(query string) ([]solicitudEliminacion, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, solicitudEliminacionResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+	if query != "" {
+		values.Set("query", query)
+	}
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando solicitudes de eliminación")
+	}
+	out := make([]solicitudEliminacion, 0, len(raw))
+	for _, r := range raw {
+		if s := solicitudEliminacionFromCRUD(r); s.ID > 0 {
+			out = append(out, s)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].ID > out[j].ID
+	})
+	return out, nil
+}
+
+func updateSolicitudEliminacionCRUD(s solicitudEliminacion) error {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, solicitudEliminacionResource, strconv.Itoa(s.ID))
+	body := s.toCRUD()
+	body["Id"] = s.ID
+	var out map[string]interface{}
+	if err := helpers.DoJSON("PUT", endpoint, body, &out, cfg.RequestTimeout); err != nil {
+		return helpers.AsAppError(err, "error actualizando solicitud de eliminación")
+	}
+	return nil
+}
+
+func solicitudEliminacionFromCRUD(raw map[string]interface{}) solicitudEliminacion {
+	var s solicitudEliminacion
+	if len(raw) == 0 {
+		return s
+	}
+	s.ID, _ = normalizeToInt(raw["Id"])
+	s.TerceroID, _ = normalizeToInt(raw["TerceroId"])
+	s.PerfilID, _ = normalizeToInt(raw["PerfilId"])
+	s.Estado = strings.ToUpper(strings.TrimSpace(normalizeToString(raw["Estado"])))
+	s.Motivo = strings.TrimSpace(normalizeToString(raw["Motivo"]))
+	s.Observacion = strings.TrimSpace(normalizeToString(raw["Observacion"]))
+	s.CoordinadorID, _ = normalizeToInt(raw["CoordinadorId"])
+	s.FechaSolicitud = strings.TrimSpace(normalizeToString(raw["FechaSolicitud"]))
+	s.FechaResolucion = strings.TrimSpace(normalizeToString(raw["FechaResolucion"]))
+	if r := strings.TrimSpace(normalizeToString(raw["Resultado"])); r != "" {
+		_ = json.Unmarshal([]byte(r), &s.Resultado)
+	}
+	return s
+}
+
+func (s solicitudEliminacion) toCRUD() map[string]interface{} {
+	body := map[string]interface{}{
+		"TerceroId":       s.TerceroID,
+		"PerfilId":        s.PerfilID,
+		"Estado":          s.Estado,
+		"Motivo":          s.Motivo,
+		"Observacion":     s.Observacion,
+		"CoordinadorId":   s.CoordinadorID,
+		"Resultado":       "",
+		"FechaSolicitud":  s.FechaSolicitud,
+		"FechaResolucion": nil,
+	}
+	if len(s.Resultado) > 0 {
+		resultado, _ := json.Marshal(s.Resultado)
+		body["Resultado"] = string(resultado)
+	}
+	if s.FechaResolucion != "" {
+		body["FechaResolucion"] = s.FechaResolucion
+	}
+	return body
+}
+
+func (s solicitudEliminacion) toDTO() internaldto.SolicitudEliminacion {
+	out := internaldto.SolicitudEliminacion{
+		ID:            s.ID,
+		TerceroID:     s.TerceroID,
+		PerfilID:      s.PerfilID,
+		Estado:        s.Estado,
+		Motivo:        s.Motivo,
+		Observacion:   s.Observacion,
+		CoordinadorID: s.CoordinadorID,
+		Resultado:     s.Resultado,
+	}
+	if t := parseTime(s.FechaSolicitud); !t.IsZero() {
+		out.FechaSolicitud = &t
+	}
+	if t := parseTime(s.FechaResolucion); !t.IsZero() {
+		out.FechaResolucion = &t
+	}
+	return out
+}
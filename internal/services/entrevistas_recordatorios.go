@@ -0,0 +1,101 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	beego "github.com/beego/beego/v2/server/web"
+
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+)
+
+const (
+	defaultRecordatorioAntelacionMin = 24 * 60
+	defaultRecordatorioIntervaloMin  = 15
+	plantillaRecordatorioEntrevista  = "entrevista_recordatorio"
+)
+
+var recordatoriosOnce sync.Once
+
+// IniciarRecordatoriosEntrevistas arranca el proceso periódico que envía recordatorios de
+// entrevistas confirmadas. Se desactiva con entrevistas_recordatorio_intervalo_min = 0.
+func IniciarRecordatoriosEntrevistas() {
+	recordatoriosOnce.Do(func() {
+		intervalo := configMinutos("ENTREVISTAS_RECORDATORIO_INTERVALO_MIN", "entrevistas_recordatorio_intervalo_min", defaultRecordatorioIntervaloMin)
+		antelacion := configMinutos("ENTREVISTAS_RECORDATORIO_ANTELACION_MIN", "entrevistas_recordatorio_antelacion_min", defaultRecordatorioAntelacionMin)
+		if intervalo <= 0 || antelacion <= 0 {
+			return
+		}
+		go func() {
+			ticker := time.NewTicker(intervalo)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				enviarRecordatoriosEntrevistas(now, antelacion)
+			}
+		}()
+	})
+}
+
+// enviarRecordatoriosEntrevistas notifica a tutor y estudiante las entrevistas que inician
+// dentro de la ventana de antelación y las marca para no repetir el envío.
+func enviarRecordatoriosEntrevistas(now time.Time, antelacion time.Duration) {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("recordatorios de entrevistas:", r)
+		}
+	}()
+
+	pendientes, err := listEntrevistasCRUD(fmt.Sprintf("Estado:%s,RecordatorioEnviado:false", EntrevistaEstadoConfirmada))
+	if err != nil {
+		logs.Warn("recordatorios de entrevistas:", err)
+		return
+	}
+	limite := now.Add(antelacion)
+	for _, e := range pendientes {
+		if e.RecordatorioEnviado || e.Inicio.IsZero() || !e.Inicio.After(now) || e.Inicio.After(limite) {
+			continue
+		}
+		for _, destino := range []int{e.TutorID, e.EstudianteID} {
+			if err := internalhelpers.Notificaciones.Send(nil, destino, "Recordatorio de entrevista", plantillaRecordatorioEntrevista, e.toMap()); err != nil {
+				logs.Warn("recordatorio entrevista", e.ID, "tercero", destino, ":", err)
+			}
+		}
+		e.RecordatorioEnviado = true
+		if err := updateEntrevistaCRUD(e); err != nil {
+			logs.Warn("recordatorio entrevista", e.ID, ":", err)
+		}
+	}
+}
+
+func configMinutos(envKey, confKey string, def int) time.Duration {
+	return time.Duration(configEntero(envKey, confKey, def)) * time.Minute
+}
+
+// configEntero lee un entero de la variable de entorno o, en su defecto, de app.conf.
+func configEntero(envKey, confKey string, def int) int {
+	if v := strings.TrimSpace(os.Getenv(envKey)); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+		return def
+	}
+	if n, err := beego.AppConfig.Int(confKey); err == nil {
+		return n
+	}
+	return def
+}
+
+func configTexto(envKey, confKey, def string) string {
+	if v := strings.TrimSpace(os.Getenv(envKey)); v != "" {
+		return v
+	}
+	if v, err := beego.AppConfig.String(confKey); err == nil && strings.TrimSpace(v) != "" {
+		return strings.TrimSpace(v)
+	}
+	return def
+}
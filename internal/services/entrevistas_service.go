@@ -0,0 +1,528 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	beegocontext "github.com/beego/beego/v2/server/web/context"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	entrevistaResource = "entrevista"
+
+	EntrevistaEstadoPropuesta  = "PROPUESTA"
+	EntrevistaEstadoConfirmada = "CONFIRMADA"
+	EntrevistaEstadoCancelada  = "CANCELADA"
+
+	maxSlotsEntrevista     = 10
+	maxDuracionEntrevista  = 8 * time.Hour
+	plantillaEntrevista    = "entrevista_pasantia"
+	entrevistaICSUIDDomain = "pasantias.udistrital.edu.co"
+)
+
+// entrevista es la representación interna del registro entrevista del CRUD.
+type entrevista struct {
+	ID                  int
+	PostulacionID       int64
+	OfertaID            int
+	TutorID             int
+	EstudianteID        int
+	Estado              string
+	PropuestaPor        string
+	Slots               []internaldto.EntrevistaSlot
+	Inicio              time.Time
+	Fin                 time.Time
+	Lugar               string
+	EnlaceVideo         string
+	Notas               string
+	MotivoCancelacion   string
+	Secuencia           int
+	RecordatorioEnviado bool
+	FechaCreacion       string
+}
+
+// ProponerEntrevista registra las franjas propuestas por el tutor para una postulación preseleccionada.
+func ProponerEntrevista(ctx *beegocontext.Context, tutorID int, postulacionID int64, payload internaldto.EntrevistaPropuesta) (map[string]interface{}, error) {
+	stdCtx := requestContext(ctx)
+	post, rol, err := autorizarPostulacion(stdCtx, postulacionID, tutorID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if rol != rolTutor {
+		return nil, helpers.NewAppError(http.StatusForbidden, "solo el tutor puede proponer entrevistas", nil)
+	}
+	if !strings.EqualFold(strings.TrimSpace(post.EstadoPostulacion), obtenerPreselectCodigo()) {
+		return nil, helpers.NewAppError(http.StatusConflict, "solo se pueden agendar entrevistas para postulaciones preseleccionadas", nil)
+	}
+
+	existentes, err := listEntrevistasCRUD(fmt.Sprintf("PostulacionId:%d", postulacionID))
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range existentes {
+		if e.Estado != EntrevistaEstadoCancelada {
+			return nil, helpers.NewAppError(http.StatusConflict, "la postulación ya tiene una entrevista activa; reprograme o cancele la existente", nil)
+		}
+	}
+
+	slots, lugar, enlace, err := validarPropuestaEntrevista(payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := verificarConflictosTutor(tutorID, 0, slots); err != nil {
+		return nil, err
+	}
+
+	nueva := entrevista{
+		PostulacionID: postulacionID,
+		OfertaID:      int(post.OfertaId),
+		TutorID:       tutorID,
+		EstudianteID:  int(post.EstudianteId),
+		Estado:        EntrevistaEstadoPropuesta,
+		PropuestaPor:  rolTutor,
+		Slots:         slots,
+		Lugar:         lugar,
+		EnlaceVideo:   enlace,
+		Notas:         strings.TrimSpace(payload.Notas),
+		FechaCreacion: nowISO(),
+	}
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, entrevistaResource)
+	var created map[string]interface{}
+	if err := helpers.DoJSON("POST", endpoint, nueva.toCRUD(), &created, cfg.RequestTimeout); err != nil {
+		return nil, helpers.AsAppError(err, "error registrando entrevista")
+	}
+	if id, ok := normalizeToInt(created["Id"]); ok {
+		nueva.ID = id
+	}
+
+	notificarEntrevista(ctx, nueva, nueva.EstudianteID, "Nueva propuesta de entrevista", plantillaEntrevista)
+	return nueva.toMap(), nil
+}
+
+// ListarEntrevistasPostulacion retorna las entrevistas de la postulación para el tutor o el estudiante.
+func ListarEntrevistasPostulacion(ctx *beegocontext.Context, postulacionID int64, tutorID, estudianteID int) (map[string]interface{}, error) {
+	if _, _, err := autorizarPostulacion(requestContext(ctx), postulacionID, tutorID, estudianteID); err != nil {
+		return nil, err
+	}
+	entrevistas, err := listEntrevistasCRUD(fmt.Sprintf("PostulacionId:%d", postulacionID))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entrevistas, func(i, j int) bool {
+		return entrevistas[i].ID > entrevistas[j].ID
+	})
+	items := make([]map[string]interface{}, 0, len(entrevistas))
+	for _, e := range entrevistas {
+		items = append(items, e.toMap())
+	}
+	return map[string]interface{}{
+		"postulacion_id": postulacionID,
+		"items":          items,
+		"total":          len(items),
+	}, nil
+}
+
+// GetEntrevista retorna el detalle de una entrevista.
+func GetEntrevista(ctx *beegocontext.Context, entrevistaID, tutorID, estudianteID int) (map[string]interface{}, error) {
+	e, _, err := autorizarEntrevista(entrevistaID, tutorID, estudianteID)
+	if err != nil {
+		return nil, err
+	}
+	return e.toMap(), nil
+}
+
+// ConfirmarEntrevista fija una de las franjas propuestas; la confirma la contraparte de quien propuso.
+func ConfirmarEntrevista(ctx *beegocontext.Context, entrevistaID, tutorID, estudianteID int, payload internaldto.EntrevistaConfirmacion) (map[string]interface{}, error) {
+	e, rol, err := autorizarEntrevista(entrevistaID, tutorID, estudianteID)
+	if err != nil {
+		return nil, err
+	}
+	if e.Estado != EntrevistaEstadoPropuesta {
+		return nil, helpers.NewAppError(http.StatusConflict, "la entrevista no está pendiente de confirmación", nil)
+	}
+	if rol == e.PropuestaPor {
+		return nil, helpers.NewAppError(http.StatusForbidden, "la franja debe elegirla la contraparte de quien la propuso", nil)
+	}
+	if payload.SlotIndex < 0 || payload.SlotIndex >= len(e.Slots) {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "slot_index inválido", nil)
+	}
+	slot := e.Slots[payload.SlotIndex]
+	if !slot.Inicio.After(time.Now()) {
+		return nil, helpers.NewAppError(http.StatusConflict, "la franja seleccionada ya pasó", nil)
+	}
+	if err := verificarConflictosTutor(e.TutorID, e.ID, []internaldto.EntrevistaSlot{slot}); err != nil {
+		return nil, err
+	}
+
+	e.Estado = EntrevistaEstadoConfirmada
+	e.Inicio, e.Fin = slot.Inicio, slot.Fin
+	e.RecordatorioEnviado = false
+	if err := updateEntrevistaCRUD(e); err != nil {
+		return nil, err
+	}
+
+	notificarEntrevista(ctx, e, contraparteEntrevista(e, rol), "Entrevista confirmada", internalhelpers.PlantillaEntrevistaProgramada)
+	return e.toMap(), nil
+}
+
+// ReprogramarEntrevista reemplaza las franjas; la otra parte debe volver a confirmar.
+func ReprogramarEntrevista(ctx *beegocontext.Context, entrevistaID, tutorID, estudianteID int, payload internaldto.EntrevistaPropuesta) (map[string]interface{}, error) {
+	e, rol, err := autorizarEntrevista(entrevistaID, tutorID, estudianteID)
+	if err != nil {
+		return nil, err
+	}
+	if e.Estado == EntrevistaEstadoCancelada {
+		return nil, helpers.NewAppError(http.StatusConflict, "la entrevista está cancelada", nil)
+	}
+
+	if strings.TrimSpace(payload.Lugar) == "" && strings.TrimSpace(payload.EnlaceVideo) == "" {
+		payload.Lugar, payload.EnlaceVideo = e.Lugar, e.EnlaceVideo
+	}
+	slots, lugar, enlace, err := validarPropuestaEntrevista(payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := verificarConflictosTutor(e.TutorID, e.ID, slots); err != nil {
+		return nil, err
+	}
+
+	e.Estado = EntrevistaEstadoPropuesta
+	e.PropuestaPor = rol
+	e.Slots = slots
+	e.Lugar, e.EnlaceVideo = lugar, enlace
+	if notas := strings.TrimSpace(payload.Notas); notas != "" {
+		e.Notas = notas
+	}
+	e.Inicio, e.Fin = time.Time{}, time.Time{}
+	e.RecordatorioEnviado = false
+	e.Secuencia++
+	if err := updateEntrevistaCRUD(e); err != nil {
+		return nil, err
+	}
+
+	notificarEntrevista(ctx, e, contraparteEntrevista(e, rol), "Entrevista reprogramada", plantillaEntrevista)
+	return e.toMap(), nil
+}
+
+// CancelarEntrevista cancela la entrevista a solicitud de cualquiera de las partes.
+func CancelarEntrevista(ctx *beegocontext.Context, entrevistaID, tutorID, estudianteID int, payload internaldto.EntrevistaCancelacion) (map[string]interface{}, error) {
+	e, rol, err := autorizarEntrevista(entrevistaID, tutorID, estudianteID)
+	if err != nil {
+		return nil, err
+	}
+	if e.Estado == EntrevistaEstadoCancelada {
+		return nil, helpers.NewAppError(http.StatusConflict, "la entrevista ya está cancelada", nil)
+	}
+
+	e.Estado = EntrevistaEstadoCancelada
+	e.MotivoCancelacion = strings.TrimSpace(payload.Motivo)
+	e.Secuencia++
+	if err := updateEntrevistaCRUD(e); err != nil {
+		return nil, err
+	}
+
+	notificarEntrevista(ctx, e, contraparteEntrevista(e, rol), "Entrevista cancelada", plantillaEntrevista)
+	return e.toMap(), nil
+}
+
+// ExportarEntrevistaICS genera el archivo .ics de una entrevista confirmada o cancelada.
+func ExportarEntrevistaICS(ctx *beegocontext.Context, entrevistaID, tutorID, estudianteID int) ([]byte, string, error) {
+	e, _, err := autorizarEntrevista(entrevistaID, tutorID, estudianteID)
+	if err != nil {
+		return nil, "", err
+	}
+	if e.Inicio.IsZero() || e.Fin.IsZero() {
+		return nil, "", helpers.NewAppError(http.StatusConflict, "la entrevista aún no tiene una franja confirmada", nil)
+	}
+
+	stdCtx := requestContext(ctx)
+	titulo := "Entrevista de pasantía"
+	if oferta, err := rootservices.GetOferta(int64(e.OfertaID)); err == nil && oferta != nil && strings.TrimSpace(oferta.Titulo) != "" {
+		titulo = "Entrevista de pasantía: " + strings.TrimSpace(oferta.Titulo)
+	}
+	nombres := newNombreResolver(stdCtx)
+
+	descripcion := fmt.Sprintf("Tutor: %s\nEstudiante: %s", nombres.get(e.TutorID), nombres.get(e.EstudianteID))
+	if e.EnlaceVideo != "" {
+		descripcion += "\nEnlace: " + e.EnlaceVideo
+	}
+	if e.Notas != "" {
+		descripcion += "\n" + e.Notas
+	}
+
+	ics := internalhelpers.BuildICS(internalhelpers.ICalEvento{
+		UID:         fmt.Sprintf("entrevista-%d@%s", e.ID, entrevistaICSUIDDomain),
+		Resumen:     titulo,
+		Descripcion: descripcion,
+		Lugar:       firstNonEmptyString(e.Lugar, e.EnlaceVideo),
+		URL:         e.EnlaceVideo,
+		Inicio:      e.Inicio,
+		Fin:         e.Fin,
+		Cancelado:   e.Estado == EntrevistaEstadoCancelada,
+		Secuencia:   e.Secuencia,
+		Organizador: nombres.get(e.TutorID),
+	})
+	return ics, fmt.Sprintf("entrevista-%d.ics", e.ID), nil
+}
+
+func autorizarEntrevista(entrevistaID, tutorID, estudianteID int) (entrevista, string, error) {
+	if entrevistaID <= 0 {
+		return entrevista{}, "", helpers.NewAppError(http.StatusBadRequest, "id inválido", nil)
+	}
+	e, err := getEntrevistaCRUD(entrevistaID)
+	if err != nil {
+		return entrevista{}, "", err
+	}
+	switch {
+	case tutorID > 0 && e.TutorID == tutorID:
+		return e, rolTutor, nil
+	case estudianteID > 0 && e.EstudianteID == estudianteID:
+		return e, rolEstudiante, nil
+	default:
+		return entrevista{}, "", helpers.NewAppError(http.StatusForbidden, "no autorizado para esta entrevista", nil)
+	}
+}
+
+func validarPropuestaEntrevista(payload internaldto.EntrevistaPropuesta) ([]internaldto.EntrevistaSlot, string, string, error) {
+	lugar := strings.TrimSpace(payload.Lugar)
+	enlace := strings.TrimSpace(payload.EnlaceVideo)
+	if lugar == "" && enlace == "" {
+		return nil, "", "", helpers.NewAppError(http.StatusBadRequest, "lugar o enlace_video requerido", nil)
+	}
+	if enlace != "" {
+		u, err := url.Parse(enlace)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, "", "", helpers.NewAppError(http.StatusBadRequest, "enlace_video inválido", err)
+		}
+	}
+
+	if len(payload.Slots) == 0 {
+		return nil, "", "", helpers.NewAppError(http.StatusBadRequest, "al menos una franja es requerida", nil)
+	}
+	if len(payload.Slots) > maxSlotsEntrevista {
+		return nil, "", "", helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("máximo %d franjas", maxSlotsEntrevista), nil)
+	}
+
+	now := time.Now()
+	slots := make([]internaldto.EntrevistaSlot, 0, len(payload.Slots))
+	for i, s := range payload.Slots {
+		if s.Inicio.IsZero() || s.Fin.IsZero() || !s.Fin.After(s.Inicio) {
+			return nil, "", "", helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("franja %d inválida", i+1), nil)
+		}
+		if !s.Inicio.After(now) {
+			return nil, "", "", helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("franja %d debe ser futura", i+1), nil)
+		}
+		if s.Fin.Sub(s.Inicio) > maxDuracionEntrevista {
+			return nil, "", "", helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("franja %d excede la duración máxima", i+1), nil)
+		}
+		slots = append(slots, internaldto.EntrevistaSlot{Inicio: s.Inicio.UTC(), Fin: s.Fin.UTC()})
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].Inicio.Before(slots[j].Inicio) })
+	return slots, lugar, enlace, nil
+}
+
+// verificarConflictosTutor rechaza franjas que se crucen con entrevistas confirmadas del tutor.
+func verificarConflictosTutor(tutorID, excluirID int, slots []internaldto.EntrevistaSlot) error {
+	confirmadas, err := listEntrevistasCRUD(fmt.Sprintf("TutorId:%d,Estado:%s", tutorID, EntrevistaEstadoConfirmada))
+	if err != nil {
+		return err
+	}
+	for _, s := range slots {
+		for _, e := range confirmadas {
+			if e.ID == excluirID || e.Estado != EntrevistaEstadoConfirmada {
+				continue
+			}
+			if s.Inicio.Before(e.Fin) && e.Inicio.Before(s.Fin) {
+				return helpers.NewAppError(http.StatusConflict, fmt.Sprintf(
+					"la franja %s se cruza con otra entrevista confirmada del tutor (%s - %s)",
+					s.Inicio.Format(time.RFC3339), e.Inicio.Format(time.RFC3339), e.Fin.Format(time.RFC3339),
+				), nil)
+			}
+		}
+	}
+	return nil
+}
+
+func contraparteEntrevista(e entrevista, rol string) int {
+	if rol == rolTutor {
+		return e.EstudianteID
+	}
+	return e.TutorID
+}
+
+// notificarEntrevista envía los datos de la entrevista junto con el nombre del destinatario y el
+// título de la oferta, que usan las plantillas, sin interrumpir la operación si el servicio falla.
+func notificarEntrevista(ctx *beegocontext.Context, e entrevista, destinoID int, asunto, plantilla string) {
+	datos := e.toMap()
+	for k, v := range datosNotificacionOferta(requestContext(ctx), destinoID, int64(e.OfertaID)) {
+		datos[k] = v
+	}
+	_ = internalhelpers.Notificaciones.Send(ctx, destinoID, asunto, plantilla, datos)
+}
+
+func getEntrevistaCRUD(id int) (entrevista, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, entrevistaResource, strconv.Itoa(id))
+	var raw map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint, nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return entrevista{}, helpers.NewAppError(http.StatusNotFound, "entrevista no encontrada", err)
+		}
+		return entrevista{}, helpers.AsAppError(err, "error consultando entrevista")
+	}
+	e := entrevistaFromCRUD(raw)
+	if e.ID == 0 {
+		return entrevista{}, helpers.NewAppError(http.StatusNotFound, "entrevista no encontrada", nil)
+	}
+	return e, nil
+}
+
+func listEntrevistasCRUD(query string) ([]entrevista, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, entrevistaResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+	values.Set("query", query)
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando entrevistas")
+	}
+	out := make([]entrevista, 0, len(raw))
+	for _, r := range raw {
+		if e := entrevistaFromCRUD(r); e.ID > 0 {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func updateEntrevistaCRUD(e entrevista) error {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, entrevistaResource, strconv.Itoa(e.ID))
+	body := e.toCRUD()
+	body["Id"] = e.ID
+	var out map[string]interface{}
+	if err := helpers.DoJSON("PUT", endpoint, body, &out, cfg.RequestTimeout); err != nil {
+		return helpers.AsAppError(err, "error actualizando entrevista")
+	}
+	return nil
+}
+
+func entrevistaFromCRUD(raw map[string]interface{}) entrevista {
+	var e entrevista
+	if len(raw) == 0 {
+		return e
+	}
+	e.ID, _ = normalizeToInt(raw["Id"])
+	e.PostulacionID, _ = normalizeToInt64(raw["PostulacionId"])
+	e.OfertaID, _ = normalizeToInt(raw["OfertaPasantiaId"])
+	e.TutorID, _ = normalizeToInt(raw["TutorId"])
+	e.EstudianteID, _ = normalizeToInt(raw["EstudianteId"])
+	e.Estado = strings.ToUpper(strings.TrimSpace(normalizeToString(raw["Estado"])))
+	e.PropuestaPor = strings.ToUpper(strings.TrimSpace(normalizeToString(raw["PropuestaPor"])))
+	e.Inicio = parseTime(normalizeToString(raw["FechaInicio"]))
+	e.Fin = parseTime(normalizeToString(raw["FechaFin"]))
+	e.Lugar = strings.TrimSpace(normalizeToString(raw["Lugar"]))
+	e.EnlaceVideo = strings.TrimSpace(normalizeToString(raw["EnlaceVideo"]))
+	e.Notas = strings.TrimSpace(normalizeToString(raw["Notas"]))
+	e.MotivoCancelacion = strings.TrimSpace(normalizeToString(raw["MotivoCancelacion"]))
+	e.Secuencia, _ = normalizeToInt(raw["Secuencia"])
+	e.RecordatorioEnviado = normalizeToBool(raw["RecordatorioEnviado"], false)
+	e.FechaCreacion = strings.TrimSpace(normalizeToString(raw["FechaCreacion"]))
+	if slots := strings.TrimSpace(normalizeToString(raw["Slots"])); slots != "" {
+		_ = json.Unmarshal([]byte(slots), &e.Slots)
+	}
+	return e
+}
+
+func (e entrevista) toCRUD() map[string]interface{} {
+	slots, _ := json.Marshal(e.Slots)
+	body := map[string]interface{}{
+		"PostulacionId":       e.PostulacionID,
+		"OfertaPasantiaId":    e.OfertaID,
+		"TutorId":             e.TutorID,
+		"EstudianteId":        e.EstudianteID,
+		"Estado":              e.Estado,
+		"PropuestaPor":        e.PropuestaPor,
+		"Slots":               string(slots),
+		"Lugar":               e.Lugar,
+		"EnlaceVideo":         e.EnlaceVideo,
+		"Notas":               e.Notas,
+		"MotivoCancelacion":   e.MotivoCancelacion,
+		"Secuencia":           e.Secuencia,
+		"RecordatorioEnviado": e.RecordatorioEnviado,
+		"FechaModificacion":   nowISO(),
+		"FechaInicio":         nil,
+		"FechaFin":            nil,
+	}
+	if e.FechaCreacion != "" {
+		body["FechaCreacion"] = e.FechaCreacion
+	}
+	if !e.Inicio.IsZero() {
+		body["FechaInicio"] = e.Inicio.UTC().Format(time.RFC3339)
+		body["FechaFin"] = e.Fin.UTC().Format(time.RFC3339)
+	}
+	return body
+}
+
+func (e entrevista) toMap() map[string]interface{} {
+	slots := e.Slots
+	if slots == nil {
+		slots = []internaldto.EntrevistaSlot{}
+	}
+	modalidad := "PRESENCIAL"
+	if e.EnlaceVideo != "" {
+		modalidad = "VIRTUAL"
+	}
+	out := map[string]interface{}{
+		"id":             e.ID,
+		"postulacion_id": e.PostulacionID,
+		"oferta_id":      e.OfertaID,
+		"tutor_id":       e.TutorID,
+		"estudiante_id":  e.EstudianteID,
+		"estado":         e.Estado,
+		"propuesta_por":  e.PropuestaPor,
+		"slots":          slots,
+		"modalidad":      modalidad,
+		"lugar":          e.Lugar,
+		"enlace_video":   e.EnlaceVideo,
+		"notas":          e.Notas,
+		"secuencia":      e.Secuencia,
+		"inicio":         nil,
+		"fin":            nil,
+	}
+	if !e.Inicio.IsZero() {
+		out["inicio"] = e.Inicio.UTC().Format(time.RFC3339)
+		out["fin"] = e.Fin.UTC().Format(time.RFC3339)
+	}
+	if e.MotivoCancelacion != "" {
+		out["motivo_cancelacion"] = e.MotivoCancelacion
+	}
+	return out
+}
+
+func firstNonEmptyString(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
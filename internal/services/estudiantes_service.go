@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"sort"
 	"strings"
 	"time"
 
@@ -146,6 +145,9 @@ func UpsertPerfil(ctx *context.Context, terceroID int, payload internaldto.Estud
 	if err != nil {
 		return nil, err
 	}
+	if err := prepararConsentimientoPerfil(ctx, terceroID, record, &payload); err != nil {
+		return nil, err
+	}
 
 	if record == nil {
 		return crearPerfil(terceroID, payload)
@@ -186,13 +188,19 @@ func ActualizarPerfil(ctx *context.Context, terceroID int, payload internaldto.E
 	if err := validarCvDocumentoID(ctx, payload.CVDocumentoID); err != nil {
 		return nil, err
 	}
+	if err := prepararConsentimientoPerfil(ctx, terceroID, record, &payload); err != nil {
+		return nil, err
+	}
 
 	if payload.ProyectoCurricularID == nil &&
 		payload.Resumen == nil &&
 		payload.Habilidades == nil &&
 		payload.CVDocumentoID == nil &&
 		payload.Visible == nil &&
-		payload.TratamientoDatosAceptado == nil {
+		payload.TratamientoDatosAceptado == nil &&
+		payload.ModalidadPreferida == nil &&
+		payload.CiudadPreferida == nil &&
+		payload.Anonimo == nil {
 		return mapPerfil(*record), nil
 	}
 
@@ -200,76 +208,6 @@ func ActualizarPerfil(ctx *context.Context, terceroID int, payload internaldto.E
 	return actualizarPerfil(record.Id, payload)
 }
 
-// ListarVisitasPorEstudiante resume las visitas al perfil agrupadas por tutor.
-func ListarVisitasPorEstudiante(ctx *context.Context, estudianteID int) (map[string]interface{}, error) {
-	stdCtx := requestContext(ctx)
-	crud := clients.CastorCRUD()
-
-	perfil, err := crud.GetPerfilByTerceroID(stdCtx, estudianteID)
-	if err != nil {
-		return nil, helpers.AsAppError(err, "error consultando perfil")
-	}
-	if perfil == nil {
-		return nil, helpers.NewAppError(http.StatusNotFound, "perfil no encontrado", nil)
-	}
-
-	visitas, err := crud.ListPerfilVisitas(stdCtx, perfil.Id)
-	if err != nil {
-		return nil, helpers.AsAppError(err, "error consultando visitas")
-	}
-
-	totalVisitas := len(visitas)
-	agrupado := map[int]*visitaResumen{}
-
-	for _, visita := range visitas {
-		if visita.TutorId == 0 {
-			continue
-		}
-		entry, ok := agrupado[visita.TutorId]
-		if !ok {
-			entry = &visitaResumen{TutorID: visita.TutorId}
-			agrupado[visita.TutorId] = entry
-		}
-		entry.Total++
-		if visita.FechaVisita.After(entry.Ultima) {
-			entry.Ultima = visita.FechaVisita
-		} else if entry.Ultima.IsZero() {
-			entry.Ultima = visita.FechaVisita
-		}
-	}
-
-	items := make([]visitaResumen, 0, len(agrupado))
-	for _, v := range agrupado {
-		items = append(items, *v)
-	}
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].Ultima.After(items[j].Ultima)
-	})
-
-	payload := make([]map[string]interface{}, 0, len(items))
-	for _, item := range items {
-		entry := map[string]interface{}{
-			"tutor_id": item.TutorID,
-			"total":    item.Total,
-		}
-		if !item.Ultima.IsZero() {
-			entry["ultima_visita"] = item.Ultima.Format(time.RFC3339)
-		}
-		payload = append(payload, entry)
-	}
-
-	return map[string]interface{}{
-		"items": payload,
-		"total": totalVisitas,
-	}, nil
-}
-
-type visitaResumen struct {
-	TutorID int
-	Total   int
-	Ultima  time.Time
-}
-
 func crearPerfil(terceroID int, payload internaldto.EstudiantePerfilUpsert) (map[string]interface{}, error) {
 	cfg := rootservices.GetConfig()
 	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, estudiantePerfilResource)
@@ -288,25 +226,51 @@ func crearPerfil(terceroID int, payload internaldto.EstudiantePerfilUpsert) (map
 	}
 	if payload.TratamientoDatosAceptado != nil {
 		body["TratamientoDatosAceptado"] = *payload.TratamientoDatosAceptado
+		if *payload.TratamientoDatosAceptado {
+			sellarConsentimiento(body)
+		}
+	}
+	if payload.Anonimo != nil {
+		body["Anonimo"] = *payload.Anonimo
 	}
 	if payload.Resumen != nil {
 		body["Resumen"] = strings.TrimSpace(*payload.Resumen)
 	}
 	if payload.Habilidades != nil {
-		body["Habilidades"] = strings.TrimSpace(*payload.Habilidades)
+		habilidades, err := normalizarHabilidadesPerfil(*payload.Habilidades, true)
+		if err != nil {
+			return nil, err
+		}
+		body["Habilidades"] = serializarHabilidadesPerfil(habilidades)
 	}
 	if payload.CVDocumentoID != nil {
 		body["CvDocumentoId"] = *payload.CVDocumentoID
 	}
+	if payload.ModalidadPreferida != nil {
+		body["ModalidadPreferida"] = strings.ToUpper(strings.TrimSpace(*payload.ModalidadPreferida))
+	}
+	if payload.CiudadPreferida != nil {
+		body["CiudadPreferida"] = strings.TrimSpace(*payload.CiudadPreferida)
+	}
 
 	var created perfilRecord
 	if err := helpers.DoJSON("POST", endpoint, body, &created, cfg.RequestTimeout); err != nil {
 		return nil, helpers.AsAppError(err, "error creando perfil de estudiante")
 	}
+	reindexarPerfil(created.Id)
+	publicarPerfilActualizado(terceroID, created.Id)
 	return mapPerfil(created), nil
 }
 
 func actualizarPerfil(perfilID int, payload internaldto.EstudiantePerfilUpsert) (map[string]interface{}, error) {
+	updated, err := actualizarPerfilRecord(perfilID, payload)
+	if err != nil {
+		return nil, err
+	}
+	return mapPerfil(updated), nil
+}
+
+func actualizarPerfilRecord(perfilID int, payload internaldto.EstudiantePerfilUpsert) (perfilRecord, error) {
 	cfg := rootservices.GetConfig()
 	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, estudiantePerfilResource, fmt.Sprintf("%d", perfilID))
 
@@ -321,24 +285,55 @@ func actualizarPerfil(perfilID int, payload internaldto.EstudiantePerfilUpsert)
 		body["Resumen"] = strings.TrimSpace(*payload.Resumen)
 	}
 	if payload.Habilidades != nil {
-		body["Habilidades"] = strings.TrimSpace(*payload.Habilidades)
+		habilidades, err := normalizarHabilidadesPerfil(*payload.Habilidades, true)
+		if err != nil {
+			return perfilRecord{}, err
+		}
+		body["Habilidades"] = serializarHabilidadesPerfil(habilidades)
 	}
 	if payload.CVDocumentoID != nil {
 		body["CvDocumentoId"] = *payload.CVDocumentoID
 	}
+	if payload.ModalidadPreferida != nil {
+		body["ModalidadPreferida"] = strings.ToUpper(strings.TrimSpace(*payload.ModalidadPreferida))
+	}
+	if payload.CiudadPreferida != nil {
+		body["CiudadPreferida"] = strings.TrimSpace(*payload.CiudadPreferida)
+	}
 	if payload.Visible != nil {
 		body["Visible"] = *payload.Visible
 	}
 	if payload.TratamientoDatosAceptado != nil {
 		body["TratamientoDatosAceptado"] = *payload.TratamientoDatosAceptado
+		if *payload.TratamientoDatosAceptado {
+			sellarConsentimiento(body)
+		} else {
+			// Revocar el tratamiento de datos oculta el perfil.
+			body["Visible"] = false
+		}
+	}
+	if payload.Anonimo != nil {
+		body["Anonimo"] = *payload.Anonimo
 	}
 
 	var updated perfilRecord
 	fmt.Println("BODY CONSULTA PUT VISIBILIDAD", body)
 	if err := helpers.DoJSON("PUT", endpoint, body, &updated, cfg.RequestTimeout); err != nil {
-		return nil, helpers.AsAppError(err, "error actualizando perfil de estudiante")
+		return perfilRecord{}, helpers.AsAppError(err, "error actualizando perfil de estudiante")
 	}
-	return mapPerfil(updated), nil
+	reindexarPerfil(perfilID)
+	publicarPerfilActualizado(updated.TerceroId, perfilID)
+	return updated, nil
+}
+
+// publicarPerfilActualizado avisa que cambió el perfil (datos, visibilidad o consentimiento) para
+// que los tableros en caché que lo muestran se recalculen.
+func publicarPerfilActualizado(terceroID, perfilID int) {
+	PublicarEvento(Evento{
+		Tipo:         EventoPerfilActualizado,
+		EstudianteID: int64(terceroID),
+		PerfilID:     int64(perfilID),
+	})
 }
 
 func findPerfil(terceroID int) (*perfilRecord, error) {
@@ -379,8 +374,14 @@ func mapPerfil(record perfilRecord) map[string]interface{} {
 		"habilidades":                normalizeHabilidades(record.Habilidades),
 		"visible":                    record.Visible,
 		"tratamiento_datos_aceptado": record.TratamientoDatosAceptado,
+		"anonimo":                    record.Anonimo,
+		"consentimiento_version":     strings.TrimSpace(record.ConsentimientoVersion),
+		"consentimiento_vigente":     record.consentimientoVigente(),
+		"modalidad_preferida":        strings.TrimSpace(record.ModalidadPreferida),
+		"ciudad_preferida":           strings.TrimSpace(record.CiudadPreferida),
 		"fecha_creacion":             strings.TrimSpace(record.FechaCreacion),
 		"fecha_modificacion":         strings.TrimSpace(record.FechaModificacion),
+		"completitud":                completitudPerfil(record),
 	}
 	if doc := decodeCvDocumento(record.CvDocumentoRaw); doc != nil {
 		result["cv_documento_id"] = doc
@@ -468,6 +469,11 @@ type perfilRecord struct {
 	CvDocumentoRaw           json.RawMessage `json:"CvDocumentoId"`
 	Visible                  bool            `json:"Visible"`
 	TratamientoDatosAceptado bool            `json:"TratamientoDatosAceptado"`
+	Anonimo                  bool            `json:"Anonimo"`
+	ConsentimientoVersion    string          `json:"ConsentimientoVersion"`
+	FechaConsentimiento      string          `json:"FechaConsentimiento"`
+	ModalidadPreferida       string          `json:"ModalidadPreferida"`
+	CiudadPreferida          string          `json:"CiudadPreferida"`
 	FechaCreacion            string          `json:"FechaCreacion"`
 	FechaModificacion        string          `json:"FechaModificacion"`
 }
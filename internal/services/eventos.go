@@ -0,0 +1,87 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+)
+
+// Tipos de evento de dominio publicados por los servicios del MID.
+const (
+	EventoPostulacionCreada      = "postulacion.creada"
+	EventoPostulacionActualizada = "postulacion.actualizada"
+	EventoInvitacionCreada       = "invitacion.creada"
+	EventoInvitacionRespondida   = "invitacion.respondida"
+	EventoOfertaEstado           = "oferta.estado"
+	EventoPerfilVisitado         = "perfil.visitado"
+	EventoPerfilActualizado      = "perfil.actualizado"
+)
+
+// Evento describe un cambio hecho a través del MID. Los ids que no apliquen quedan en cero; los
+// suscriptores deben tolerar eventos con información parcial.
+type Evento struct {
+	Tipo          string                 `json:"tipo"`
+	EstudianteID  int64                  `json:"estudiante_id,omitempty"`
+	PerfilID      int64                  `json:"perfil_id,omitempty"`
+	TutorID       int64                  `json:"tutor_id,omitempty"`
+	OfertaID      int64                  `json:"oferta_id,omitempty"`
+	PostulacionID int64                  `json:"postulacion_id,omitempty"`
+	InvitacionID  int64                  `json:"invitacion_id,omitempty"`
+	Estado        string                 `json:"estado,omitempty"`
+	Fecha         time.Time              `json:"fecha"`
+	Datos         map[string]interface{} `json:"datos,omitempty"`
+}
+
+// busEventos es un bus en memoria: solo ve los cambios hechos por esta instancia del MID.
+var busEventos = struct {
+	mu         sync.RWMutex
+	siguiente  int
+	suscriptor map[int]func(Evento)
+}{suscriptor: map[int]func(Evento){}}
+
+// SuscribirEventos registra fn para todos los eventos publicados y retorna la función que cancela
+// la suscripción. fn se ejecuta en la goroutine que publica, por lo que debe ser rápida.
+func SuscribirEventos(fn func(Evento)) func() {
+	busEventos.mu.Lock()
+	busEventos.siguiente++
+	id := busEventos.siguiente
+	busEventos.suscriptor[id] = fn
+	busEventos.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			busEventos.mu.Lock()
+			delete(busEventos.suscriptor, id)
+			busEventos.mu.Unlock()
+		})
+	}
+}
+
+// PublicarEvento entrega el evento a los suscriptores. Un suscriptor que falla no afecta a los
+// demás ni a la operación que publicó.
+func PublicarEvento(e Evento) {
+	if e.Fecha.IsZero() {
+		e.Fecha = time.Now().UTC()
+	}
+	busEventos.mu.RLock()
+	handlers := make([]func(Evento), 0, len(busEventos.suscriptor))
+	for _, fn := range busEventos.suscriptor {
+		handlers = append(handlers, fn)
+	}
+	busEventos.mu.RUnlock()
+
+	for _, fn := range handlers {
+		entregarEvento(fn, e)
+	}
+}
+
+func entregarEvento(fn func(Evento), e Evento) {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("suscriptor de eventos", e.Tipo, ":", r)
+		}
+	}()
+	fn(e)
+}
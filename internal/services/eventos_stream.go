@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+
+	"github.com/udistrital/pasantia_mid/internal/clients"
+)
+
+const (
+	defaultEventosBuffer       = 500
+	defaultEventosHeartbeatSeg = 25
+
+	// colaConexionEventos es cuántos eventos puede acumular una conexión lenta antes de cerrarse;
+	// el cliente se reconecta con Last-Event-ID y recupera lo pendiente del buffer.
+	colaConexionEventos = 64
+)
+
+// EventoStream es un evento de dominio con el id secuencial que se envía como id SSE.
+type EventoStream struct {
+	ID int64
+	Evento
+}
+
+// StreamEventos es una conexión abierta al stream. Pendientes son los eventos posteriores a
+// Last-Event-ID; Resync indica que ese id ya no está en el buffer y el cliente debe recargar.
+type StreamEventos struct {
+	Pendientes []EventoStream
+	Resync     bool
+	Eventos    <-chan EventoStream
+	// Cerrado se cierra cuando el servidor descarta la conexión por no consumir a tiempo.
+	Cerrado <-chan struct{}
+
+	conexion *conexionEventos
+}
+
+// Cerrar da de baja la conexión del hub; es seguro llamarla varias veces.
+func (s *StreamEventos) Cerrar() {
+	hubEventos.mu.Lock()
+	delete(hubEventos.conexiones, s.conexion)
+	hubEventos.mu.Unlock()
+	s.conexion.cerrar()
+}
+
+// conexionEventos filtra los eventos para un usuario: como estudiante (tercero y perfil) y/o
+// como tutor (sus ofertas).
+type conexionEventos struct {
+	estudianteID int64
+	perfilID     int64
+	tutorID      int64
+
+	mu      sync.Mutex
+	ofertas map[int64]struct{}
+
+	ch      chan EventoStream
+	cerrado chan struct{}
+	once    sync.Once
+}
+
+func (c *conexionEventos) cerrar() {
+	c.once.Do(func() { close(c.cerrado) })
+}
+
+// hubEventos numera los eventos del bus, guarda los últimos para reanudar y los reparte a las
+// conexiones abiertas. La secuencia parte del reloj al arrancar para que los ids de una instancia
+// reiniciada sean mayores que los anteriores.
+var (
+	hubEventos = struct {
+		mu         sync.Mutex
+		secuencia  int64
+		buffer     []EventoStream
+		capacidad  int
+		conexiones map[*conexionEventos]struct{}
+	}{conexiones: map[*conexionEventos]struct{}{}}
+	hubEventosOnce sync.Once
+)
+
+// IniciarStreamEventos suscribe el hub al bus para que los eventos queden en el buffer de
+// reanudación desde el arranque.
+func IniciarStreamEventos() {
+	hubEventosOnce.Do(func() {
+		hubEventos.mu.Lock()
+		hubEventos.secuencia = time.Now().UnixMicro()
+		hubEventos.capacidad = configEntero("EVENTOS_BUFFER", "eventos_buffer", defaultEventosBuffer)
+		if hubEventos.capacidad <= 0 {
+			hubEventos.capacidad = defaultEventosBuffer
+		}
+		hubEventos.mu.Unlock()
+		SuscribirEventos(distribuirEvento)
+	})
+}
+
+// HeartbeatEventos es cada cuánto se envía un comentario para mantener viva la conexión.
+func HeartbeatEventos() time.Duration {
+	seg := configEntero("EVENTOS_HEARTBEAT_SEG", "eventos_heartbeat_seg", defaultEventosHeartbeatSeg)
+	if seg <= 0 {
+		seg = defaultEventosHeartbeatSeg
+	}
+	return time.Duration(seg) * time.Second
+}
+
+// AbrirStreamEventos registra una conexión para el estudiante (terceroID) y/o tutor (tutorID) del
+// token. ultimoID es el Last-Event-ID enviado por el cliente al reconectar, vacío si es nueva.
+func AbrirStreamEventos(ctx context.Context, terceroID, tutorID int, ultimoID string) *StreamEventos {
+	IniciarStreamEventos()
+
+	conexion := &conexionEventos{
+		estudianteID: int64(terceroID),
+		tutorID:      int64(tutorID),
+		ofertas:      map[int64]struct{}{},
+		ch:           make(chan EventoStream, colaConexionEventos),
+		cerrado:      make(chan struct{}),
+	}
+	crud := clients.CastorCRUD()
+	if terceroID > 0 {
+		if perfil, err := crud.GetPerfilByTerceroID(ctx, terceroID); err == nil && perfil != nil {
+			conexion.perfilID = int64(perfil.Id)
+		}
+	}
+	if tutorID > 0 {
+		ofertas, err := crud.ListOfertasTutor(ctx, tutorID)
+		if err != nil {
+			logs.Warn("stream de eventos: ofertas del tutor", tutorID, ":", err)
+		}
+		for _, o := range ofertas {
+			conexion.ofertas[o.Id] = struct{}{}
+		}
+	}
+
+	stream := &StreamEventos{Eventos: conexion.ch, Cerrado: conexion.cerrado, conexion: conexion}
+	ultimo, _ := strconv.ParseInt(strings.TrimSpace(ultimoID), 10, 64)
+
+	// El registro y la lectura del buffer van bajo el mismo candado: ningún evento se pierde ni se
+	// repite entre lo pendiente y lo que llega en vivo.
+	hubEventos.mu.Lock()
+	defer hubEventos.mu.Unlock()
+	hubEventos.conexiones[conexion] = struct{}{}
+	if ultimo <= 0 {
+		return stream
+	}
+	if len(hubEventos.buffer) == 0 || ultimo < hubEventos.buffer[0].ID-1 || ultimo > hubEventos.secuencia {
+		stream.Resync = ultimo != hubEventos.secuencia
+		return stream
+	}
+	for _, e := range hubEventos.buffer {
+		if e.ID > ultimo && conexion.relevante(e.Evento) {
+			stream.Pendientes = append(stream.Pendientes, e)
+		}
+	}
+	return stream
+}
+
+func distribuirEvento(e Evento) {
+	hubEventos.mu.Lock()
+	hubEventos.secuencia++
+	numerado := EventoStream{ID: hubEventos.secuencia, Evento: e}
+	hubEventos.buffer = append(hubEventos.buffer, numerado)
+	if exceso := len(hubEventos.buffer) - hubEventos.capacidad; exceso > 0 {
+		hubEventos.buffer = append(hubEventos.buffer[:0:0], hubEventos.buffer[exceso:]...)
+	}
+	conexiones := make([]*conexionEventos, 0, len(hubEventos.conexiones))
+	for c := range hubEventos.conexiones {
+		conexiones = append(conexiones, c)
+	}
+	hubEventos.mu.Unlock()
+
+	for _, c := range conexiones {
+		if !c.relevante(e) {
+			continue
+		}
+		select {
+		case c.ch <- numerado:
+		default:
+			c.cerrar()
+		}
+	}
+}
+
+// relevante decide si el evento interesa a la conexión: al estudiante le llegan invitaciones,
+// cambios de sus postulaciones y visitas a su perfil; al tutor, postulantes nuevos, cambios de
+// postulaciones de sus ofertas y respuestas a sus invitaciones.
+func (c *conexionEventos) relevante(e Evento) bool {
+	esEstudiante := (c.estudianteID > 0 && e.EstudianteID == c.estudianteID) ||
+		(c.perfilID > 0 && e.PerfilID == c.perfilID)
+	esTutor := c.tutorID > 0 && e.TutorID == c.tutorID
+
+	switch e.Tipo {
+	case EventoInvitacionCreada, EventoPerfilVisitado:
+		return esEstudiante
+	case EventoPostulacionActualizada:
+		return esEstudiante || esTutor || c.tieneOferta(e.OfertaID)
+	case EventoPostulacionCreada:
+		return esTutor || c.tieneOferta(e.OfertaID)
+	case EventoInvitacionRespondida:
+		return esTutor
+	case EventoOfertaEstado:
+		// Las ofertas nuevas del tutor entran a su filtro; el cambio de estado no se envía.
+		if esTutor && e.OfertaID > 0 {
+			c.mu.Lock()
+			c.ofertas[e.OfertaID] = struct{}{}
+			c.mu.Unlock()
+		}
+		return false
+	}
+	return false
+}
+
+func (c *conexionEventos) tieneOferta(ofertaID int64) bool {
+	if ofertaID <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.ofertas[ofertaID]
+	return ok
+}
@@ -34,6 +34,14 @@ type CatalogoFilters struct {
 
 // Catalogo retorna la página solicitada del catálogo de estudiantes.
 func Catalogo(ctx *context.Context, filters CatalogoFilters, tutorID int) (internaldto.PageDTO[internaldto.EstudiantePerfilCard], error) {
+	// Con texto libre se prefiere el índice local, que ordena por relevancia.
+	if strings.TrimSpace(filters.Query) != "" && indicePerfiles.estaListo() {
+		page := catalogoDesdeIndice(filters)
+		marcarGuardados(tutorID, page.Items)
+		anonimizarCards(requestContext(ctx), tutorID, page.Items)
+		return page, nil
+	}
+
 	cfg := rootservices.GetConfig()
 	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, "explorar", "estudiantes")
 	values := url.Values{}
@@ -41,12 +49,16 @@ func Catalogo(ctx *context.Context, filters CatalogoFilters, tutorID int) (inter
 	if filters.ProyectoCurricularID != nil && *filters.ProyectoCurricularID > 0 {
 		values.Set("pc_id", strconv.Itoa(*filters.ProyectoCurricularID))
 	}
-	if trimmed := strings.TrimSpace(filters.Skills); trimmed != "" {
+	if trimmed := canonizarTerminosHabilidad(filters.Skills); trimmed != "" {
 		values.Set("skills", trimmed)
 	}
 	if trimmed := strings.TrimSpace(filters.Query); trimmed != "" {
 		values.Set("q", trimmed)
 	}
+	// El CRUD pagina solo sobre perfiles con el consentimiento de la versión vigente de los términos.
+	if version := versionConsentimientoVigente(); version != "" {
+		values.Set("consentimiento_version", version)
+	}
 	if filters.Page > 0 {
 		values.Set("page", strconv.Itoa(filters.Page))
 	}
@@ -76,15 +88,21 @@ func Catalogo(ctx *context.Context, filters CatalogoFilters, tutorID int) (inter
 		}
 	}
 
+	items := mapToPerfilCards(raw.Items, pcNames)
+	marcarGuardados(tutorID, items)
+	anonimizarCards(requestContext(ctx), tutorID, items)
+
 	return internaldto.PageDTO[internaldto.EstudiantePerfilCard]{
-		Items: mapToPerfilCards(raw.Items, pcNames),
+		Items: items,
 		Page:  raw.Page,
 		Size:  raw.Size,
 		Total: raw.Total,
 	}, nil
 }
 
-// DetallePerfil obtiene el detalle del perfil, marcando si está guardado por el tutor.
+// DetallePerfil obtiene el detalle del perfil, marcando si está guardado por el tutor. Los perfiles
+// en modo anónimo se entregan sin identidad ni CV hasta que el estudiante se relacione con el tutor,
+// y los que no tienen consentimiento vigente se reportan como no encontrados.
 func DetallePerfil(ctx *context.Context, perfilID int, tutorID int) (map[string]interface{}, error) {
 	cfg := rootservices.GetConfig()
 	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, perfilResource, strconv.Itoa(perfilID))
@@ -97,18 +115,20 @@ func DetallePerfil(ctx *context.Context, perfilID int, tutorID int) (map[string]
 		return nil, helpers.AsAppError(err, "error consultando perfil")
 	}
 
+	// Sin consentimiento vigente el perfil no se expone en el explorador.
+	if !consentimientoVigente(record) {
+		return nil, helpers.NewAppError(http.StatusNotFound, "perfil no encontrado", nil)
+	}
+
 	perfil := map[string]interface{}{}
 	for k, v := range record {
 		perfil[strings.ToLower(k)] = v
 	}
+	perfil["tratamiento_datos_aceptado"] = true
 
-	if val, ok := record["TratamientoDatosAceptado"]; ok {
-		perfil["tratamiento_datos_aceptado"] = normalizeToBool(val, false)
-	} else if val, ok := perfil["tratamientodatosaceptado"]; ok {
-		perfil["tratamiento_datos_aceptado"] = normalizeToBool(val, false)
-	}
-
-	_ = tutorID
+	perfil["guardado"] = tutorID > 0 && bookmarkExists(tutorID, perfilID)
+	perfil["anonimo"] = normalizeToBool(record["Anonimo"], false)
+	perfil["anonimizado"] = anonimizarDetalle(requestContext(ctx), tutorID, perfil)
 
 	if pcRaw, ok := perfil["proyectocurricularid"]; ok {
 		if pcID, ok := normalizeToInt(pcRaw); ok && pcID > 0 {
@@ -165,8 +185,13 @@ func EliminarBookmark(ctx *context.Context, tutorID, perfilID int) error {
 	return nil
 }
 
-// RegistrarVisita crea un registro de visita tutor-perfil.
-func RegistrarVisita(ctx *context.Context, tutorID, perfilID int) error {
+// RegistrarVisita crea un registro de visita tutor-perfil. Si el tutor ya visitó el perfil dentro de
+// la ventana de deduplicación no registra nada y retorna false.
+func RegistrarVisita(ctx *context.Context, tutorID, perfilID int) (bool, error) {
+	if visitaReciente(requestContext(ctx), tutorID, perfilID) {
+		return false, nil
+	}
+
 	cfg := rootservices.GetConfig()
 
 	//Endpoint REAL del CRUD según tu router.go
@@ -188,10 +213,15 @@ func RegistrarVisita(ctx *context.Context, tutorID, perfilID int) error {
 		cfg.RequestTimeout,
 		true, // porque BaseController.Created / Ok suele venir envuelto
 	); err != nil {
-		return helpers.AsAppError(err, "error registrando visita")
+		return false, helpers.AsAppError(err, "error registrando visita")
 	}
 
-	return nil
+	PublicarEvento(Evento{
+		Tipo:     EventoPerfilVisitado,
+		TutorID:  int64(tutorID),
+		PerfilID: int64(perfilID),
+	})
+	return true, nil
 }
 
 func buildCatalogQuery(filters CatalogoFilters) url.Values {
@@ -209,7 +239,7 @@ func buildCatalogQuery(filters CatalogoFilters) url.Values {
 		var clauses []string
 		seen := make(map[string]struct{})
 		for _, part := range parts {
-			s := strings.TrimSpace(part)
+			s := nombreCanonicoHabilidad(part)
 			if s == "" {
 				continue
 			}
@@ -238,25 +268,12 @@ func buildCatalogQuery(filters CatalogoFilters) url.Values {
 
 func bookmarkSetForTutor(tutorID int) map[int]struct{} {
 	set := make(map[int]struct{})
-	cfg := rootservices.GetConfig()
-	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, bookmarkResource)
-	values := url.Values{}
-	values.Set("limit", "0")
-	values.Set("query", fmt.Sprintf("TutorId:%d", tutorID))
-
-	urlWithQuery := endpoint
-	if encoded := values.Encode(); encoded != "" {
-		urlWithQuery = endpoint + "?" + encoded
-	}
-
-	var bookmarks []map[string]interface{}
-	if err := helpers.DoJSON("GET", urlWithQuery, nil, &bookmarks, cfg.RequestTimeout); err != nil {
+	bookmarks, err := listBookmarksTutor(tutorID)
+	if err != nil {
 		return set
 	}
-	for _, entry := range bookmarks {
-		if perfilID, ok := normalizeToInt(entry["PerfilEstudianteId"]); ok {
-			set[perfilID] = struct{}{}
-		}
+	for _, b := range bookmarks {
+		set[b.PerfilID] = struct{}{}
 	}
 	return set
 }
@@ -267,32 +284,11 @@ func bookmarkExists(tutorID, perfilID int) bool {
 }
 
 func findBookmarkID(tutorID, perfilID int) (int, error) {
-	cfg := rootservices.GetConfig()
-	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, bookmarkResource)
-
-	values := url.Values{}
-	values.Set("limit", "1")
-	values.Set("query", fmt.Sprintf("TutorId:%d,PerfilEstudianteId:%d", tutorID, perfilID))
-
-	urlWithQuery := endpoint
-	if encoded := values.Encode(); encoded != "" {
-		urlWithQuery = endpoint + "?" + encoded
-	}
-
-	var records []map[string]interface{}
-	if err := helpers.DoJSON("GET", urlWithQuery, nil, &records, cfg.RequestTimeout); err != nil {
-		if helpers.IsHTTPError(err, http.StatusNotFound) {
-			return 0, nil
-		}
-		return 0, helpers.AsAppError(err, "error consultando bookmark")
-	}
-	if len(records) == 0 {
-		return 0, nil
-	}
-	if id, ok := normalizeToInt(records[0]["Id"]); ok {
-		return id, nil
+	b, err := findBookmark(tutorID, perfilID)
+	if err != nil {
+		return 0, err
 	}
-	return 0, nil
+	return b.ID, nil
 }
 
 func mapToPerfilCard(entry map[string]interface{}) internaldto.EstudiantePerfilCard {
@@ -308,7 +304,8 @@ func mapToPerfilCard(entry map[string]interface{}) internaldto.EstudiantePerfilC
 		Habilidades:              parseHabilidades(entry["Habilidades"]),
 		Visible:                  normalizeToBool(entry["Visible"], true),
 		Guardado:                 normalizeToBool(entry["Guardado"], false),
-		TratamientoDatosAceptado: normalizeToBool(entry["TratamientoDatosAceptado"], false),
+		TratamientoDatosAceptado: consentimientoVigente(entry),
+		Anonimo:                  normalizeToBool(entry["Anonimo"], false),
 	}
 
 	if docID, ok := normalizeToInt(entry["CvDocumentoId"]); ok {
@@ -348,7 +345,8 @@ func mapToPerfilCardExplorar(entry map[string]interface{}, pcNames map[int64]str
 		Habilidades:              parseHabilidades(entry["Habilidades"]),
 		Visible:                  normalizeToBool(entry["Visible"], true),
 		Guardado:                 false,
-		TratamientoDatosAceptado: normalizeToBool(entry["TratamientoDatosAceptado"], false),
+		TratamientoDatosAceptado: consentimientoVigente(entry),
+		Anonimo:                  normalizeToBool(entry["Anonimo"], false),
 	}
 	if card.Resumen == "" {
 		card.Resumen = strings.TrimSpace(normalizeToString(entry["resumen"]))
@@ -359,8 +357,8 @@ func mapToPerfilCardExplorar(entry map[string]interface{}, pcNames map[int64]str
 	if !card.Visible {
 		card.Visible = normalizeToBool(entry["visible"], card.Visible)
 	}
-	if !card.TratamientoDatosAceptado {
-		card.TratamientoDatosAceptado = normalizeToBool(entry["tratamiento_datos_aceptado"], card.TratamientoDatosAceptado)
+	if !card.Anonimo {
+		card.Anonimo = normalizeToBool(entry["anonimo"], false)
 	}
 
 	if docID, ok := normalizeToInt(entry["CvDocumentoId"]); ok {
@@ -412,10 +410,17 @@ func parseHabilidades(value interface{}) []string {
 		if json.Unmarshal([]byte(v), &arr) == nil {
 			return arr
 		}
+		var items []interface{}
+		if json.Unmarshal([]byte(v), &items) == nil {
+			return parseHabilidades(items)
+		}
 		return []string{strings.TrimSpace(v)}
 	case []interface{}:
 		result := make([]string, 0, len(v))
 		for _, item := range v {
+			if obj, ok := item.(map[string]interface{}); ok {
+				item, _ = habilidadNombreEnObjeto(obj)
+			}
 			if s := strings.TrimSpace(normalizeToString(item)); s != "" {
 				result = append(result, s)
 			}
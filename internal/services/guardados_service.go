@@ -0,0 +1,472 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	carpetaBookmarkResource = "tutor_bookmark_carpeta"
+
+	maxCarpetasPorTutor   = 50
+	maxNombreCarpeta      = 80
+	maxNotaPerfilGuardado = 2000
+)
+
+// bookmark es la representación interna del registro tutor_bookmark del CRUD.
+type bookmark struct {
+	ID            int
+	TutorID       int
+	PerfilID      int
+	CarpetaID     int
+	Nota          string
+	FechaCreacion string
+}
+
+// ListarGuardados retorna los perfiles guardados por el tutor, del más reciente al más antiguo.
+// carpetaID nil lista todos, 0 solo los que no tienen carpeta y >0 los de esa carpeta.
+func ListarGuardados(ctx context.Context, tutorID int, carpetaID *int, page, size int) (internaldto.PageDTO[internaldto.PerfilGuardado], error) {
+	bookmarks, err := listBookmarksTutor(tutorID)
+	if err != nil {
+		return internaldto.PageDTO[internaldto.PerfilGuardado]{}, err
+	}
+	carpetas, err := listCarpetasCRUD(tutorID)
+	if err != nil {
+		return internaldto.PageDTO[internaldto.PerfilGuardado]{}, err
+	}
+	nombres := make(map[int]string, len(carpetas))
+	for _, c := range carpetas {
+		nombres[c.ID] = c.Nombre
+	}
+	if carpetaID != nil && *carpetaID > 0 {
+		if _, ok := nombres[*carpetaID]; !ok {
+			return internaldto.PageDTO[internaldto.PerfilGuardado]{}, helpers.NewAppError(http.StatusNotFound, "carpeta no encontrada", nil)
+		}
+	}
+
+	filtrados := make([]bookmark, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		if carpetaID != nil && b.CarpetaID != *carpetaID {
+			continue
+		}
+		filtrados = append(filtrados, b)
+	}
+	sort.SliceStable(filtrados, func(i, j int) bool {
+		ti, tj := parseTime(filtrados[i].FechaCreacion), parseTime(filtrados[j].FechaCreacion)
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return filtrados[i].ID > filtrados[j].ID
+	})
+
+	start := (page - 1) * size
+	if start > len(filtrados) {
+		start = len(filtrados)
+	}
+	end := start + size
+	if end > len(filtrados) {
+		end = len(filtrados)
+	}
+
+	items := make([]internaldto.PerfilGuardado, 0, end-start)
+	pcNames := map[int64]string{}
+	for _, b := range filtrados[start:end] {
+		item := internaldto.PerfilGuardado{
+			EstudiantePerfilCard: perfilGuardadoCard(b.PerfilID, pcNames),
+			CarpetaNombre:        nombres[b.CarpetaID],
+			Nota:                 b.Nota,
+		}
+		item.Guardado = true
+		if b.CarpetaID > 0 {
+			id := b.CarpetaID
+			item.CarpetaID = &id
+		}
+		if t := parseTime(b.FechaCreacion); !t.IsZero() {
+			item.FechaGuardado = &t
+		}
+		items = append(items, item)
+	}
+	cards := make([]*internaldto.EstudiantePerfilCard, len(items))
+	for i := range items {
+		cards[i] = &items[i].EstudiantePerfilCard
+	}
+	anonimizarPerfiles(ctx, tutorID, cards)
+	return internaldto.PageDTO[internaldto.PerfilGuardado]{Items: items, Page: page, Size: size, Total: int64(len(filtrados))}, nil
+}
+
+// ActualizarGuardado asigna carpeta o nota privada a un perfil; si aún no estaba guardado lo guarda.
+func ActualizarGuardado(tutorID, perfilID int, payload internaldto.GuardadoUpdate) (map[string]interface{}, error) {
+	if payload.CarpetaID == nil && payload.Nota == nil {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "carpeta_id o nota requerido", nil)
+	}
+	// Se valida todo el payload antes de crear el marcador para que un 400/403 no deje el perfil guardado.
+	carpetaNombre := ""
+	if payload.CarpetaID != nil {
+		if *payload.CarpetaID < 0 {
+			return nil, helpers.NewAppError(http.StatusBadRequest, "carpeta_id inválido", nil)
+		}
+		if *payload.CarpetaID > 0 {
+			carpeta, err := autorizarCarpeta(tutorID, *payload.CarpetaID)
+			if err != nil {
+				return nil, err
+			}
+			carpetaNombre = carpeta.Nombre
+		}
+	}
+	nota := ""
+	if payload.Nota != nil {
+		nota = strings.TrimSpace(*payload.Nota)
+		if len([]rune(nota)) > maxNotaPerfilGuardado {
+			return nil, helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("nota supera %d caracteres", maxNotaPerfilGuardado), nil)
+		}
+	}
+
+	b, err := findBookmark(tutorID, perfilID)
+	if err != nil {
+		return nil, err
+	}
+	if b.ID == 0 {
+		if err := GuardarPerfil(nil, tutorID, perfilID); err != nil {
+			return nil, err
+		}
+		if b, err = findBookmark(tutorID, perfilID); err != nil {
+			return nil, err
+		}
+		if b.ID == 0 {
+			return nil, helpers.NewAppError(http.StatusBadGateway, "no fue posible guardar el perfil", nil)
+		}
+	}
+
+	if payload.CarpetaID != nil {
+		b.CarpetaID = *payload.CarpetaID
+	}
+	if payload.Nota != nil {
+		b.Nota = nota
+	}
+	if err := updateBookmarkCRUD(b); err != nil {
+		return nil, err
+	}
+
+	out := map[string]interface{}{
+		"perfil_id":  perfilID,
+		"carpeta_id": nil,
+		"nota":       b.Nota,
+	}
+	if b.CarpetaID > 0 {
+		out["carpeta_id"] = b.CarpetaID
+		out["carpeta_nombre"] = carpetaNombre
+	}
+	return out, nil
+}
+
+// ListarCarpetas retorna las carpetas del tutor con la cantidad de perfiles de cada una.
+func ListarCarpetas(tutorID int) ([]internaldto.CarpetaGuardados, error) {
+	carpetas, err := listCarpetasCRUD(tutorID)
+	if err != nil {
+		return nil, err
+	}
+	bookmarks, err := listBookmarksTutor(tutorID)
+	if err != nil {
+		return nil, err
+	}
+	conteo := make(map[int]int)
+	for _, b := range bookmarks {
+		conteo[b.CarpetaID]++
+	}
+	for i := range carpetas {
+		carpetas[i].Total = conteo[carpetas[i].ID]
+	}
+	sort.SliceStable(carpetas, func(i, j int) bool {
+		return strings.ToLower(carpetas[i].Nombre) < strings.ToLower(carpetas[j].Nombre)
+	})
+	return carpetas, nil
+}
+
+// CrearCarpeta crea una carpeta de guardados; el nombre es único por tutor.
+func CrearCarpeta(tutorID int, payload internaldto.CarpetaUpsert) (internaldto.CarpetaGuardados, error) {
+	nombre, err := validarNombreCarpeta(tutorID, 0, payload.Nombre)
+	if err != nil {
+		return internaldto.CarpetaGuardados{}, err
+	}
+	existentes, err := listCarpetasCRUD(tutorID)
+	if err != nil {
+		return internaldto.CarpetaGuardados{}, err
+	}
+	if len(existentes) >= maxCarpetasPorTutor {
+		return internaldto.CarpetaGuardados{}, helpers.NewAppError(http.StatusConflict, fmt.Sprintf("se alcanzó el máximo de %d carpetas", maxCarpetasPorTutor), nil)
+	}
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, carpetaBookmarkResource)
+	body := map[string]interface{}{
+		"TutorId":           tutorID,
+		"Nombre":            nombre,
+		"FechaCreacion":     nowISO(),
+		"FechaModificacion": nowISO(),
+	}
+	var created map[string]interface{}
+	if err := helpers.DoJSON("POST", endpoint, body, &created, cfg.RequestTimeout); err != nil {
+		return internaldto.CarpetaGuardados{}, helpers.AsAppError(err, "error creando carpeta")
+	}
+	id, _ := normalizeToInt(created["Id"])
+	return internaldto.CarpetaGuardados{ID: id, Nombre: nombre}, nil
+}
+
+// RenombrarCarpeta cambia el nombre de una carpeta del tutor.
+func RenombrarCarpeta(tutorID, carpetaID int, payload internaldto.CarpetaUpsert) (internaldto.CarpetaGuardados, error) {
+	carpeta, err := autorizarCarpeta(tutorID, carpetaID)
+	if err != nil {
+		return internaldto.CarpetaGuardados{}, err
+	}
+	nombre, err := validarNombreCarpeta(tutorID, carpetaID, payload.Nombre)
+	if err != nil {
+		return internaldto.CarpetaGuardados{}, err
+	}
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, carpetaBookmarkResource, strconv.Itoa(carpetaID))
+	body := map[string]interface{}{
+		"Id":                carpetaID,
+		"TutorId":           tutorID,
+		"Nombre":            nombre,
+		"FechaModificacion": nowISO(),
+	}
+	var out map[string]interface{}
+	if err := helpers.DoJSON("PUT", endpoint, body, &out, cfg.RequestTimeout); err != nil {
+		return internaldto.CarpetaGuardados{}, helpers.AsAppError(err, "error renombrando carpeta")
+	}
+	carpeta.Nombre = nombre
+	return carpeta, nil
+}
+
+// EliminarCarpeta borra la carpeta; los perfiles que contenía siguen guardados, sin carpeta.
+func EliminarCarpeta(tutorID, carpetaID int) error {
+	if _, err := autorizarCarpeta(tutorID, carpetaID); err != nil {
+		return err
+	}
+	bookmarks, err := listBookmarksTutor(tutorID)
+	if err != nil {
+		return err
+	}
+	for _, b := range bookmarks {
+		if b.CarpetaID != carpetaID {
+			continue
+		}
+		b.CarpetaID = 0
+		if err := updateBookmarkCRUD(b); err != nil {
+			return err
+		}
+	}
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, carpetaBookmarkResource, strconv.Itoa(carpetaID))
+	if err := helpers.DoJSON("DELETE", endpoint, nil, nil, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil
+		}
+		return helpers.AsAppError(err, "error eliminando carpeta")
+	}
+	return nil
+}
+
+// marcarGuardados activa el indicador Guardado en las tarjetas que el tutor tiene guardadas.
+func marcarGuardados(tutorID int, items []internaldto.EstudiantePerfilCard) {
+	if tutorID <= 0 || len(items) == 0 {
+		return
+	}
+	guardados := bookmarkSetForTutor(tutorID)
+	for i := range items {
+		if _, ok := guardados[int(items[i].PerfilID)]; ok {
+			items[i].Guardado = true
+		}
+	}
+}
+
+// perfilGuardadoCard arma la tarjeta del perfil guardado. Si el estudiante ocultó el perfil o retiró
+// el tratamiento de datos solo se conserva el identificador.
+func perfilGuardadoCard(perfilID int, pcNames map[int64]string) internaldto.EstudiantePerfilCard {
+	oculto := internaldto.EstudiantePerfilCard{PerfilID: int64(perfilID)}
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, perfilResource, strconv.Itoa(perfilID))
+	var record map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint, nil, &record, cfg.RequestTimeout); err != nil || len(record) == 0 {
+		return oculto
+	}
+	if !normalizeToBool(record["Visible"], true) || !consentimientoVigente(record) {
+		return oculto
+	}
+
+	if pcID, _ := normalizeToInt64(record["ProyectoCurricularId"]); pcID > 0 {
+		if _, ok := pcNames[pcID]; !ok {
+			pcNames[pcID] = ""
+			if detalle, err := rootservices.GetProyectoCurricular(int(pcID)); err == nil && detalle != nil {
+				pcNames[pcID] = strings.TrimSpace(detalle.Nombre)
+			}
+		}
+	}
+	return mapToPerfilCardExplorar(record, pcNames)
+}
+
+func validarNombreCarpeta(tutorID, carpetaID int, raw string) (string, error) {
+	nombre := strings.TrimSpace(raw)
+	if nombre == "" {
+		return "", helpers.NewAppError(http.StatusBadRequest, "nombre requerido", nil)
+	}
+	if len([]rune(nombre)) > maxNombreCarpeta {
+		return "", helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("nombre supera %d caracteres", maxNombreCarpeta), nil)
+	}
+	carpetas, err := listCarpetasCRUD(tutorID)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range carpetas {
+		if c.ID != carpetaID && strings.EqualFold(c.Nombre, nombre) {
+			return "", helpers.NewAppError(http.StatusConflict, "ya existe una carpeta con ese nombre", nil)
+		}
+	}
+	return nombre, nil
+}
+
+func autorizarCarpeta(tutorID, carpetaID int) (internaldto.CarpetaGuardados, error) {
+	if carpetaID <= 0 {
+		return internaldto.CarpetaGuardados{}, helpers.NewAppError(http.StatusBadRequest, "carpeta_id inválido", nil)
+	}
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, carpetaBookmarkResource, strconv.Itoa(carpetaID))
+	var raw map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint, nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return internaldto.CarpetaGuardados{}, helpers.NewAppError(http.StatusNotFound, "carpeta no encontrada", err)
+		}
+		return internaldto.CarpetaGuardados{}, helpers.AsAppError(err, "error consultando carpeta")
+	}
+	id, _ := normalizeToInt(raw["Id"])
+	if id == 0 {
+		return internaldto.CarpetaGuardados{}, helpers.NewAppError(http.StatusNotFound, "carpeta no encontrada", nil)
+	}
+	if owner, _ := normalizeToInt(raw["TutorId"]); owner != tutorID {
+		return internaldto.CarpetaGuardados{}, helpers.NewAppError(http.StatusForbidden, "no autorizado para esta carpeta", nil)
+	}
+	return internaldto.CarpetaGuardados{ID: id, Nombre: strings.TrimSpace(normalizeToString(raw["Nombre"]))}, nil
+}
+
+func listCarpetasCRUD(tutorID int) ([]internaldto.CarpetaGuardados, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, carpetaBookmarkResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+	values.Set("query", fmt.Sprintf("TutorId:%d", tutorID))
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return []internaldto.CarpetaGuardados{}, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando carpetas")
+	}
+	out := make([]internaldto.CarpetaGuardados, 0, len(raw))
+	for _, r := range raw {
+		id, _ := normalizeToInt(r["Id"])
+		if id == 0 {
+			continue
+		}
+		out = append(out, internaldto.CarpetaGuardados{ID: id, Nombre: strings.TrimSpace(normalizeToString(r["Nombre"]))})
+	}
+	return out, nil
+}
+
+func listBookmarksTutor(tutorID int) ([]bookmark, error) {
+	return listBookmarksCRUD(fmt.Sprintf("TutorId:%d", tutorID))
+}
+
+func listBookmarksCRUD(query string) ([]bookmark, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, bookmarkResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+	values.Set("query", query)
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando perfiles guardados")
+	}
+	out := make([]bookmark, 0, len(raw))
+	for _, r := range raw {
+		if b := bookmarkFromCRUD(r); b.ID > 0 && b.PerfilID > 0 {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func findBookmark(tutorID, perfilID int) (bookmark, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, bookmarkResource)
+	values := url.Values{}
+	values.Set("limit", "1")
+	values.Set("query", fmt.Sprintf("TutorId:%d,PerfilEstudianteId:%d", tutorID, perfilID))
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return bookmark{}, nil
+		}
+		return bookmark{}, helpers.AsAppError(err, "error consultando bookmark")
+	}
+	if len(raw) == 0 {
+		return bookmark{}, nil
+	}
+	return bookmarkFromCRUD(raw[0]), nil
+}
+
+func updateBookmarkCRUD(b bookmark) error {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, bookmarkResource, strconv.Itoa(b.ID))
+	body := map[string]interface{}{
+		"Id":                 b.ID,
+		"TutorId":            b.TutorID,
+		"PerfilEstudianteId": b.PerfilID,
+		"CarpetaId":          nil,
+		"Nota":               b.Nota,
+		"FechaModificacion":  nowISO(),
+	}
+	if b.CarpetaID > 0 {
+		body["CarpetaId"] = b.CarpetaID
+	}
+	if b.FechaCreacion != "" {
+		body["FechaCreacion"] = b.FechaCreacion
+	}
+	var out map[string]interface{}
+	if err := helpers.DoJSON("PUT", endpoint, body, &out, cfg.RequestTimeout); err != nil {
+		return helpers.AsAppError(err, "error actualizando bookmark")
+	}
+	return nil
+}
+
+func bookmarkFromCRUD(raw map[string]interface{}) bookmark {
+	var b bookmark
+	b.ID, _ = normalizeToInt(raw["Id"])
+	b.TutorID, _ = normalizeToInt(raw["TutorId"])
+	b.PerfilID, _ = normalizeToInt(raw["PerfilEstudianteId"])
+	if carpeta, ok := raw["CarpetaId"].(map[string]interface{}); ok {
+		b.CarpetaID, _ = normalizeToInt(carpeta["Id"])
+	} else {
+		b.CarpetaID, _ = normalizeToInt(raw["CarpetaId"])
+	}
+	b.Nota = strings.TrimSpace(normalizeToString(raw["Nota"]))
+	b.FechaCreacion = strings.TrimSpace(normalizeToString(raw["FechaCreacion"]))
+	return b
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/beego/beego/v2/core/logs"
+	beego "github.com/beego/beego/v2/server/web"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const habilidadesMigracionLote = 100
+
+var migracionHabilidadesOnce sync.Once
+
+// IniciarMigracionHabilidades reescribe en segundo plano las habilidades de texto libre de los
+// perfiles al formato del catálogo. Es idempotente: los perfiles ya normalizados no se tocan.
+// Como modifica datos de producción solo corre con habilidades_migracion_habilitada = true; un
+// valor ausente o inválido la deja desactivada.
+func IniciarMigracionHabilidades() {
+	migracionHabilidadesOnce.Do(func() {
+		if !migracionHabilidadesHabilitada() {
+			return
+		}
+		go migrarHabilidadesPerfiles()
+	})
+}
+
+func migrarHabilidadesPerfiles() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("migración de habilidades:", r)
+		}
+	}()
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, perfilResource)
+	migrados, revisados := 0, 0
+
+	for offset := 0; ; offset += habilidadesMigracionLote {
+		values := url.Values{}
+		values.Set("limit", strconv.Itoa(habilidadesMigracionLote))
+		values.Set("offset", strconv.Itoa(offset))
+		values.Set("sortby", "Id")
+		values.Set("order", "asc")
+		values.Set("fields", "Id,Habilidades")
+
+		var records []map[string]interface{}
+		if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &records, cfg.RequestTimeout); err != nil {
+			logs.Warn("migración de habilidades: lectura de perfiles en offset", offset, ":", err)
+			return
+		}
+
+		for _, rec := range records {
+			revisados++
+			id, ok := normalizeToInt(rec["Id"])
+			raw := strings.TrimSpace(normalizeToString(rec["Habilidades"]))
+			if !ok || raw == "" {
+				continue
+			}
+			habilidades, err := normalizarHabilidadesPerfil(raw, false)
+			if err != nil {
+				logs.Warn("migración de habilidades: perfil", id, ":", err)
+				continue
+			}
+			normalizado := serializarHabilidadesPerfil(habilidades)
+			if normalizado == raw {
+				continue
+			}
+			// No se actualiza FechaModificacion: la recencia del perfil refleja cambios del estudiante.
+			put := rootservices.BuildURL(cfg.CastorCRUDBaseURL, perfilResource, strconv.Itoa(id))
+			if err := helpers.DoJSON("PUT", put, map[string]interface{}{"Habilidades": normalizado}, nil, cfg.RequestTimeout); err != nil {
+				logs.Warn("migración de habilidades: perfil", id, ":", err)
+				continue
+			}
+			migrados++
+		}
+
+		if len(records) < habilidadesMigracionLote {
+			break
+		}
+	}
+	logs.Info("migración de habilidades: perfiles revisados", revisados, "migrados", migrados)
+}
+
+func migracionHabilidadesHabilitada() bool {
+	if v := strings.TrimSpace(os.Getenv("HABILIDADES_MIGRACION_HABILITADA")); v != "" {
+		habilitada, err := strconv.ParseBool(v)
+		return err == nil && habilitada
+	}
+	return beego.AppConfig.DefaultBool("habilidades_migracion_habilitada", false)
+}
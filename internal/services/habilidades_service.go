@@ -0,0 +1,473 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	habilidadResource = "habilidad"
+
+	habilidadMaxLongitud = 80
+	habilidadesRetryTTL  = time.Minute
+)
+
+// Niveles de dominio admitidos para las habilidades del perfil.
+const (
+	NivelHabilidadBasico     = "BASICO"
+	NivelHabilidadIntermedio = "INTERMEDIO"
+	NivelHabilidadAvanzado   = "AVANZADO"
+	NivelHabilidadExperto    = "EXPERTO"
+)
+
+// NivelesHabilidad lista los niveles en orden creciente de dominio.
+var NivelesHabilidad = []string{NivelHabilidadBasico, NivelHabilidadIntermedio, NivelHabilidadAvanzado, NivelHabilidadExperto}
+
+var nivelesHabilidadAlias = map[string]string{
+	"basico":       NivelHabilidadBasico,
+	"principiante": NivelHabilidadBasico,
+	"intermedio":   NivelHabilidadIntermedio,
+	"medio":        NivelHabilidadIntermedio,
+	"avanzado":     NivelHabilidadAvanzado,
+	"experto":      NivelHabilidadExperto,
+}
+
+// habilidadesBase se usa cuando el CRUD no tiene catálogo y complementa el catálogo del CRUD.
+// Las entradas del CRUD prevalecen sobre estas si comparten nombre o sinónimo.
+var habilidadesBase = []internaldto.Habilidad{
+	{Nombre: "Go", Categoria: "Lenguajes de programación", Sinonimos: []string{"golang"}},
+	{Nombre: "Python", Categoria: "Lenguajes de programación", Sinonimos: []string{"py"}},
+	{Nombre: "Java", Categoria: "Lenguajes de programación"},
+	{Nombre: "JavaScript", Categoria: "Lenguajes de programación", Sinonimos: []string{"js", "ecmascript"}},
+	{Nombre: "TypeScript", Categoria: "Lenguajes de programación", Sinonimos: []string{"ts"}},
+	{Nombre: "C#", Categoria: "Lenguajes de programación", Sinonimos: []string{"csharp", "c sharp"}},
+	{Nombre: "React", Categoria: "Frameworks", Sinonimos: []string{"reactjs", "react.js"}},
+	{Nombre: "Angular", Categoria: "Frameworks", Sinonimos: []string{"angularjs"}},
+	{Nombre: "Node.js", Categoria: "Frameworks", Sinonimos: []string{"node", "nodejs"}},
+	{Nombre: "SQL", Categoria: "Datos", Sinonimos: []string{"postgresql", "postgres", "mysql"}},
+	{Nombre: "Power BI", Categoria: "Datos", Sinonimos: []string{"powerbi"}},
+	{Nombre: "Excel", Categoria: "Ofimática", Sinonimos: []string{"microsoft excel", "ms excel", "hojas de calculo"}},
+	{Nombre: "Git", Categoria: "Herramientas", Sinonimos: []string{"github", "gitlab"}},
+	{Nombre: "Docker", Categoria: "Herramientas", Sinonimos: []string{"contenedores"}},
+	{Nombre: "AutoCAD", Categoria: "Diseño", Sinonimos: []string{"auto cad"}},
+	{Nombre: "Inglés", Categoria: "Idiomas", Sinonimos: []string{"ingles", "english"}},
+	{Nombre: "Trabajo en equipo", Categoria: "Habilidades blandas", Sinonimos: []string{"teamwork"}},
+	{Nombre: "Comunicación", Categoria: "Habilidades blandas", Sinonimos: []string{"comunicacion asertiva"}},
+}
+
+type taxonomiaHabilidades struct {
+	items    []internaldto.Habilidad
+	porClave map[string]int
+}
+
+var cacheHabilidades struct {
+	mu        sync.RWMutex
+	expiresAt time.Time
+	data      *taxonomiaHabilidades
+}
+
+// ListarHabilidades retorna el catálogo filtrado por texto (nombre o sinónimo) y categoría.
+func ListarHabilidades(q, categoria string) map[string]interface{} {
+	tax := cargarTaxonomiaHabilidades()
+	q = normalizarTexto(q)
+	categoria = normalizarTexto(categoria)
+
+	items := make([]internaldto.Habilidad, 0, len(tax.items))
+	for _, h := range tax.items {
+		if categoria != "" && normalizarTexto(h.Categoria) != categoria {
+			continue
+		}
+		if q != "" && !habilidadCoincide(h, q) {
+			continue
+		}
+		if h.Sinonimos == nil {
+			h.Sinonimos = []string{}
+		}
+		items = append(items, h)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Categoria != items[j].Categoria {
+			return items[i].Categoria < items[j].Categoria
+		}
+		return items[i].Nombre < items[j].Nombre
+	})
+
+	return map[string]interface{}{
+		"items":   items,
+		"total":   len(items),
+		"niveles": NivelesHabilidad,
+	}
+}
+
+// CrearHabilidad agrega una habilidad al catálogo del CRUD.
+func CrearHabilidad(req internaldto.HabilidadUpsert) (*internaldto.Habilidad, error) {
+	body, err := validarHabilidadUpsert(0, req)
+	if err != nil {
+		return nil, err
+	}
+	body["Activo"] = true
+	body["FechaCreacion"] = nowISO()
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, habilidadResource)
+	var created map[string]interface{}
+	if err := helpers.DoJSON("POST", endpoint, body, &created, cfg.RequestTimeout); err != nil {
+		return nil, helpers.AsAppError(err, "error creando habilidad")
+	}
+	invalidarCacheHabilidades()
+	h := mapHabilidad(created)
+	return &h, nil
+}
+
+// ActualizarHabilidad reemplaza nombre, categoría y sinónimos de una habilidad del catálogo.
+func ActualizarHabilidad(id int, req internaldto.HabilidadUpsert) (*internaldto.Habilidad, error) {
+	if id <= 0 {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "id inválido", nil)
+	}
+	body, err := validarHabilidadUpsert(id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, habilidadResource, strconv.Itoa(id))
+	var updated map[string]interface{}
+	if err := helpers.DoJSON("PUT", endpoint, body, &updated, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil, helpers.NewAppError(http.StatusNotFound, "habilidad no encontrada", nil)
+		}
+		return nil, helpers.AsAppError(err, "error actualizando habilidad")
+	}
+	invalidarCacheHabilidades()
+	h := mapHabilidad(updated)
+	return &h, nil
+}
+
+// canonizarHabilidad busca la habilidad del catálogo por nombre o sinónimo.
+func canonizarHabilidad(nombre string) (internaldto.Habilidad, bool) {
+	clave := normalizarTexto(nombre)
+	if clave == "" {
+		return internaldto.Habilidad{}, false
+	}
+	tax := cargarTaxonomiaHabilidades()
+	idx, ok := tax.porClave[clave]
+	if !ok {
+		return internaldto.Habilidad{}, false
+	}
+	return tax.items[idx], true
+}
+
+// nombreCanonicoHabilidad retorna el nombre del catálogo o el texto recortado si no está catalogado.
+func nombreCanonicoHabilidad(nombre string) string {
+	if h, ok := canonizarHabilidad(nombre); ok {
+		return h.Nombre
+	}
+	return strings.TrimSpace(nombre)
+}
+
+// canonizarTerminosHabilidad lleva a nombre canónico cada término de una lista separada por comas,
+// de modo que un filtro por sinónimo encuentre los perfiles ya normalizados.
+func canonizarTerminosHabilidad(csv string) string {
+	parts := strings.Split(csv, ",")
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if s := nombreCanonicoHabilidad(part); s != "" {
+			out = append(out, s)
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+// normalizarHabilidadesPerfil interpreta las habilidades recibidas (arreglo de textos, arreglo de
+// objetos {nombre, nivel}, objeto agrupado por categoría o texto separado por comas) y las lleva
+// al catálogo. Con estricto = false los niveles no reconocidos se descartan en vez de fallar.
+func normalizarHabilidadesPerfil(raw string, estricto bool) ([]internaldto.HabilidadPerfil, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return nil, nil
+	}
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(trimmed), &parsed); err != nil {
+		parsed = trimmed
+	}
+
+	out := make([]internaldto.HabilidadPerfil, 0)
+	indice := make(map[string]int)
+	agregar := func(nombre, nivelRaw string) error {
+		nombre = strings.TrimSpace(nombre)
+		if nombre == "" {
+			return nil
+		}
+		if len([]rune(nombre)) > habilidadMaxLongitud {
+			return helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("habilidad demasiado larga (máx %d caracteres)", habilidadMaxLongitud), nil)
+		}
+		nivel, ok := normalizarNivelHabilidad(nivelRaw)
+		if !ok {
+			if estricto {
+				return helpers.NewAppError(http.StatusBadRequest, "nivel de habilidad inválido: "+strings.TrimSpace(nivelRaw), nil)
+			}
+			nivel = ""
+		}
+		item := internaldto.HabilidadPerfil{Nombre: nombre, Nivel: nivel}
+		if h, ok := canonizarHabilidad(nombre); ok {
+			item.Nombre = h.Nombre
+			item.Categoria = h.Categoria
+		}
+		clave := normalizarTexto(item.Nombre)
+		if idx, ok := indice[clave]; ok {
+			if out[idx].Nivel == "" {
+				out[idx].Nivel = item.Nivel
+			}
+			return nil
+		}
+		indice[clave] = len(out)
+		out = append(out, item)
+		return nil
+	}
+
+	var walk func(v interface{}) error
+	walk = func(v interface{}) error {
+		switch t := v.(type) {
+		case string:
+			for _, part := range strings.FieldsFunc(t, func(r rune) bool { return r == ',' || r == ';' || r == '|' }) {
+				if err := agregar(part, ""); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for _, item := range t {
+				if err := walk(item); err != nil {
+					return err
+				}
+			}
+		case map[string]interface{}:
+			if nombre, ok := habilidadNombreEnObjeto(t); ok {
+				return agregar(nombre, normalizeToString(firstPresent(t, "nivel", "Nivel")))
+			}
+			keys := make([]string, 0, len(t))
+			for k := range t {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				if err := walk(t[k]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(parsed); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// serializarHabilidadesPerfil produce el formato persistido en el CRUD (arreglo JSON).
+func serializarHabilidadesPerfil(items []internaldto.HabilidadPerfil) string {
+	if len(items) == 0 {
+		return ""
+	}
+	encoded, err := json.Marshal(items)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+func normalizarNivelHabilidad(raw string) (string, bool) {
+	clave := normalizarTexto(raw)
+	if clave == "" {
+		return "", true
+	}
+	nivel, ok := nivelesHabilidadAlias[clave]
+	return nivel, ok
+}
+
+func habilidadNombreEnObjeto(m map[string]interface{}) (string, bool) {
+	v := firstPresent(m, "nombre", "Nombre")
+	if v == nil {
+		return "", false
+	}
+	return normalizeToString(v), true
+}
+
+func firstPresent(m map[string]interface{}, keys ...string) interface{} {
+	for _, k := range keys {
+		if v, ok := m[k]; ok && v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func habilidadCoincide(h internaldto.Habilidad, q string) bool {
+	if strings.Contains(normalizarTexto(h.Nombre), q) {
+		return true
+	}
+	for _, s := range h.Sinonimos {
+		if strings.Contains(normalizarTexto(s), q) {
+			return true
+		}
+	}
+	return false
+}
+
+func validarHabilidadUpsert(id int, req internaldto.HabilidadUpsert) (map[string]interface{}, error) {
+	nombre := strings.TrimSpace(req.Nombre)
+	if nombre == "" {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "nombre requerido", nil)
+	}
+	if len([]rune(nombre)) > habilidadMaxLongitud {
+		return nil, helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("nombre demasiado largo (máx %d caracteres)", habilidadMaxLongitud), nil)
+	}
+	categoria := strings.TrimSpace(req.Categoria)
+	if categoria == "" {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "categoria requerida", nil)
+	}
+
+	claves := map[string]struct{}{normalizarTexto(nombre): {}}
+	sinonimos := make([]string, 0, len(req.Sinonimos))
+	for _, s := range req.Sinonimos {
+		s = strings.TrimSpace(s)
+		clave := normalizarTexto(s)
+		if clave == "" {
+			continue
+		}
+		if _, dup := claves[clave]; dup {
+			continue
+		}
+		claves[clave] = struct{}{}
+		sinonimos = append(sinonimos, s)
+	}
+
+	// Solo se valida contra el catálogo del CRUD: una entrada base puede sobrescribirse.
+	tax := cargarTaxonomiaHabilidades()
+	for clave := range claves {
+		idx, ok := tax.porClave[clave]
+		if !ok {
+			continue
+		}
+		if otra := tax.items[idx]; otra.ID > 0 && otra.ID != id {
+			return nil, helpers.NewAppError(http.StatusConflict, fmt.Sprintf("'%s' ya pertenece a la habilidad %s", clave, otra.Nombre), nil)
+		}
+	}
+
+	encoded, _ := json.Marshal(sinonimos)
+	return map[string]interface{}{
+		"Nombre":            nombre,
+		"Categoria":         categoria,
+		"Sinonimos":         string(encoded),
+		"FechaModificacion": nowISO(),
+	}, nil
+}
+
+func cargarTaxonomiaHabilidades() *taxonomiaHabilidades {
+	cacheHabilidades.mu.RLock()
+	if cacheHabilidades.data != nil && time.Now().Before(cacheHabilidades.expiresAt) {
+		data := cacheHabilidades.data
+		cacheHabilidades.mu.RUnlock()
+		return data
+	}
+	cacheHabilidades.mu.RUnlock()
+
+	cacheHabilidades.mu.Lock()
+	defer cacheHabilidades.mu.Unlock()
+	if cacheHabilidades.data != nil && time.Now().Before(cacheHabilidades.expiresAt) {
+		return cacheHabilidades.data
+	}
+
+	ttl := cacheTTL
+	crud, err := listHabilidadesCRUD()
+	if err != nil {
+		logs.Warn("catálogo de habilidades no disponible, se usa el catálogo base:", err)
+		ttl = habilidadesRetryTTL
+	}
+	cacheHabilidades.data = construirTaxonomia(crud, habilidadesBase)
+	cacheHabilidades.expiresAt = time.Now().Add(ttl)
+	return cacheHabilidades.data
+}
+
+func invalidarCacheHabilidades() {
+	cacheHabilidades.mu.Lock()
+	cacheHabilidades.data = nil
+	cacheHabilidades.mu.Unlock()
+}
+
+func construirTaxonomia(crud, base []internaldto.Habilidad) *taxonomiaHabilidades {
+	tax := &taxonomiaHabilidades{porClave: make(map[string]int)}
+	agregar := func(h internaldto.Habilidad) {
+		claves := append([]string{h.Nombre}, h.Sinonimos...)
+		for _, c := range claves {
+			if _, ocupada := tax.porClave[normalizarTexto(c)]; ocupada && h.ID == 0 {
+				return
+			}
+		}
+		idx := len(tax.items)
+		tax.items = append(tax.items, h)
+		for _, c := range claves {
+			if clave := normalizarTexto(c); clave != "" {
+				if _, ocupada := tax.porClave[clave]; !ocupada {
+					tax.porClave[clave] = idx
+				}
+			}
+		}
+	}
+	for _, h := range crud {
+		agregar(h)
+	}
+	for _, h := range base {
+		agregar(h)
+	}
+	return tax
+}
+
+func listHabilidadesCRUD() ([]internaldto.Habilidad, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, habilidadResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+	values.Set("query", "Activo:true")
+
+	var records []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &records, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out := make([]internaldto.Habilidad, 0, len(records))
+	for _, rec := range records {
+		if h := mapHabilidad(rec); h.Nombre != "" {
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+
+func mapHabilidad(rec map[string]interface{}) internaldto.Habilidad {
+	id, _ := normalizeToInt(rec["Id"])
+	sinonimos := parseHabilidades(rec["Sinonimos"])
+	if sinonimos == nil {
+		sinonimos = []string{}
+	}
+	return internaldto.Habilidad{
+		ID:        id,
+		Nombre:    strings.TrimSpace(normalizeToString(rec["Nombre"])),
+		Categoria: strings.TrimSpace(normalizeToString(rec["Categoria"])),
+		Sinonimos: sinonimos,
+	}
+}
@@ -77,6 +77,7 @@ func CrearInvitacion(ctx context.Context, tutorID, perfilID int, payload interna
 	out := normalizeInvitacion(created)
 	enrichInvitacionEstados([]map[string]interface{}{out})
 	attachOfertaResumen([]map[string]interface{}{out})
+	publicarEventoInvitacion(EventoInvitacionCreada, out, tutorID, 0, perfilID)
 	return out, nil
 }
 
@@ -132,7 +133,7 @@ func BandejaTutor(ctx context.Context, tutorID int, estado string, page, size in
 
 	enrichInvitacionEstados(items)
 	attachOfertaResumen(items)
-	enrichBandejaTutorConEstudiantes(ctx, items)
+	enrichBandejaTutorConEstudiantes(ctx, tutorID, items)
 
 	// paginación manual (sin usar paginate global del package)
 	total := len(items)
@@ -297,7 +298,10 @@ func GetInvitacionDetalle(ctx context.Context, invitacionID int, tutorID int, es
 		enrichInvitacionEstados([]map[string]interface{}{inv})
 		attachOfertaResumen([]map[string]interface{}{inv})
 		enrichInvitacionDetalle(ctx, inv) // aquí sí
-		enrichInvitacionConEstudianteDetalle(ctx, inv)
+		perfil := enrichInvitacionConEstudianteDetalle(ctx, inv)
+		if estudianteID <= 0 {
+			anonimizarInvitacionDetalle(ctx, tutorID, inv, perfil)
+		}
 		return inv, nil
 	} else if !helpers.IsHTTPError(err, http.StatusNotFound) && !helpers.IsHTTPError(err, http.StatusMethodNotAllowed) {
 		return nil, helpers.AsAppError(err, "error consultando invitación")
@@ -311,7 +315,8 @@ func GetInvitacionDetalle(ctx context.Context, invitacionID int, tutorID int, es
 			enrichInvitacionEstados([]map[string]interface{}{inv})
 			attachOfertaResumen([]map[string]interface{}{inv})
 			enrichInvitacionDetalle(ctx, inv) // agregado
-			enrichInvitacionConEstudianteDetalle(ctx, inv)
+			perfil := enrichInvitacionConEstudianteDetalle(ctx, inv)
+			anonimizarInvitacionDetalle(ctx, tutorID, inv, perfil)
 			return inv, nil
 		}
 	}
@@ -387,10 +392,19 @@ func AceptarInvitacion(ctx context.Context, invitacionID int, terceroID int) (ma
 	out := normalizeInvitacion(updated)
 	enrichInvitacionEstados([]map[string]interface{}{out})
 	attachOfertaResumen([]map[string]interface{}{out})
+	invalidarContactosTutor(tutorID)
+	publicarEventoInvitacion(EventoInvitacionRespondida, out, tutorID, terceroID, 0)
 
 	// (Opcional)
 	if ofertaID := extractOfertaID(out); ofertaID > 0 {
-		_ = crearPostulacionDesdeInvitacion(int64(terceroID), ofertaID)
+		if err := crearPostulacionDesdeInvitacion(int64(terceroID), ofertaID); err == nil {
+			PublicarEvento(Evento{
+				Tipo:         EventoPostulacionCreada,
+				EstudianteID: int64(terceroID),
+				TutorID:      int64(tutorID),
+				OfertaID:     ofertaID,
+			})
+		}
 	}
 
 	return out, nil
@@ -437,9 +451,37 @@ func RechazarInvitacion(ctx context.Context, invitacionID int, terceroID int) (m
 	out := normalizeInvitacion(updated)
 	enrichInvitacionEstados([]map[string]interface{}{out})
 	attachOfertaResumen([]map[string]interface{}{out})
+	publicarEventoInvitacion(EventoInvitacionRespondida, out, tutorID, terceroID, 0)
 	return out, nil
 }
 
+// publicarEventoInvitacion informa al bus el cambio sobre la invitación normalizada. Con
+// perfilID = 0 el perfil se toma de la invitación.
+func publicarEventoInvitacion(tipo string, inv map[string]interface{}, tutorID, estudianteID, perfilID int) {
+	e := Evento{
+		Tipo:         tipo,
+		TutorID:      int64(tutorID),
+		EstudianteID: int64(estudianteID),
+		PerfilID:     int64(perfilID),
+		OfertaID:     extractOfertaID(inv),
+	}
+	if id, ok := toInt(inv["id"]); ok {
+		e.InvitacionID = int64(id)
+	}
+	if e.PerfilID == 0 {
+		if id, ok := toInt(inv["perfil_estudiante_id"]); ok {
+			e.PerfilID = int64(id)
+		}
+	}
+	if estado, ok := inv["estado"].(string); ok {
+		e.Estado = estado
+	}
+	if mensaje, ok := inv["mensaje"].(string); ok && strings.TrimSpace(mensaje) != "" {
+		e.Datos = map[string]interface{}{"mensaje": strings.TrimSpace(mensaje)}
+	}
+	PublicarEvento(e)
+}
+
 // Busca la invitación (por id) dentro de la bandeja del estudiante (CRUD).
 func findInvitacionInBandejaEstudiante(ctx context.Context, terceroID int, invitacionID int) (map[string]interface{}, error) {
 	cfg := rootservices.GetConfig()
@@ -784,21 +826,22 @@ func enrichInvitacionDetalle(ctx context.Context, inv map[string]interface{}) {
 	}
 }
 
-func enrichInvitacionConEstudianteDetalle(ctx context.Context, inv map[string]interface{}) {
+// enrichInvitacionConEstudianteDetalle completa estudiante_detalle con la identidad del
+// estudiante y devuelve el perfil consultado, o nil si no fue posible obtenerlo.
+func enrichInvitacionConEstudianteDetalle(ctx context.Context, inv map[string]interface{}) *clients.PerfilRecord {
 	if inv == nil {
-		return
+		return nil
 	}
 
 	perfilID, ok := toInt(inv["perfil_estudiante_id"])
 	if !ok || perfilID <= 0 {
-		return
+		return nil
 	}
 
 	crud := clients.CastorCRUD()
 	perfilRec, err := crud.GetPerfilByID(ctx, perfilID)
 	if err != nil || perfilRec == nil {
-
-		return
+		return nil
 	}
 
 	terceroID := perfilRec.TerceroId
@@ -824,6 +867,7 @@ func enrichInvitacionConEstudianteDetalle(ctx context.Context, inv map[string]in
 	}
 
 	inv["estudiante_detalle"] = detalle
+	return perfilRec
 }
 
 func enrichInvitacionesConEstudianteResumen(ctx context.Context, items []map[string]interface{}) {
@@ -918,7 +962,7 @@ func nombreProyectoCurricular(ctx context.Context, pcID int) string {
 	return strings.TrimSpace(detalle.Nombre)
 }
 
-func enrichBandejaTutorConEstudiantes(ctx context.Context, items []map[string]interface{}) {
+func enrichBandejaTutorConEstudiantes(ctx context.Context, tutorID int, items []map[string]interface{}) {
 	if len(items) == 0 {
 		return
 	}
@@ -960,6 +1004,10 @@ func enrichBandejaTutorConEstudiantes(ctx context.Context, items []map[string]in
 		}
 
 		terceroID := perfil.TerceroId
+		if ocultaEstudianteInvitacion(ctx, tutorID, item, perfil) {
+			resumen["anonimo"] = true
+			terceroID = 0
+		}
 		if terceroID > 0 {
 			resumen["tercero_id"] = terceroID
 			if nombre, ok := nombreCache[terceroID]; ok {
@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	"github.com/udistrital/pasantia_mid/internal/clients"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	"github.com/udistrital/pasantia_mid/models"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+// Pesos del puntaje de afinidad estudiante-oferta; suman 1.
+const (
+	pesoMatchPC          = 0.35
+	pesoMatchHabilidades = 0.35
+	pesoMatchModalidad   = 0.10
+	pesoMatchCiudad      = 0.10
+	pesoMatchRecencia    = 0.10
+
+	// Con preferencias o requisitos ausentes se otorga crédito parcial para no castigar ni premiar.
+	creditoNeutro = 0.5
+
+	recenciaVidaMediaDias = 14.0
+	matchConcurrency      = 8
+)
+
+// perfilMatching resume los datos del estudiante usados para puntuar ofertas.
+type perfilMatching struct {
+	PCID        int
+	Habilidades []string
+	Modalidad   string
+	Ciudad      string
+}
+
+// ofertaMatch es una oferta puntuada con las razones de la coincidencia.
+type ofertaMatch struct {
+	Oferta  models.Oferta
+	PCIDs   []int
+	Puntaje float64
+	Razones []string
+}
+
+// RecomendarOfertas retorna las ofertas abiertas ordenadas por afinidad con el perfil del estudiante.
+func RecomendarOfertas(ctx context.Context, estudianteID, page, size int) (internaldto.PageDTO[map[string]interface{}], error) {
+	out := internaldto.PageDTO[map[string]interface{}]{Items: []map[string]interface{}{}, Page: page, Size: size}
+	if estudianteID <= 0 {
+		return out, helpers.NewAppError(http.StatusBadRequest, "estudiante_id inválido", nil)
+	}
+
+	perfil, err := clients.CastorCRUD().GetPerfilByTerceroID(ctx, estudianteID)
+	if err != nil {
+		return out, helpers.AsAppError(err, "error consultando perfil")
+	}
+	if perfil == nil || perfil.Id == 0 {
+		return out, helpers.NewAppError(http.StatusNotFound, "perfil no encontrado", nil)
+	}
+
+	matches, err := rankOfertasParaEstudiante(ctx, estudianteID, perfilMatchingDesde(perfil))
+	if err != nil {
+		return out, err
+	}
+
+	out.Total = int64(len(matches))
+	start := (page - 1) * size
+	if start >= len(matches) {
+		return out, nil
+	}
+	end := start + size
+	if end > len(matches) {
+		end = len(matches)
+	}
+	for _, m := range matches[start:end] {
+		out.Items = append(out.Items, m.toMap())
+	}
+	return out, nil
+}
+
+// rankOfertasParaEstudiante puntúa las ofertas abiertas a las que el estudiante no se ha postulado.
+// Las ofertas restringidas a otros proyectos curriculares se excluyen.
+func rankOfertasParaEstudiante(ctx context.Context, estudianteID int, perfil perfilMatching) ([]ofertaMatch, error) {
+	postuladas := make(map[int64]struct{})
+	if list, err := clients.CastorCRUD().ListPostulaciones(ctx, map[string]string{
+		"EstudianteId": fmt.Sprint(estudianteID),
+	}); err == nil {
+		for _, p := range list {
+			if p.OfertaId > 0 {
+				postuladas[p.OfertaId] = struct{}{}
+			}
+		}
+	}
+
+	ofertas, err := rootservices.ListOfertas(map[string]string{"estado": models.OfertaEstadoCreada})
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error consultando ofertas")
+	}
+
+	candidatas := make([]models.Oferta, 0, len(ofertas))
+	for _, o := range ofertas {
+		if _, ok := postuladas[o.Id]; !ok {
+			candidatas = append(candidatas, o)
+		}
+	}
+
+	pcsPorOferta := make([][]int, len(candidatas))
+	errs := make([]error, len(candidatas))
+	sem := make(chan struct{}, matchConcurrency)
+	var wg sync.WaitGroup
+	for i, o := range candidatas {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int, ofertaID int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			pcsPorOferta[idx], errs[idx] = getPCIDsByOferta(ofertaID)
+		}(i, int(o.Id))
+	}
+	wg.Wait()
+
+	now := time.Now()
+	matches := make([]ofertaMatch, 0, len(candidatas))
+	for i, o := range candidatas {
+		if errs[i] != nil {
+			continue
+		}
+		if m, ok := puntuarOferta(perfil, o, pcsPorOferta[i], now); ok {
+			matches = append(matches, m)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Puntaje != matches[j].Puntaje {
+			return matches[i].Puntaje > matches[j].Puntaje
+		}
+		return matches[i].Oferta.FechaPublicacion.After(matches[j].Oferta.FechaPublicacion)
+	})
+	return matches, nil
+}
+
+// puntuarOferta calcula la afinidad (0-100) de la oferta con el perfil y explica el resultado.
+func puntuarOferta(perfil perfilMatching, oferta models.Oferta, pcIDs []int, now time.Time) (ofertaMatch, bool) {
+	puntaje, razones, ok := puntuarCoincidencia(perfil, oferta, pcIDs, oferta.FechaPublicacion, now, razonesParaEstudiante)
+	if !ok {
+		return ofertaMatch{}, false
+	}
+	return ofertaMatch{
+		Oferta:  oferta,
+		PCIDs:   pcIDs,
+		Puntaje: puntaje,
+		Razones: razones,
+	}, true
+}
+
+// razonesMatch redacta las razones del puntaje según quién las lee: el estudiante que ve ofertas
+// recomendadas o el tutor que ve candidatos. Los formatos con %s reciben el valor de la oferta.
+type razonesMatch struct {
+	pcAbierta   string
+	pcPropio    string
+	habilidades string // cumplidas, requeridas, lista
+	modalidad   string
+	ciudad      string
+	reciente    string
+}
+
+var (
+	razonesParaEstudiante = razonesMatch{
+		pcAbierta:   "Abierta a todos los proyectos curriculares",
+		pcPropio:    "Dirigida a tu proyecto curricular",
+		habilidades: "Tienes %d de %d habilidades requeridas: %s",
+		modalidad:   "Modalidad %s como prefieres",
+		ciudad:      "En tu ciudad preferida: %s",
+		reciente:    "Publicada recientemente",
+	}
+	razonesParaTutor = razonesMatch{
+		pcPropio:    "Pertenece a un proyecto curricular de la oferta",
+		habilidades: "Cumple %d de %d habilidades requeridas: %s",
+		modalidad:   "Prefiere modalidad %s",
+		ciudad:      "Prefiere trabajar en %s",
+		reciente:    "Perfil actualizado recientemente",
+	}
+)
+
+// puntuarCoincidencia calcula el puntaje (0-100) entre un perfil y una oferta, igual para las
+// ofertas recomendadas y para los candidatos de una oferta. referencia es la fecha que da el
+// componente de recencia: publicación de la oferta o actualización del perfil. Retorna false si
+// la oferta está restringida a proyectos curriculares que no incluyen el del perfil.
+func puntuarCoincidencia(perfil perfilMatching, oferta models.Oferta, pcIDs []int, referencia, now time.Time, textos razonesMatch) (float64, []string, bool) {
+	var score float64
+	razones := make([]string, 0, 4)
+
+	switch {
+	case len(pcIDs) == 0:
+		score += pesoMatchPC * creditoNeutro
+		if textos.pcAbierta != "" {
+			razones = append(razones, textos.pcAbierta)
+		}
+	case perfil.PCID > 0 && hasInt(pcIDs, perfil.PCID):
+		score += pesoMatchPC
+		razones = append(razones, textos.pcPropio)
+	default:
+		return 0, nil, false
+	}
+
+	comunes := interseccionHabilidades(perfil.Habilidades, oferta.Habilidades)
+	switch {
+	case len(oferta.Habilidades) == 0:
+		score += pesoMatchHabilidades * creditoNeutro
+	case len(comunes) > 0:
+		score += pesoMatchHabilidades * float64(len(comunes)) / float64(len(oferta.Habilidades))
+		razones = append(razones, fmt.Sprintf(textos.habilidades,
+			len(comunes), len(oferta.Habilidades), strings.Join(comunes, ", ")))
+	}
+
+	modalidadOferta := normalizarTexto(oferta.Modalidad)
+	switch {
+	case perfil.Modalidad == "" || modalidadOferta == "":
+		score += pesoMatchModalidad * creditoNeutro
+	case perfil.Modalidad == modalidadOferta:
+		score += pesoMatchModalidad
+		razones = append(razones, fmt.Sprintf(textos.modalidad, strings.ToLower(strings.TrimSpace(oferta.Modalidad))))
+	}
+
+	ciudadOferta := normalizarTexto(oferta.Ciudad)
+	switch {
+	case esModalidadRemota(modalidadOferta):
+		score += pesoMatchCiudad
+	case perfil.Ciudad == "" || ciudadOferta == "":
+		score += pesoMatchCiudad * creditoNeutro
+	case perfil.Ciudad == ciudadOferta:
+		score += pesoMatchCiudad
+		razones = append(razones, fmt.Sprintf(textos.ciudad, strings.TrimSpace(oferta.Ciudad)))
+	}
+
+	if !referencia.IsZero() {
+		dias := now.Sub(referencia).Hours() / 24
+		if dias < 0 {
+			dias = 0
+		}
+		score += pesoMatchRecencia * math.Pow(0.5, dias/recenciaVidaMediaDias)
+		if dias <= 7 {
+			razones = append(razones, textos.reciente)
+		}
+	}
+
+	return math.Round(score*10000) / 100, razones, true
+}
+
+func (m ofertaMatch) toMap() map[string]interface{} {
+	entry := mapOferta(m.Oferta)
+	entry["proyecto_curricular_ids"] = m.PCIDs
+	entry["puntaje_match"] = m.Puntaje
+	entry["razones"] = m.Razones
+	return entry
+}
+
+func perfilMatchingDesde(perfil *clients.PerfilRecord) perfilMatching {
+	if perfil == nil {
+		return perfilMatching{}
+	}
+	return perfilMatching{
+		PCID:        perfil.ProyectoCurricularId,
+		Habilidades: extraerHabilidades(perfil.Habilidades),
+		Modalidad:   normalizarTexto(normalizeToString(perfil.Extra["ModalidadPreferida"])),
+		Ciudad:      normalizarTexto(normalizeToString(perfil.Extra["CiudadPreferida"])),
+	}
+}
+
+// extraerHabilidades interpreta las habilidades guardadas como arreglo JSON (de textos o de
+// objetos {nombre, nivel}), objeto JSON con listas (ej. {"stack":["Go"]}) o texto separado por comas.
+func extraerHabilidades(raw string) []string {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return nil
+	}
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(trimmed), &parsed); err != nil {
+		parsed = trimmed
+	}
+
+	var out []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case string:
+			for _, part := range strings.FieldsFunc(t, func(r rune) bool { return r == ',' || r == ';' || r == '|' }) {
+				if p := strings.TrimSpace(part); p != "" {
+					out = append(out, p)
+				}
+			}
+		case []interface{}:
+			for _, item := range t {
+				walk(item)
+			}
+		case map[string]interface{}:
+			if nombre, ok := habilidadNombreEnObjeto(t); ok {
+				walk(nombre)
+				return
+			}
+			for _, item := range t {
+				walk(item)
+			}
+		}
+	}
+	walk(parsed)
+	return out
+}
+
+// interseccionHabilidades retorna las habilidades requeridas que el estudiante declara.
+func interseccionHabilidades(estudiante, requeridas []string) []string {
+	if len(estudiante) == 0 || len(requeridas) == 0 {
+		return nil
+	}
+	propias := make(map[string]struct{}, len(estudiante))
+	for _, h := range estudiante {
+		propias[normalizarHabilidad(h)] = struct{}{}
+	}
+	comunes := make([]string, 0, len(requeridas))
+	for _, h := range requeridas {
+		if _, ok := propias[normalizarHabilidad(h)]; ok {
+			comunes = append(comunes, strings.TrimSpace(h))
+		}
+	}
+	return comunes
+}
+
+// normalizarHabilidad compara por nombre canónico, de modo que los sinónimos coincidan.
+func normalizarHabilidad(h string) string {
+	return normalizarTexto(nombreCanonicoHabilidad(h))
+}
+
+var reemplazoTildes = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+	"Á", "a", "É", "e", "Í", "i", "Ó", "o", "Ú", "u", "Ü", "u", "Ñ", "n",
+)
+
+// normalizarTexto compara textos libres sin distinguir mayúsculas, tildes ni espacios repetidos.
+func normalizarTexto(s string) string {
+	s = strings.ToLower(reemplazoTildes.Replace(strings.TrimSpace(s)))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func esModalidadRemota(modalidad string) bool {
+	switch modalidad {
+	case "remota", "remoto", "virtual", "teletrabajo":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/beego/beego/v2/core/logs"
+
+	"github.com/udistrital/pasantia_mid/internal/clients"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	rootmodels "github.com/udistrital/pasantia_mid/models"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+var notificacionesDominioOnce sync.Once
+
+// IniciarNotificacionesDominio suscribe al bus de eventos el envío de las notificaciones con
+// plantilla: invitación recibida, cambio de estado o selección de la postulación y cancelación de
+// la oferta. Las consultas y el envío corren fuera del request que publicó el evento.
+func IniciarNotificacionesDominio() {
+	notificacionesDominioOnce.Do(func() {
+		SuscribirEventos(func(e Evento) {
+			switch {
+			case e.Tipo == EventoInvitacionCreada,
+				e.Tipo == EventoPostulacionActualizada && e.TutorID > 0,
+				e.Tipo == EventoOfertaEstado && e.Estado == rootmodels.OfertaEstadoCancelada:
+				go notificarEvento(e)
+			}
+		})
+	})
+}
+
+func notificarEvento(e Evento) {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("notificación del evento", e.Tipo, ":", r)
+		}
+	}()
+
+	switch e.Tipo {
+	case EventoInvitacionCreada:
+		notificarInvitacionRecibida(e)
+	case EventoPostulacionActualizada:
+		notificarEstadoPostulacion(e)
+	case EventoOfertaEstado:
+		notificarOfertaCancelada(e)
+	}
+}
+
+// notificarInvitacionRecibida avisa al estudiante dueño del perfil invitado.
+func notificarInvitacionRecibida(e Evento) {
+	ctx := context.Background()
+	perfil, err := clients.CastorCRUD().GetPerfilByID(ctx, int(e.PerfilID))
+	if err != nil || perfil == nil || perfil.TerceroId <= 0 {
+		logs.Warn("invitación", e.InvitacionID, ": no se pudo resolver el estudiante del perfil", e.PerfilID)
+		return
+	}
+	datos := datosNotificacionOferta(ctx, perfil.TerceroId, e.OfertaID)
+	datos["invitacion_id"] = e.InvitacionID
+	datos["mensaje"] = ""
+	if m, ok := e.Datos["mensaje"].(string); ok {
+		datos["mensaje"] = m
+	}
+	enviarNotificacionDominio(perfil.TerceroId, "Nueva invitación a postular", internalhelpers.PlantillaInvitacionRecibida, datos)
+}
+
+// notificarEstadoPostulacion avisa al estudiante los cambios que hace el tutor sobre su postulación.
+func notificarEstadoPostulacion(e Evento) {
+	if e.EstudianteID <= 0 {
+		return
+	}
+	ctx := context.Background()
+	datos := datosNotificacionOferta(ctx, int(e.EstudianteID), e.OfertaID)
+	datos["postulacion_id"] = e.PostulacionID
+	if e.Estado == rootmodels.PostEstadoSeleccionada {
+		enviarNotificacionDominio(int(e.EstudianteID), "Postulación seleccionada", internalhelpers.PlantillaPostulacionSeleccionada, datos)
+		return
+	}
+	datos["estado"] = e.Estado
+	datos["estado_nombre"] = resolveEstadoNombre(e.Estado)
+	enviarNotificacionDominio(int(e.EstudianteID), "Cambio de estado de la postulación", internalhelpers.PlantillaPostulacionEstado, datos)
+}
+
+// notificarOfertaCancelada avisa a cada estudiante que había postulado a la oferta.
+func notificarOfertaCancelada(e Evento) {
+	postulaciones, err := rootservices.ListPostulacionesByOferta(e.OfertaID)
+	if err != nil {
+		logs.Warn("oferta", e.OfertaID, "cancelada: no se pudieron consultar las postulaciones:", err)
+		return
+	}
+	ctx := context.Background()
+	titulo := tituloOferta(e.OfertaID)
+	avisados := make(map[int64]struct{}, len(postulaciones))
+	for _, p := range postulaciones {
+		if p.EstudianteId <= 0 {
+			continue
+		}
+		if _, ok := avisados[p.EstudianteId]; ok {
+			continue
+		}
+		avisados[p.EstudianteId] = struct{}{}
+		datos := map[string]interface{}{
+			"nombre":        strings.TrimSpace(NombreCompletoPorIDCoreStd(ctx, int(p.EstudianteId))),
+			"oferta_titulo": titulo,
+			"oferta_id":     e.OfertaID,
+		}
+		enviarNotificacionDominio(int(p.EstudianteId), "Oferta cancelada", internalhelpers.PlantillaOfertaCancelada, datos)
+	}
+}
+
+// datosNotificacionOferta arma las variables comunes: nombre del destinatario y título de la oferta.
+func datosNotificacionOferta(ctx context.Context, terceroID int, ofertaID int64) map[string]interface{} {
+	return map[string]interface{}{
+		"nombre":        strings.TrimSpace(NombreCompletoPorIDCoreStd(ctx, terceroID)),
+		"oferta_titulo": tituloOferta(ofertaID),
+		"oferta_id":     ofertaID,
+	}
+}
+
+func tituloOferta(ofertaID int64) string {
+	if ofertaID <= 0 {
+		return ""
+	}
+	oferta, err := rootservices.GetOferta(ofertaID)
+	if err != nil || oferta == nil {
+		return ""
+	}
+	return strings.TrimSpace(oferta.Titulo)
+}
+
+func enviarNotificacionDominio(terceroID int, asunto, plantilla string, datos map[string]interface{}) {
+	if err := internalhelpers.Notificaciones.Send(nil, terceroID, asunto, plantilla, datos); err != nil {
+		logs.Warn("notificación", plantilla, "al tercero", terceroID, ":", err)
+	}
+}
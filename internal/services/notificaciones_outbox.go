@@ -0,0 +1,320 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+)
+
+const (
+	OutboxColaMemoria = "memoria"
+	OutboxColaArchivo = "archivo"
+	OutboxColaNinguna = "ninguna"
+
+	defaultOutboxArchivo       = "data/notificaciones_outbox.json"
+	defaultOutboxIntervaloSeg  = 10
+	defaultOutboxMaxIntentos   = 6
+	defaultOutboxBackoffSeg    = 30
+	defaultOutboxBackoffMaxSeg = 3600
+	defaultOutboxRetencionH    = 72
+	outboxLote                 = 50
+)
+
+var (
+	outboxOnce      sync.Once
+	outboxDespertar = make(chan struct{}, 1)
+)
+
+// IniciarOutboxNotificaciones registra la cola de entregas según notificaciones_outbox
+// ("memoria", "archivo" o "ninguna") y arranca el worker que las entrega con backoff exponencial.
+// Con "ninguna" las notificaciones se entregan en línea, sin reintentos. La cola es local a la
+// instancia: "archivo" sobrevive reinicios pero no se comparte entre réplicas.
+func IniciarOutboxNotificaciones() {
+	outboxOnce.Do(func() {
+		var cola *outboxLocal
+		switch tipo := strings.ToLower(configTexto("NOTIFICACIONES_OUTBOX", "notificaciones_outbox", OutboxColaMemoria)); tipo {
+		case OutboxColaNinguna:
+			return
+		case OutboxColaArchivo:
+			ruta := configTexto("NOTIFICACIONES_OUTBOX_ARCHIVO", "notificaciones_outbox_archivo", defaultOutboxArchivo)
+			var err error
+			if cola, err = newOutboxArchivo(ruta); err != nil {
+				logs.Error("outbox de notificaciones: no se pudo abrir", ruta, ":", err, "; se usa memoria")
+				cola = newOutboxMemoria()
+			}
+		default:
+			if tipo != OutboxColaMemoria {
+				logs.Warn("notificaciones_outbox desconocido:", tipo, "; se usa memoria")
+			}
+			cola = newOutboxMemoria()
+		}
+		internalhelpers.SetNotificacionOutbox(outboxConAviso{cola})
+
+		intervalo := time.Duration(configEntero("NOTIFICACIONES_OUTBOX_INTERVALO_SEG", "notificaciones_outbox_intervalo_seg", defaultOutboxIntervaloSeg)) * time.Second
+		if intervalo <= 0 {
+			intervalo = defaultOutboxIntervaloSeg * time.Second
+		}
+		go func() {
+			ticker := time.NewTicker(intervalo)
+			defer ticker.Stop()
+			procesarOutbox(time.Now())
+			for {
+				select {
+				case now := <-ticker.C:
+					procesarOutbox(now)
+				case <-outboxDespertar:
+					procesarOutbox(time.Now())
+				}
+			}
+		}()
+	})
+}
+
+// outboxConAviso despierta al worker cuando se encola un mensaje para no esperar al intervalo.
+type outboxConAviso struct {
+	*outboxLocal
+}
+
+func (o outboxConAviso) Encolar(m *internaldto.MensajeOutbox) error {
+	if err := o.outboxLocal.Encolar(m); err != nil {
+		return err
+	}
+	despertarOutbox()
+	return nil
+}
+
+func despertarOutbox() {
+	select {
+	case outboxDespertar <- struct{}{}:
+	default:
+	}
+}
+
+// procesarOutbox entrega los mensajes vencidos. Cada fallo programa el siguiente intento con
+// backoff exponencial; al agotar notificaciones_outbox_max_intentos el mensaje pasa a MUERTA.
+func procesarOutbox(now time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("outbox de notificaciones:", r)
+		}
+	}()
+	outbox := internalhelpers.GetNotificacionOutbox()
+	if outbox == nil {
+		return
+	}
+
+	retencion := time.Duration(configEntero("NOTIFICACIONES_OUTBOX_RETENCION_H", "notificaciones_outbox_retencion_h", defaultOutboxRetencionH)) * time.Hour
+	if retencion > 0 {
+		if _, err := outbox.Purgar(now.Add(-retencion)); err != nil {
+			logs.Warn("outbox de notificaciones: purga:", err)
+		}
+	}
+
+	vencidos, err := outbox.Vencidos(now, outboxLote)
+	if err != nil {
+		logs.Warn("outbox de notificaciones:", err)
+		return
+	}
+	maxIntentos := configEntero("NOTIFICACIONES_OUTBOX_MAX_INTENTOS", "notificaciones_outbox_max_intentos", defaultOutboxMaxIntentos)
+	for _, m := range vencidos {
+		m.Intentos++
+		if err := entregarMensajeOutbox(m); err != nil {
+			m.UltimoError = err.Error()
+			if m.Intentos >= maxIntentos {
+				m.Estado = internaldto.OutboxMuerta
+				m.ProximoIntento = ""
+				logs.Error("outbox de notificaciones: mensaje", m.ID, "agotó", m.Intentos, "intentos:", err)
+			} else {
+				m.ProximoIntento = now.UTC().Add(backoffOutbox(m.Intentos)).Format(time.RFC3339)
+			}
+		} else {
+			m.Estado = internaldto.OutboxEnviada
+			m.UltimoError = ""
+			m.ProximoIntento = ""
+			m.FechaEnvio = now.UTC().Format(time.RFC3339)
+		}
+		estadoBandeja := ""
+		switch m.Estado {
+		case internaldto.OutboxEnviada:
+			estadoBandeja = internaldto.NotificacionEnvioEnviada
+		case internaldto.OutboxMuerta:
+			estadoBandeja = internaldto.NotificacionEnvioFallida
+		}
+		if estadoBandeja != "" {
+			m.Notificacion.EstadoEnvio = estadoBandeja
+		}
+		if err := outbox.Actualizar(m); err != nil {
+			logs.Warn("outbox de notificaciones: mensaje", m.ID, ":", err)
+		}
+		if estadoBandeja != "" {
+			actualizarEnvioBandeja(m.Notificacion, estadoBandeja)
+		}
+	}
+}
+
+// entregarMensajeOutbox entrega el mensaje por el canal con que se encoló. Si ese canal ya no está
+// configurado el intento falla y el mensaje sigue el camino normal de reintentos.
+func entregarMensajeOutbox(m internaldto.MensajeOutbox) error {
+	canal := internalhelpers.CanalNotificacionPorNombre(m.Canal)
+	if canal == nil {
+		return errors.New("canal de notificación no disponible: " + m.Canal)
+	}
+	return canal.Entregar(nil, m.Notificacion)
+}
+
+// backoffOutbox es la espera antes del siguiente intento: base·2^(intentos-1), con tope.
+func backoffOutbox(intentos int) time.Duration {
+	base := time.Duration(configEntero("NOTIFICACIONES_OUTBOX_BACKOFF_SEG", "notificaciones_outbox_backoff_seg", defaultOutboxBackoffSeg)) * time.Second
+	tope := time.Duration(configEntero("NOTIFICACIONES_OUTBOX_BACKOFF_MAX_SEG", "notificaciones_outbox_backoff_max_seg", defaultOutboxBackoffMaxSeg)) * time.Second
+	espera := base
+	for i := 1; i < intentos && espera < tope; i++ {
+		espera *= 2
+	}
+	if tope > 0 && espera > tope {
+		espera = tope
+	}
+	return espera
+}
+
+func actualizarEnvioBandeja(n internaldto.Notificacion, estado string) {
+	store := internalhelpers.GetNotificacionStore()
+	if store == nil || n.ID <= 0 {
+		return
+	}
+	if err := store.ActualizarEnvio(n.TerceroID, n.ID, estado); err != nil {
+		logs.Warn("bandeja de notificaciones: notificación", n.ID, ":", err)
+	}
+}
+
+// ListarOutbox retorna los mensajes del outbox, los más recientes primero; estado vacío no filtra.
+func ListarOutbox(estado string) ([]internaldto.MensajeOutbox, error) {
+	outbox := internalhelpers.GetNotificacionOutbox()
+	if outbox == nil {
+		return nil, helpers.NewAppError(http.StatusServiceUnavailable, "outbox de notificaciones desactivado", nil)
+	}
+	estado = strings.ToUpper(strings.TrimSpace(estado))
+	switch estado {
+	case "", internaldto.OutboxPendiente, internaldto.OutboxEnviada, internaldto.OutboxMuerta:
+	default:
+		return nil, helpers.NewAppError(http.StatusBadRequest, "estado inválido", nil)
+	}
+	items, err := outbox.Listar(estado)
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error consultando outbox")
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].ID > items[j].ID })
+	return items, nil
+}
+
+// ReintentarOutbox vuelve a encolar un mensaje de la cola de muertos con los intentos en cero, por
+// el canal activo.
+func ReintentarOutbox(id int64) (*internaldto.MensajeOutbox, error) {
+	outbox := internalhelpers.GetNotificacionOutbox()
+	if outbox == nil {
+		return nil, helpers.NewAppError(http.StatusServiceUnavailable, "outbox de notificaciones desactivado", nil)
+	}
+	if id <= 0 {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "id inválido", nil)
+	}
+	m, err := outbox.Obtener(id)
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error consultando outbox")
+	}
+	if m == nil {
+		return nil, helpers.NewAppError(http.StatusNotFound, "mensaje no encontrado", nil)
+	}
+	if m.Estado != internaldto.OutboxMuerta {
+		return nil, helpers.NewAppError(http.StatusConflict, "solo se reintentan mensajes en estado MUERTA", nil)
+	}
+
+	if canal := internalhelpers.CanalNotificacionActivo(); canal != nil {
+		m.Canal = canal.Nombre()
+	}
+	m.Estado = internaldto.OutboxPendiente
+	m.Intentos = 0
+	m.UltimoError = ""
+	m.ProximoIntento = time.Now().UTC().Format(time.RFC3339)
+	m.Notificacion.EstadoEnvio = internaldto.NotificacionEnvioPendiente
+	if err := outbox.Actualizar(*m); err != nil {
+		return nil, helpers.AsAppError(err, "error actualizando outbox")
+	}
+	actualizarEnvioBandeja(m.Notificacion, internaldto.NotificacionEnvioPendiente)
+	despertarOutbox()
+	return m, nil
+}
+
+// ---------------------------------------------------------------------------------------------
+// Cola local del outbox sobre colaJSON.
+
+type outboxLocal struct {
+	cola *colaJSON[internaldto.MensajeOutbox]
+}
+
+func idMensajeOutbox(m *internaldto.MensajeOutbox) *int64 { return &m.ID }
+
+func newOutboxMemoria() *outboxLocal {
+	return &outboxLocal{cola: newColaJSONMemoria(idMensajeOutbox)}
+}
+
+func newOutboxArchivo(ruta string) (*outboxLocal, error) {
+	cola, err := newColaJSONArchivo(ruta, idMensajeOutbox)
+	if err != nil {
+		return nil, err
+	}
+	return &outboxLocal{cola: cola}, nil
+}
+
+func (o *outboxLocal) Encolar(m *internaldto.MensajeOutbox) error {
+	return o.cola.Agregar(m)
+}
+
+func (o *outboxLocal) Obtener(id int64) (*internaldto.MensajeOutbox, error) {
+	m, ok := o.cola.Obtener(id)
+	if !ok {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+func (o *outboxLocal) Actualizar(m internaldto.MensajeOutbox) error {
+	ok, err := o.cola.Reemplazar(m)
+	if !ok {
+		return helpers.NewAppError(http.StatusNotFound, "mensaje no encontrado", nil)
+	}
+	return err
+}
+
+func (o *outboxLocal) Vencidos(ahora time.Time, limite int) ([]internaldto.MensajeOutbox, error) {
+	out := o.cola.Filtrar(func(m internaldto.MensajeOutbox) bool {
+		if m.Estado != internaldto.OutboxPendiente {
+			return false
+		}
+		t := parseTime(m.ProximoIntento)
+		return t.IsZero() || !t.After(ahora)
+	})
+	if limite > 0 && len(out) > limite {
+		out = out[:limite]
+	}
+	return out, nil
+}
+
+func (o *outboxLocal) Listar(estado string) ([]internaldto.MensajeOutbox, error) {
+	return o.cola.Filtrar(func(m internaldto.MensajeOutbox) bool {
+		return estado == "" || m.Estado == estado
+	}), nil
+}
+
+func (o *outboxLocal) Purgar(antesDe time.Time) (int, error) {
+	return o.cola.Eliminar(func(m internaldto.MensajeOutbox) bool {
+		return m.Estado == internaldto.OutboxEnviada && parseTime(m.FechaEnvio).Before(antesDe)
+	})
+}
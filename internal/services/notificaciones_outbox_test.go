@@ -0,0 +1,198 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/beego/beego/v2/server/web/context"
+
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+)
+
+func TestBackoffOutbox(t *testing.T) {
+	tests := []struct {
+		nombre   string
+		base     string
+		tope     string
+		intentos int
+		want     time.Duration
+	}{
+		{nombre: "primer intento usa la base", intentos: 1, want: 30 * time.Second},
+		{nombre: "se duplica por intento", intentos: 3, want: 120 * time.Second},
+		{nombre: "no supera el tope por defecto", intentos: 20, want: time.Hour},
+		{nombre: "base configurada", base: "10", intentos: 4, want: 80 * time.Second},
+		{nombre: "tope configurado", base: "10", tope: "45", intentos: 4, want: 45 * time.Second},
+		{nombre: "intentos cero usa la base", intentos: 0, want: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			t.Setenv("NOTIFICACIONES_OUTBOX_BACKOFF_SEG", tt.base)
+			t.Setenv("NOTIFICACIONES_OUTBOX_BACKOFF_MAX_SEG", tt.tope)
+			if got := backoffOutbox(tt.intentos); got != tt.want {
+				t.Errorf("backoffOutbox(%d) = %s, se esperaba %s", tt.intentos, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcesarOutbox(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		nombre       string
+		canal        string
+		intentos     int
+		falla        bool
+		wantEstado   string
+		wantIntentos int
+		wantProximo  string
+		wantBandeja  string
+	}{
+		{
+			nombre: "entrega exitosa", canal: canalOutboxFalso, falla: false,
+			wantEstado: internaldto.OutboxEnviada, wantIntentos: 1, wantBandeja: internaldto.NotificacionEnvioEnviada,
+		},
+		{
+			nombre: "fallo programa reintento con backoff", canal: canalOutboxFalso, intentos: 1, falla: true,
+			wantEstado: internaldto.OutboxPendiente, wantIntentos: 2, wantProximo: "2026-03-10T15:01:00Z",
+		},
+		{
+			nombre: "último intento pasa a muerta", canal: canalOutboxFalso, intentos: 2, falla: true,
+			wantEstado: internaldto.OutboxMuerta, wantIntentos: 3, wantBandeja: internaldto.NotificacionEnvioFallida,
+		},
+		{
+			nombre: "canal no disponible cuenta como fallo", canal: internaldto.OutboxCanalSMTP, intentos: 2,
+			wantEstado: internaldto.OutboxMuerta, wantIntentos: 3, wantBandeja: internaldto.NotificacionEnvioFallida,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			t.Setenv("NOTIFICACIONES_OUTBOX_MAX_INTENTOS", "3")
+			t.Setenv("NOTIFICACIONES_OUTBOX_BACKOFF_SEG", "30")
+			t.Setenv("NOTIFICACIONES_OUTBOX_BACKOFF_MAX_SEG", "")
+			t.Setenv("NOTIFICACIONES_OUTBOX_RETENCION_H", "0")
+
+			outbox := newOutboxMemoria()
+			store := &bandejaFalsa{}
+			canal := &canalFalso{falla: tt.falla}
+			registrarNotificacionesPrueba(t, outbox, store, canal)
+
+			m := internaldto.MensajeOutbox{
+				Canal:          tt.canal,
+				Estado:         internaldto.OutboxPendiente,
+				Intentos:       tt.intentos,
+				ProximoIntento: now.Add(-time.Minute).Format(time.RFC3339),
+				Notificacion:   internaldto.Notificacion{ID: 9, TerceroID: 7, Asunto: "Aviso"},
+			}
+			if err := outbox.Encolar(&m); err != nil {
+				t.Fatal(err)
+			}
+
+			procesarOutbox(now)
+
+			got, err := outbox.Obtener(m.ID)
+			if err != nil || got == nil {
+				t.Fatalf("mensaje %d: %v", m.ID, err)
+			}
+			if got.Estado != tt.wantEstado || got.Intentos != tt.wantIntentos {
+				t.Errorf("estado = %s, intentos = %d; se esperaba %s, %d", got.Estado, got.Intentos, tt.wantEstado, tt.wantIntentos)
+			}
+			if got.ProximoIntento != tt.wantProximo {
+				t.Errorf("próximo intento = %q, se esperaba %q", got.ProximoIntento, tt.wantProximo)
+			}
+			if tt.falla || tt.canal != canalOutboxFalso {
+				if got.UltimoError == "" {
+					t.Error("el fallo no quedó registrado")
+				}
+			} else if got.FechaEnvio != now.Format(time.RFC3339) || got.UltimoError != "" {
+				t.Errorf("fecha de envío = %q, último error = %q", got.FechaEnvio, got.UltimoError)
+			}
+			if got.Notificacion.EstadoEnvio != tt.wantBandeja {
+				t.Errorf("estado de envío del mensaje = %q, se esperaba %q", got.Notificacion.EstadoEnvio, tt.wantBandeja)
+			}
+			if store.estado != tt.wantBandeja {
+				t.Errorf("estado de envío en la bandeja = %q, se esperaba %q", store.estado, tt.wantBandeja)
+			}
+		})
+	}
+}
+
+func TestProcesarOutboxSoloVencidosYPurga(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	t.Setenv("NOTIFICACIONES_OUTBOX_RETENCION_H", "24")
+
+	outbox := newOutboxMemoria()
+	canal := &canalFalso{}
+	registrarNotificacionesPrueba(t, outbox, &bandejaFalsa{}, canal)
+
+	mensajes := []internaldto.MensajeOutbox{
+		{Canal: canalOutboxFalso, Estado: internaldto.OutboxPendiente, ProximoIntento: now.Add(time.Minute).Format(time.RFC3339)},
+		{Canal: canalOutboxFalso, Estado: internaldto.OutboxMuerta},
+		{Canal: canalOutboxFalso, Estado: internaldto.OutboxEnviada, FechaEnvio: now.Add(-25 * time.Hour).Format(time.RFC3339)},
+		{Canal: canalOutboxFalso, Estado: internaldto.OutboxEnviada, FechaEnvio: now.Add(-time.Hour).Format(time.RFC3339)},
+	}
+	for i := range mensajes {
+		if err := outbox.Encolar(&mensajes[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	procesarOutbox(now)
+
+	if canal.entregas != 0 {
+		t.Errorf("%d entregas, no había mensajes vencidos", canal.entregas)
+	}
+	restantes, _ := outbox.Listar("")
+	if len(restantes) != 3 {
+		t.Fatalf("quedan %d mensajes, se esperaban 3", len(restantes))
+	}
+	if m, _ := outbox.Obtener(mensajes[2].ID); m != nil {
+		t.Error("no se purgó el enviado fuera de la retención")
+	}
+}
+
+const canalOutboxFalso = "FALSO"
+
+type canalFalso struct {
+	mu       sync.Mutex
+	falla    bool
+	entregas int
+}
+
+func (c *canalFalso) Nombre() string { return canalOutboxFalso }
+
+func (c *canalFalso) Entregar(_ *context.Context, _ internaldto.Notificacion) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entregas++
+	if c.falla {
+		return errors.New("canal caído")
+	}
+	return nil
+}
+
+// bandejaFalsa solo registra el último estado de envío; el resto de la bandeja no se usa aquí.
+type bandejaFalsa struct {
+	internalhelpers.NotificacionStore
+	estado string
+}
+
+func (b *bandejaFalsa) ActualizarEnvio(_, _ int, estado string) error {
+	b.estado = estado
+	return nil
+}
+
+func registrarNotificacionesPrueba(t *testing.T, outbox internalhelpers.NotificacionOutbox, store internalhelpers.NotificacionStore, canal internalhelpers.CanalNotificacion) {
+	t.Helper()
+	internalhelpers.SetNotificacionOutbox(outbox)
+	internalhelpers.SetNotificacionStore(store)
+	internalhelpers.SetCanalNotificacion(canal)
+	t.Cleanup(func() {
+		internalhelpers.SetNotificacionOutbox(nil)
+		internalhelpers.SetNotificacionStore(nil)
+		internalhelpers.SetCanalNotificacion(nil)
+	})
+}
@@ -0,0 +1,540 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	preferenciaNotificacionResource = "preferencia_notificacion"
+
+	ResumenesColaMemoria = "memoria"
+	ResumenesColaArchivo = "archivo"
+
+	defaultResumenesArchivo      = "data/notificaciones_resumenes.json"
+	defaultResumenesIntervaloMin = 15
+	defaultResumenHora           = 7
+	defaultResumenDiaSemana      = int(time.Monday)
+	preferenciasTTL              = 5 * time.Minute
+
+	// maxItemsResumen limita las notificaciones listadas en cada resumen; el resto solo se cuenta.
+	maxItemsResumen = 50
+)
+
+// tiposNotificacion son las plantillas que el tercero puede configurar en sus preferencias.
+var tiposNotificacion = []string{
+	internalhelpers.PlantillaInvitacionRecibida,
+	internalhelpers.PlantillaPostulacionEstado,
+	internalhelpers.PlantillaPostulacionSeleccionada,
+	internalhelpers.PlantillaEntrevistaProgramada,
+	internalhelpers.PlantillaOfertaCancelada,
+	plantillaEntrevista,
+	plantillaRecordatorioEntrevista,
+	plantillaBusquedaAlerta,
+	plantillaBusquedaResumen,
+}
+
+var (
+	preferenciasOnce sync.Once
+	preferencias     *preferenciasNotificacion
+)
+
+// IniciarPreferenciasNotificaciones registra las preferencias por tercero y arranca el armado de
+// resúmenes. Las preferencias se guardan donde indica notificaciones_store; las notificaciones
+// que esperan un resumen, en la cola de notificaciones_resumenes: "archivo" (por defecto,
+// sobrevive reinicios) o "memoria". Los resúmenes diarios salen a partir de
+// notificaciones_resumen_hora y los semanales ese mismo día de notificaciones_resumen_dia
+// (0 domingo … 6 sábado).
+func IniciarPreferenciasNotificaciones() {
+	preferenciasOnce.Do(func() {
+		p := &preferenciasNotificacion{cache: map[int]entradaPreferencias{}}
+		switch strings.ToLower(configTexto("NOTIFICACIONES_STORE", "notificaciones_store", NotificacionStoreCRUD)) {
+		case NotificacionStoreMemoria:
+			p.store = &preferenciasStoreMemoria{items: map[int]internaldto.PreferenciasNotificacion{}}
+		default:
+			p.store = preferenciasStoreCRUD{}
+		}
+
+		switch tipo := strings.ToLower(configTexto("NOTIFICACIONES_RESUMENES", "notificaciones_resumenes", ResumenesColaArchivo)); tipo {
+		case ResumenesColaMemoria:
+			p.cola = newResumenesMemoria()
+		default:
+			if tipo != ResumenesColaArchivo {
+				logs.Warn("notificaciones_resumenes desconocido:", tipo, "; se usa archivo")
+			}
+			ruta := configTexto("NOTIFICACIONES_RESUMENES_ARCHIVO", "notificaciones_resumenes_archivo", defaultResumenesArchivo)
+			cola, err := newResumenesArchivo(ruta)
+			if err != nil {
+				logs.Error("resúmenes de notificaciones: no se pudo abrir", ruta, ":", err, "; se usa memoria y los resúmenes pendientes se pierden al reiniciar")
+				cola = newResumenesMemoria()
+			}
+			p.cola = cola
+		}
+		preferencias = p
+		internalhelpers.SetNotificacionPreferencias(p)
+
+		intervalo := configMinutos("NOTIFICACIONES_RESUMENES_INTERVALO_MIN", "notificaciones_resumenes_intervalo_min", defaultResumenesIntervaloMin)
+		if intervalo <= 0 {
+			intervalo = defaultResumenesIntervaloMin * time.Minute
+		}
+		hora := configEntero("NOTIFICACIONES_RESUMEN_HORA", "notificaciones_resumen_hora", defaultResumenHora)
+		if hora < 0 || hora > 23 {
+			hora = defaultResumenHora
+		}
+		dia := configEntero("NOTIFICACIONES_RESUMEN_DIA", "notificaciones_resumen_dia", defaultResumenDiaSemana)
+		if dia < 0 || dia > 6 {
+			dia = defaultResumenDiaSemana
+		}
+		go func() {
+			ticker := time.NewTicker(intervalo)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				procesarResumenes(now, hora, time.Weekday(dia))
+			}
+		}()
+	})
+}
+
+// ObtenerPreferenciasNotificacion retorna las preferencias efectivas del tercero para cada tipo.
+func ObtenerPreferenciasNotificacion(terceroID int) (internaldto.PreferenciasNotificacion, error) {
+	p, err := preferenciasActivas(terceroID)
+	if err != nil {
+		return internaldto.PreferenciasNotificacion{}, err
+	}
+	prefs, err := p.obtener(terceroID)
+	if err != nil {
+		return internaldto.PreferenciasNotificacion{}, err
+	}
+	return vistaPreferencias(prefs), nil
+}
+
+// ActualizarPreferenciasNotificacion reemplaza las preferencias del tercero. Las notificaciones
+// que ya esperan un resumen salen con él aunque el tipo pase a INSTANTANEA.
+func ActualizarPreferenciasNotificacion(terceroID int, req internaldto.PreferenciasNotificacionUpdate) (internaldto.PreferenciasNotificacion, error) {
+	p, err := preferenciasActivas(terceroID)
+	if err != nil {
+		return internaldto.PreferenciasNotificacion{}, err
+	}
+
+	prefs := internaldto.PreferenciasNotificacion{
+		TerceroID:          terceroID,
+		PorDefecto:         internaldto.PreferenciaCanales{App: internaldto.FrecuenciaInstantanea, Email: internaldto.FrecuenciaInstantanea},
+		Tipos:              map[string]internaldto.PreferenciaCanales{},
+		FechaActualizacion: nowISO(),
+	}
+	if req.PorDefecto != nil {
+		canales, err := normalizarCanales(*req.PorDefecto, "por_defecto")
+		if err != nil {
+			return internaldto.PreferenciasNotificacion{}, err
+		}
+		if canales.App != "" {
+			prefs.PorDefecto.App = canales.App
+		}
+		if canales.Email != "" {
+			prefs.PorDefecto.Email = canales.Email
+		}
+	}
+	for tipo, c := range req.Tipos {
+		tipo = strings.TrimSpace(tipo)
+		if !tipoNotificacionValido(tipo) {
+			return internaldto.PreferenciasNotificacion{}, helpers.NewAppError(http.StatusBadRequest, "tipo de notificación desconocido: "+tipo, nil)
+		}
+		canales, err := normalizarCanales(c, tipo)
+		if err != nil {
+			return internaldto.PreferenciasNotificacion{}, err
+		}
+		if canales.App != "" || canales.Email != "" {
+			prefs.Tipos[tipo] = canales
+		}
+	}
+
+	if err := p.store.Guardar(prefs); err != nil {
+		return internaldto.PreferenciasNotificacion{}, helpers.AsAppError(err, "error guardando preferencias")
+	}
+	p.invalidar(terceroID)
+	return vistaPreferencias(prefs), nil
+}
+
+func preferenciasActivas(terceroID int) (*preferenciasNotificacion, error) {
+	if terceroID <= 0 {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "tercero_id inválido", nil)
+	}
+	if preferencias == nil {
+		return nil, helpers.NewAppError(http.StatusServiceUnavailable, "preferencias de notificación no disponibles", nil)
+	}
+	return preferencias, nil
+}
+
+// vistaPreferencias completa cada tipo configurable con su valor efectivo.
+func vistaPreferencias(prefs internaldto.PreferenciasNotificacion) internaldto.PreferenciasNotificacion {
+	out := prefs
+	out.Tipos = make(map[string]internaldto.PreferenciaCanales, len(tiposNotificacion))
+	for _, tipo := range tiposNotificacion {
+		out.Tipos[tipo] = canalesEfectivos(prefs, tipo)
+	}
+	return out
+}
+
+// canalesEfectivos aplica al tipo los valores por defecto en los canales que no define.
+func canalesEfectivos(prefs internaldto.PreferenciasNotificacion, tipo string) internaldto.PreferenciaCanales {
+	out := prefs.PorDefecto
+	if c, ok := prefs.Tipos[tipo]; ok {
+		if c.App != "" {
+			out.App = c.App
+		}
+		if c.Email != "" {
+			out.Email = c.Email
+		}
+	}
+	if out.App == "" {
+		out.App = internaldto.FrecuenciaInstantanea
+	}
+	if out.Email == "" {
+		out.Email = internaldto.FrecuenciaInstantanea
+	}
+	return out
+}
+
+func normalizarCanales(c internaldto.PreferenciaCanales, campo string) (internaldto.PreferenciaCanales, error) {
+	var out internaldto.PreferenciaCanales
+	var ok bool
+	if out.App, ok = normalizarFrecuencia(c.App); !ok {
+		return out, helpers.NewAppError(http.StatusBadRequest, "frecuencia inválida en "+campo+".app: "+c.App, nil)
+	}
+	if out.Email, ok = normalizarFrecuencia(c.Email); !ok {
+		return out, helpers.NewAppError(http.StatusBadRequest, "frecuencia inválida en "+campo+".email: "+c.Email, nil)
+	}
+	return out, nil
+}
+
+func normalizarFrecuencia(raw string) (string, bool) {
+	f := strings.ToUpper(strings.TrimSpace(raw))
+	switch f {
+	case "", internaldto.FrecuenciaInstantanea, internaldto.FrecuenciaDiaria, internaldto.FrecuenciaSemanal, internaldto.FrecuenciaDesactivada:
+		return f, true
+	}
+	return "", false
+}
+
+func tipoNotificacionValido(tipo string) bool {
+	for _, t := range tiposNotificacion {
+		if t == tipo {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------------------------
+// Resolución de preferencias para Send (implementa internalhelpers.NotificacionPreferencias)
+
+type preferenciasNotificacion struct {
+	store preferenciasStore
+	cola  *resumenesLocal
+
+	mu    sync.Mutex
+	cache map[int]entradaPreferencias
+}
+
+type entradaPreferencias struct {
+	prefs     internaldto.PreferenciasNotificacion
+	expiresAt time.Time
+}
+
+// Resolver retorna las frecuencias del tipo para el tercero. Si las preferencias no se pueden
+// consultar se entrega al instante: es preferible notificar de más que perder un aviso.
+func (p *preferenciasNotificacion) Resolver(terceroID int, tipo string) internaldto.PreferenciaCanales {
+	prefs, err := p.obtener(terceroID)
+	if err != nil {
+		logs.Warn("preferencias de notificación, tercero", terceroID, ":", err)
+		return internaldto.PreferenciaCanales{App: internaldto.FrecuenciaInstantanea, Email: internaldto.FrecuenciaInstantanea}
+	}
+	return canalesEfectivos(prefs, tipo)
+}
+
+func (p *preferenciasNotificacion) Diferir(d *internaldto.NotificacionDiferida) error {
+	return p.cola.Agregar(d)
+}
+
+func (p *preferenciasNotificacion) obtener(terceroID int) (internaldto.PreferenciasNotificacion, error) {
+	p.mu.Lock()
+	if e, ok := p.cache[terceroID]; ok && time.Now().Before(e.expiresAt) {
+		p.mu.Unlock()
+		return e.prefs, nil
+	}
+	p.mu.Unlock()
+
+	prefs, encontradas, err := p.store.Obtener(terceroID)
+	if err != nil {
+		return internaldto.PreferenciasNotificacion{}, err
+	}
+	if !encontradas {
+		prefs = internaldto.PreferenciasNotificacion{
+			TerceroID:  terceroID,
+			PorDefecto: internaldto.PreferenciaCanales{App: internaldto.FrecuenciaInstantanea, Email: internaldto.FrecuenciaInstantanea},
+			Tipos:      map[string]internaldto.PreferenciaCanales{},
+		}
+	}
+
+	p.mu.Lock()
+	p.cache[terceroID] = entradaPreferencias{prefs: prefs, expiresAt: time.Now().Add(preferenciasTTL)}
+	p.mu.Unlock()
+	return prefs, nil
+}
+
+func (p *preferenciasNotificacion) invalidar(terceroID int) {
+	p.mu.Lock()
+	delete(p.cache, terceroID)
+	p.mu.Unlock()
+}
+
+// ---------------------------------------------------------------------------------------------
+// Armado de resúmenes
+
+type claveResumen struct {
+	terceroID  int
+	canal      string
+	frecuencia string
+}
+
+// procesarResumenes agrupa las notificaciones diferidas por tercero, canal y frecuencia y envía
+// un solo resumen por grupo cuando llega su hora: el primer notificaciones_resumen_hora posterior
+// a la más antigua del grupo (y, si es semanal, el día configurado). Un grupo que no se pudo
+// entregar se intenta en la siguiente pasada.
+func procesarResumenes(now time.Time, hora int, dia time.Weekday) {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("resúmenes de notificaciones:", r)
+		}
+	}()
+	if preferencias == nil {
+		return
+	}
+
+	grupos := map[claveResumen][]internaldto.NotificacionDiferida{}
+	for _, d := range preferencias.cola.Listar() {
+		k := claveResumen{terceroID: d.Notificacion.TerceroID, canal: d.Canal, frecuencia: d.Frecuencia}
+		grupos[k] = append(grupos[k], d)
+	}
+	for k, items := range grupos {
+		sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+		desde := parseTime(items[0].Notificacion.FechaCreacion)
+		if now.Before(proximoResumen(k.frecuencia, desde, hora, dia)) {
+			continue
+		}
+		if err := enviarResumen(k, items); err != nil {
+			logs.Warn("resumen de notificaciones, tercero", k.terceroID, "canal", k.canal, ":", err)
+			continue
+		}
+		ids := make([]int64, 0, len(items))
+		for _, d := range items {
+			ids = append(ids, d.ID)
+		}
+		if err := preferencias.cola.Eliminar(ids); err != nil {
+			logs.Warn("resumen de notificaciones, tercero", k.terceroID, ":", err)
+		}
+	}
+}
+
+// proximoResumen es el primer envío programado después de desde.
+func proximoResumen(frecuencia string, desde time.Time, hora int, dia time.Weekday) time.Time {
+	d := desde.Local()
+	t := time.Date(d.Year(), d.Month(), d.Day(), hora, 0, 0, 0, time.Local)
+	if !t.After(d) {
+		t = t.AddDate(0, 0, 1)
+	}
+	if frecuencia == internaldto.FrecuenciaSemanal {
+		for t.Weekday() != dia {
+			t = t.AddDate(0, 0, 1)
+		}
+	}
+	return t
+}
+
+func enviarResumen(k claveResumen, diferidas []internaldto.NotificacionDiferida) error {
+	items := make([]map[string]interface{}, 0, maxItemsResumen)
+	for _, d := range diferidas {
+		if len(items) == maxItemsResumen {
+			break
+		}
+		items = append(items, map[string]interface{}{
+			"asunto":    d.Notificacion.Asunto,
+			"plantilla": d.Notificacion.Plantilla,
+			"fecha":     d.Notificacion.FechaCreacion,
+			"datos":     d.Notificacion.Datos,
+		})
+	}
+	data := map[string]interface{}{
+		"frecuencia": k.frecuencia,
+		"total":      len(diferidas),
+		"items":      items,
+		"omitidas":   len(diferidas) - len(items),
+	}
+	if locale := diferidas[0].Notificacion.Locale; locale != "" {
+		data["locale"] = locale
+	}
+	asunto := "Resumen diario de notificaciones"
+	if k.frecuencia == internaldto.FrecuenciaSemanal {
+		asunto = "Resumen semanal de notificaciones"
+	}
+	return internalhelpers.Notificaciones.EnviarResumen(k.terceroID, k.canal, asunto, data)
+}
+
+// ---------------------------------------------------------------------------------------------
+// Almacenamiento de preferencias
+
+type preferenciasStore interface {
+	Obtener(terceroID int) (internaldto.PreferenciasNotificacion, bool, error)
+	Guardar(p internaldto.PreferenciasNotificacion) error
+}
+
+// preferenciasStoreCRUD guarda un registro por tercero en el recurso preferencia_notificacion;
+// Preferencias es el JSON con por_defecto y tipos.
+type preferenciasStoreCRUD struct{}
+
+func (s preferenciasStoreCRUD) Obtener(terceroID int) (internaldto.PreferenciasNotificacion, bool, error) {
+	id, prefs, err := s.buscar(terceroID)
+	return prefs, id > 0, err
+}
+
+func (s preferenciasStoreCRUD) Guardar(p internaldto.PreferenciasNotificacion) error {
+	id, _, err := s.buscar(p.TerceroID)
+	if err != nil {
+		return err
+	}
+	contenido, err := json.Marshal(struct {
+		PorDefecto internaldto.PreferenciaCanales            `json:"por_defecto"`
+		Tipos      map[string]internaldto.PreferenciaCanales `json:"tipos"`
+	}{p.PorDefecto, p.Tipos})
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"TerceroId":          p.TerceroID,
+		"Preferencias":       string(contenido),
+		"FechaActualizacion": p.FechaActualizacion,
+	}
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, preferenciaNotificacionResource)
+	method := "POST"
+	if id > 0 {
+		endpoint = rootservices.BuildURL(cfg.CastorCRUDBaseURL, preferenciaNotificacionResource, strconv.Itoa(id))
+		method = "PUT"
+		body["Id"] = id
+	}
+	var out map[string]interface{}
+	if err := helpers.DoJSON(method, endpoint, body, &out, cfg.RequestTimeout); err != nil {
+		return helpers.AsAppError(err, "error guardando preferencias")
+	}
+	return nil
+}
+
+func (preferenciasStoreCRUD) buscar(terceroID int) (int, internaldto.PreferenciasNotificacion, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, preferenciaNotificacionResource)
+	values := url.Values{}
+	values.Set("limit", "1")
+	values.Set("query", "TerceroId:"+strconv.Itoa(terceroID))
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return 0, internaldto.PreferenciasNotificacion{}, nil
+		}
+		return 0, internaldto.PreferenciasNotificacion{}, helpers.AsAppError(err, "error consultando preferencias")
+	}
+	for _, r := range raw {
+		id, _ := normalizeToInt(r["Id"])
+		tercero, _ := normalizeToInt(r["TerceroId"])
+		if id <= 0 || tercero != terceroID {
+			continue
+		}
+		prefs := internaldto.PreferenciasNotificacion{
+			TerceroID:          terceroID,
+			Tipos:              map[string]internaldto.PreferenciaCanales{},
+			FechaActualizacion: strings.TrimSpace(normalizeToString(r["FechaActualizacion"])),
+		}
+		if s := strings.TrimSpace(normalizeToString(r["Preferencias"])); s != "" {
+			if err := json.Unmarshal([]byte(s), &prefs); err != nil {
+				logs.Warn("preferencias de notificación", id, "ilegibles:", err)
+			}
+			prefs.TerceroID = terceroID
+		}
+		return id, prefs, nil
+	}
+	return 0, internaldto.PreferenciasNotificacion{}, nil
+}
+
+type preferenciasStoreMemoria struct {
+	mu    sync.Mutex
+	items map[int]internaldto.PreferenciasNotificacion
+}
+
+func (s *preferenciasStoreMemoria) Obtener(terceroID int) (internaldto.PreferenciasNotificacion, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.items[terceroID]
+	return p, ok, nil
+}
+
+func (s *preferenciasStoreMemoria) Guardar(p internaldto.PreferenciasNotificacion) error {
+	s.mu.Lock()
+	s.items[p.TerceroID] = p
+	s.mu.Unlock()
+	return nil
+}
+
+// ---------------------------------------------------------------------------------------------
+// Cola local de notificaciones diferidas sobre colaJSON.
+
+type resumenesLocal struct {
+	cola *colaJSON[internaldto.NotificacionDiferida]
+}
+
+func idNotificacionDiferida(d *internaldto.NotificacionDiferida) *int64 { return &d.ID }
+
+func newResumenesMemoria() *resumenesLocal {
+	return &resumenesLocal{cola: newColaJSONMemoria(idNotificacionDiferida)}
+}
+
+func newResumenesArchivo(ruta string) (*resumenesLocal, error) {
+	cola, err := newColaJSONArchivo(ruta, idNotificacionDiferida)
+	if err != nil {
+		return nil, err
+	}
+	return &resumenesLocal{cola: cola}, nil
+}
+
+func (r *resumenesLocal) Agregar(d *internaldto.NotificacionDiferida) error {
+	return r.cola.Agregar(d)
+}
+
+func (r *resumenesLocal) Listar() []internaldto.NotificacionDiferida {
+	return r.cola.Filtrar(nil)
+}
+
+func (r *resumenesLocal) Eliminar(ids []int64) error {
+	borrar := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		borrar[id] = struct{}{}
+	}
+	_, err := r.cola.Eliminar(func(d internaldto.NotificacionDiferida) bool {
+		_, ok := borrar[d.ID]
+		return ok
+	})
+	return err
+}
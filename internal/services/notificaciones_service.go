@@ -0,0 +1,411 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	notificacionResource = "notificacion"
+
+	NotificacionStoreCRUD    = "crud"
+	NotificacionStoreMemoria = "memoria"
+)
+
+// IniciarBandejaNotificaciones registra el almacenamiento de la bandeja según
+// notificaciones_store: "crud" (por defecto) o "memoria", útil en desarrollo porque se pierde
+// al reiniciar.
+func IniciarBandejaNotificaciones() {
+	tipo := configTexto("NOTIFICACIONES_STORE", "notificaciones_store", NotificacionStoreCRUD)
+	switch strings.ToLower(tipo) {
+	case NotificacionStoreMemoria:
+		internalhelpers.SetNotificacionStore(newNotificacionStoreMemoria())
+	case NotificacionStoreCRUD:
+		internalhelpers.SetNotificacionStore(notificacionStoreCRUD{})
+	default:
+		logs.Warn("notificaciones_store desconocido:", tipo, "; se usa el CRUD")
+		internalhelpers.SetNotificacionStore(notificacionStoreCRUD{})
+	}
+}
+
+// ListarNotificaciones retorna la bandeja del tercero, de la más reciente a la más antigua.
+func ListarNotificaciones(terceroID int, soloNoLeidas bool, pageStr, sizeStr string) (internaldto.PageDTO[internaldto.Notificacion], error) {
+	page, size := internalhelpers.ParsePageSize(pageStr, sizeStr)
+	store, err := bandejaNotificaciones(terceroID)
+	if err != nil {
+		return internaldto.PageDTO[internaldto.Notificacion]{}, err
+	}
+	items, total, err := store.Listar(terceroID, soloNoLeidas, page, size)
+	if err != nil {
+		return internaldto.PageDTO[internaldto.Notificacion]{}, err
+	}
+	if items == nil {
+		items = []internaldto.Notificacion{}
+	}
+	return internaldto.PageDTO[internaldto.Notificacion]{Items: items, Page: page, Size: size, Total: total}, nil
+}
+
+// ContarNotificacionesNoLeidas retorna el contador de no leídas del tercero.
+func ContarNotificacionesNoLeidas(terceroID int) (internaldto.NotificacionesNoLeidas, error) {
+	store, err := bandejaNotificaciones(terceroID)
+	if err != nil {
+		return internaldto.NotificacionesNoLeidas{}, err
+	}
+	total, err := store.ContarNoLeidas(terceroID)
+	if err != nil {
+		return internaldto.NotificacionesNoLeidas{}, err
+	}
+	return internaldto.NotificacionesNoLeidas{NoLeidas: total}, nil
+}
+
+// MarcarNotificacionLeida marca una notificación del tercero como leída (idempotente).
+func MarcarNotificacionLeida(terceroID, id int) (*internaldto.Notificacion, error) {
+	if id <= 0 {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "id inválido", nil)
+	}
+	store, err := bandejaNotificaciones(terceroID)
+	if err != nil {
+		return nil, err
+	}
+	return store.MarcarLeida(terceroID, id)
+}
+
+// MarcarTodasNotificacionesLeidas marca como leídas todas las notificaciones del tercero.
+func MarcarTodasNotificacionesLeidas(terceroID int) (internaldto.NotificacionesMarcadas, error) {
+	store, err := bandejaNotificaciones(terceroID)
+	if err != nil {
+		return internaldto.NotificacionesMarcadas{}, err
+	}
+	n, err := store.MarcarTodasLeidas(terceroID)
+	if err != nil {
+		return internaldto.NotificacionesMarcadas{}, err
+	}
+	return internaldto.NotificacionesMarcadas{Actualizadas: n}, nil
+}
+
+func bandejaNotificaciones(terceroID int) (internalhelpers.NotificacionStore, error) {
+	if terceroID <= 0 {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "tercero_id inválido", nil)
+	}
+	store := internalhelpers.GetNotificacionStore()
+	if store == nil {
+		return nil, helpers.NewAppError(http.StatusServiceUnavailable, "bandeja de notificaciones no disponible", nil)
+	}
+	return store, nil
+}
+
+func paginarNotificaciones(items []internaldto.Notificacion, page, size int) []internaldto.Notificacion {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].FechaCreacion != items[j].FechaCreacion {
+			return parseTime(items[i].FechaCreacion).After(parseTime(items[j].FechaCreacion))
+		}
+		return items[i].ID > items[j].ID
+	})
+	start := (page - 1) * size
+	if start >= len(items) {
+		return []internaldto.Notificacion{}
+	}
+	end := start + size
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
+
+// ---------------------------------------------------------------------------------------------
+// Almacenamiento en el CRUD (recurso notificacion)
+
+type notificacionStoreCRUD struct{}
+
+func (notificacionStoreCRUD) Crear(n *internaldto.Notificacion) error {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, notificacionResource)
+	var created map[string]interface{}
+	if err := helpers.DoJSON("POST", endpoint, notificacionToCRUD(*n), &created, cfg.RequestTimeout); err != nil {
+		return helpers.AsAppError(err, "error guardando notificación")
+	}
+	n.ID, _ = normalizeToInt(created["Id"])
+	return nil
+}
+
+func (s notificacionStoreCRUD) Listar(terceroID int, soloNoLeidas bool, page, size int) ([]internaldto.Notificacion, int64, error) {
+	items, err := s.listar(terceroID, soloNoLeidas)
+	if err != nil {
+		return nil, 0, err
+	}
+	return paginarNotificaciones(items, page, size), int64(len(items)), nil
+}
+
+func (s notificacionStoreCRUD) ContarNoLeidas(terceroID int) (int64, error) {
+	items, err := s.listar(terceroID, true)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(items)), nil
+}
+
+func (s notificacionStoreCRUD) MarcarLeida(terceroID, id int) (*internaldto.Notificacion, error) {
+	n, err := getNotificacionCRUD(id)
+	if err != nil {
+		return nil, err
+	}
+	if n.ID <= 0 || n.TerceroID != terceroID {
+		return nil, helpers.NewAppError(http.StatusNotFound, "notificación no encontrada", nil)
+	}
+	if n.Leida {
+		return &n, nil
+	}
+	if err := s.marcar(&n); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (s notificacionStoreCRUD) MarcarTodasLeidas(terceroID int) (int, error) {
+	items, err := s.listar(terceroID, true)
+	if err != nil {
+		return 0, err
+	}
+	marcadas := 0
+	for i := range items {
+		if err := s.marcar(&items[i]); err != nil {
+			return marcadas, err
+		}
+		marcadas++
+	}
+	return marcadas, nil
+}
+
+func (s notificacionStoreCRUD) ActualizarEnvio(terceroID, id int, estado string) error {
+	n, err := getNotificacionCRUD(id)
+	if err != nil {
+		return err
+	}
+	if n.ID <= 0 || n.TerceroID != terceroID {
+		return helpers.NewAppError(http.StatusNotFound, "notificación no encontrada", nil)
+	}
+	return s.actualizarCampos(id, map[string]interface{}{"EstadoEnvio": estado})
+}
+
+func getNotificacionCRUD(id int) (internaldto.Notificacion, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, notificacionResource, strconv.Itoa(id))
+	var raw map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint, nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return internaldto.Notificacion{}, helpers.NewAppError(http.StatusNotFound, "notificación no encontrada", nil)
+		}
+		return internaldto.Notificacion{}, helpers.AsAppError(err, "error consultando notificación")
+	}
+	return notificacionFromCRUD(raw), nil
+}
+
+func (notificacionStoreCRUD) listar(terceroID int, soloNoLeidas bool) ([]internaldto.Notificacion, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, notificacionResource)
+	query := "TerceroId:" + strconv.Itoa(terceroID)
+	if soloNoLeidas {
+		query += ",Leida:false"
+	}
+	values := url.Values{}
+	values.Set("limit", "0")
+	values.Set("query", query)
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando notificaciones")
+	}
+	out := make([]internaldto.Notificacion, 0, len(raw))
+	for _, r := range raw {
+		n := notificacionFromCRUD(r)
+		if n.ID <= 0 || n.TerceroID != terceroID || (soloNoLeidas && n.Leida) {
+			continue
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func (s notificacionStoreCRUD) marcar(n *internaldto.Notificacion) error {
+	n.Leida = true
+	n.FechaLectura = nowISO()
+	return s.actualizarCampos(n.ID, map[string]interface{}{"Leida": true, "FechaLectura": n.FechaLectura})
+}
+
+// actualizarCampos envía solo los campos que cambian: la lectura (request del usuario) y el estado
+// de envío (worker del outbox) se escriben en paralelo y un PUT completo pisaría al otro.
+func (notificacionStoreCRUD) actualizarCampos(id int, campos map[string]interface{}) error {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, notificacionResource, strconv.Itoa(id))
+	body := map[string]interface{}{"Id": id}
+	for k, v := range campos {
+		body[k] = v
+	}
+	var out map[string]interface{}
+	if err := helpers.DoJSON("PUT", endpoint, body, &out, cfg.RequestTimeout); err != nil {
+		return helpers.AsAppError(err, "error actualizando notificación")
+	}
+	return nil
+}
+
+func notificacionFromCRUD(raw map[string]interface{}) internaldto.Notificacion {
+	var n internaldto.Notificacion
+	if len(raw) == 0 {
+		return n
+	}
+	n.ID, _ = normalizeToInt(raw["Id"])
+	n.TerceroID, _ = normalizeToInt(raw["TerceroId"])
+	n.Asunto = strings.TrimSpace(normalizeToString(raw["Asunto"]))
+	n.Plantilla = strings.TrimSpace(normalizeToString(raw["Plantilla"]))
+	n.Cuerpo = normalizeToString(raw["Cuerpo"])
+	n.CuerpoHTML = normalizeToString(raw["CuerpoHtml"])
+	n.Locale = strings.TrimSpace(normalizeToString(raw["Locale"]))
+	n.Leida = normalizeToBool(raw["Leida"], false)
+	n.EstadoEnvio = strings.TrimSpace(normalizeToString(raw["EstadoEnvio"]))
+	n.FechaCreacion = strings.TrimSpace(normalizeToString(raw["FechaCreacion"]))
+	n.FechaLectura = strings.TrimSpace(normalizeToString(raw["FechaLectura"]))
+	if s := strings.TrimSpace(normalizeToString(raw["Datos"])); s != "" {
+		var datos interface{}
+		if err := json.Unmarshal([]byte(s), &datos); err == nil {
+			n.Datos = datos
+		}
+	}
+	return n
+}
+
+func notificacionToCRUD(n internaldto.Notificacion) map[string]interface{} {
+	body := map[string]interface{}{
+		"TerceroId":     n.TerceroID,
+		"Asunto":        n.Asunto,
+		"Plantilla":     n.Plantilla,
+		"Cuerpo":        n.Cuerpo,
+		"CuerpoHtml":    n.CuerpoHTML,
+		"Locale":        n.Locale,
+		"Datos":         nil,
+		"Leida":         n.Leida,
+		"EstadoEnvio":   n.EstadoEnvio,
+		"FechaCreacion": n.FechaCreacion,
+		"FechaLectura":  nil,
+	}
+	if n.Datos != nil {
+		if b, err := json.Marshal(n.Datos); err == nil {
+			body["Datos"] = string(b)
+		}
+	}
+	if n.FechaLectura != "" {
+		body["FechaLectura"] = n.FechaLectura
+	}
+	return body
+}
+
+// ---------------------------------------------------------------------------------------------
+// Almacenamiento en memoria
+
+type notificacionStoreMemoria struct {
+	mu        sync.Mutex
+	siguiente int
+	items     map[int][]internaldto.Notificacion
+}
+
+func newNotificacionStoreMemoria() *notificacionStoreMemoria {
+	return &notificacionStoreMemoria{items: map[int][]internaldto.Notificacion{}}
+}
+
+func (s *notificacionStoreMemoria) Crear(n *internaldto.Notificacion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.siguiente++
+	n.ID = s.siguiente
+	if n.FechaCreacion == "" {
+		n.FechaCreacion = nowISO()
+	}
+	s.items[n.TerceroID] = append(s.items[n.TerceroID], *n)
+	return nil
+}
+
+func (s *notificacionStoreMemoria) Listar(terceroID int, soloNoLeidas bool, page, size int) ([]internaldto.Notificacion, int64, error) {
+	s.mu.Lock()
+	out := make([]internaldto.Notificacion, 0, len(s.items[terceroID]))
+	for _, n := range s.items[terceroID] {
+		if soloNoLeidas && n.Leida {
+			continue
+		}
+		out = append(out, n)
+	}
+	s.mu.Unlock()
+	return paginarNotificaciones(out, page, size), int64(len(out)), nil
+}
+
+func (s *notificacionStoreMemoria) ContarNoLeidas(terceroID int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total int64
+	for _, n := range s.items[terceroID] {
+		if !n.Leida {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func (s *notificacionStoreMemoria) MarcarLeida(terceroID, id int) (*internaldto.Notificacion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.items[terceroID] {
+		n := &s.items[terceroID][i]
+		if n.ID != id {
+			continue
+		}
+		if !n.Leida {
+			n.Leida = true
+			n.FechaLectura = time.Now().UTC().Format(time.RFC3339)
+		}
+		copia := *n
+		return &copia, nil
+	}
+	return nil, helpers.NewAppError(http.StatusNotFound, "notificación no encontrada", nil)
+}
+
+func (s *notificacionStoreMemoria) ActualizarEnvio(terceroID, id int, estado string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.items[terceroID] {
+		if s.items[terceroID][i].ID == id {
+			s.items[terceroID][i].EstadoEnvio = estado
+			return nil
+		}
+	}
+	return helpers.NewAppError(http.StatusNotFound, "notificación no encontrada", nil)
+}
+
+func (s *notificacionStoreMemoria) MarcarTodasLeidas(terceroID int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ahora := time.Now().UTC().Format(time.RFC3339)
+	marcadas := 0
+	for i := range s.items[terceroID] {
+		n := &s.items[terceroID][i]
+		if !n.Leida {
+			n.Leida = true
+			n.FechaLectura = ahora
+			marcadas++
+		}
+	}
+	return marcadas, nil
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	beegocontext "github.com/beego/beego/v2/server/web/context"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	NotificacionCanalServicio = "servicio"
+	NotificacionCanalSMTP     = "smtp"
+	NotificacionCanalNinguno  = "ninguno"
+
+	SMTPSeguridadStartTLS = "starttls"
+	SMTPSeguridadTLS      = "tls"
+	SMTPSeguridadNinguna  = "ninguna"
+
+	defaultSMTPPuerto     = 587
+	defaultSMTPTimeoutSeg = 15
+	correosTTL            = time.Hour
+)
+
+var canalNotificacionesOnce sync.Once
+
+// IniciarCanalNotificaciones registra el canal externo según notificaciones_canal: "servicio"
+// (por defecto, el servicio HTTP de notificaciones_base_url), "smtp" o "ninguno" (solo bandeja).
+// Si la configuración SMTP está incompleta no se registra canal y las notificaciones quedan en la
+// bandeja como SIN_CANAL.
+func IniciarCanalNotificaciones() {
+	canalNotificacionesOnce.Do(func() {
+		switch tipo := strings.ToLower(configTexto("NOTIFICACIONES_CANAL", "notificaciones_canal", NotificacionCanalServicio)); tipo {
+		case NotificacionCanalSMTP:
+			cfg := configSMTP()
+			if err := cfg.validar(); err != nil {
+				logs.Error("canal SMTP de notificaciones:", err, "; las notificaciones quedan solo en la bandeja")
+				internalhelpers.SetCanalNotificacion(nil)
+				return
+			}
+			if cfg.Seguridad == SMTPSeguridadNinguna && cfg.Usuario != "" {
+				logs.Warn("smtp_seguridad = ninguna: se ignoran smtp_usuario y smtp_password")
+			}
+			internalhelpers.SetCanalNotificacion(canalSMTP{cfg: cfg, correo: correoTerceroCache})
+		case NotificacionCanalNinguno:
+			internalhelpers.SetCanalNotificacion(nil)
+		default:
+			if tipo != NotificacionCanalServicio {
+				logs.Warn("notificaciones_canal desconocido:", tipo, "; se usa el servicio de notificaciones")
+			}
+			internalhelpers.SetCanalNotificacion(internalhelpers.CanalServicioNotificaciones())
+		}
+	})
+}
+
+// smtpConfig es la configuración del servidor de correo. Seguridad: "starttls" (por defecto,
+// exige que el servidor lo ofrezca), "tls" (conexión cifrada desde el inicio, puerto 465) o
+// "ninguna", pensada para un servidor SMTP local de pruebas; sin cifrado no se autentica.
+type smtpConfig struct {
+	Host            string
+	Puerto          int
+	Usuario         string
+	Password        string
+	Remitente       string
+	RemitenteNombre string
+	Seguridad       string
+	Timeout         time.Duration
+}
+
+func configSMTP() smtpConfig {
+	cfg := smtpConfig{
+		Host:            configTexto("SMTP_HOST", "smtp_host", ""),
+		Puerto:          configEntero("SMTP_PUERTO", "smtp_puerto", defaultSMTPPuerto),
+		Usuario:         configTexto("SMTP_USUARIO", "smtp_usuario", ""),
+		Password:        configTexto("SMTP_PASSWORD", "smtp_password", ""),
+		Remitente:       configTexto("SMTP_REMITENTE", "smtp_remitente", ""),
+		RemitenteNombre: configTexto("SMTP_REMITENTE_NOMBRE", "smtp_remitente_nombre", ""),
+		Seguridad:       strings.ToLower(configTexto("SMTP_SEGURIDAD", "smtp_seguridad", SMTPSeguridadStartTLS)),
+		Timeout:         time.Duration(configEntero("SMTP_TIMEOUT_SEG", "smtp_timeout_seg", defaultSMTPTimeoutSeg)) * time.Second,
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultSMTPTimeoutSeg * time.Second
+	}
+	return cfg
+}
+
+func (c smtpConfig) validar() error {
+	if strings.TrimSpace(c.Host) == "" {
+		return errors.New("smtp_host requerido")
+	}
+	if c.Puerto <= 0 || c.Puerto > 65535 {
+		return errors.New("smtp_puerto inválido")
+	}
+	if _, err := mail.ParseAddress(c.Remitente); err != nil {
+		return fmt.Errorf("smtp_remitente inválido: %w", err)
+	}
+	switch c.Seguridad {
+	case SMTPSeguridadStartTLS, SMTPSeguridadTLS, SMTPSeguridadNinguna:
+	default:
+		return errors.New("smtp_seguridad debe ser starttls, tls o ninguna")
+	}
+	return nil
+}
+
+// canalSMTP envía la notificación como correo multipart (texto y HTML) a la dirección CORREO del
+// tercero en Terceros.
+type canalSMTP struct {
+	cfg    smtpConfig
+	correo func(terceroID int) (string, error)
+}
+
+func (canalSMTP) Nombre() string { return internaldto.OutboxCanalSMTP }
+
+func (c canalSMTP) Entregar(_ *beegocontext.Context, n internaldto.Notificacion) error {
+	destino, err := c.correo(n.TerceroID)
+	if err != nil {
+		return helpers.AsAppError(err, "error consultando el correo del tercero")
+	}
+	if destino == "" {
+		return helpers.NewAppError(http.StatusUnprocessableEntity, fmt.Sprintf("el tercero %d no tiene correo registrado", n.TerceroID), nil)
+	}
+	to, err := mail.ParseAddress(destino)
+	if err != nil {
+		return helpers.NewAppError(http.StatusUnprocessableEntity, fmt.Sprintf("correo inválido para el tercero %d", n.TerceroID), err)
+	}
+	from, _ := mail.ParseAddress(c.cfg.Remitente)
+	from.Name = c.cfg.RemitenteNombre
+
+	msg, err := mensajeSMTP(*from, *to, n, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := c.enviar(from.Address, to.Address, msg); err != nil {
+		return helpers.NewAppError(http.StatusBadGateway, "error enviando correo", err)
+	}
+	return nil
+}
+
+// enviar abre la conexión, negocia TLS según la configuración, autentica si hay usuario y la
+// conexión va cifrada y entrega el mensaje.
+func (c canalSMTP) enviar(from, to string, msg []byte) error {
+	addr := net.JoinHostPort(c.cfg.Host, strconv.Itoa(c.cfg.Puerto))
+	dialer := &net.Dialer{Timeout: c.cfg.Timeout}
+	tlsConfig := &tls.Config{ServerName: c.cfg.Host, MinVersion: tls.VersionTLS12}
+
+	var conn net.Conn
+	var err error
+	if c.cfg.Seguridad == SMTPSeguridadTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(c.cfg.Timeout))
+
+	cliente, err := smtp.NewClient(conn, c.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer cliente.Close()
+
+	if c.cfg.Seguridad == SMTPSeguridadStartTLS {
+		if ok, _ := cliente.Extension("STARTTLS"); !ok {
+			return errors.New("el servidor SMTP no ofrece STARTTLS")
+		}
+		if err := cliente.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	// Sin cifrado no se envían credenciales (smtp.PlainAuth además lo rechaza fuera de localhost):
+	// el servidor de pruebas debe aceptar correo sin autenticación.
+	if c.cfg.Usuario != "" && c.cfg.Seguridad != SMTPSeguridadNinguna {
+		if err := cliente.Auth(smtp.PlainAuth("", c.cfg.Usuario, c.cfg.Password, c.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := cliente.Mail(from); err != nil {
+		return err
+	}
+	if err := cliente.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := cliente.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return cliente.Quit()
+}
+
+// mensajeSMTP arma el correo: multipart/alternative con texto y HTML si la notificación trae
+// cuerpo HTML; si no, solo texto. Sin cuerpo renderizado se usa el asunto.
+func mensajeSMTP(from, to mail.Address, n internaldto.Notificacion, ahora time.Time) ([]byte, error) {
+	asunto := strings.Join(strings.Fields(n.Asunto), " ")
+	texto := strings.TrimSpace(n.Cuerpo)
+	if texto == "" {
+		texto = asunto + "\n\nIngresa al sistema de pasantías para ver el detalle."
+	}
+
+	var buf bytes.Buffer
+	encabezado := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+	encabezado("From", from.String())
+	encabezado("To", to.String())
+	encabezado("Subject", mime.QEncoding.Encode("utf-8", asunto))
+	encabezado("Date", ahora.Format(time.RFC1123Z))
+	encabezado("Message-ID", idMensajeSMTP(from.Address, ahora))
+	encabezado("MIME-Version", "1.0")
+
+	if strings.TrimSpace(n.CuerpoHTML) == "" {
+		encabezado("Content-Type", "text/plain; charset=UTF-8")
+		encabezado("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := escribirQuotedPrintable(&buf, texto); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var cuerpo bytes.Buffer
+	mw := multipart.NewWriter(&cuerpo)
+	partes := []struct{ tipo, contenido string }{
+		{"text/plain; charset=UTF-8", texto},
+		{"text/html; charset=UTF-8", n.CuerpoHTML},
+	}
+	for _, p := range partes {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.tipo},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := escribirQuotedPrintable(w, p.contenido); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	encabezado("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": mw.Boundary()}))
+	buf.WriteString("\r\n")
+	buf.Write(cuerpo.Bytes())
+	return buf.Bytes(), nil
+}
+
+func escribirQuotedPrintable(w io.Writer, contenido string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(contenido, "\n", "\r\n"))); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func idMensajeSMTP(remitente string, ahora time.Time) string {
+	dominio := "localhost"
+	if i := strings.LastIndex(remitente, "@"); i >= 0 && i < len(remitente)-1 {
+		dominio = remitente[i+1:]
+	}
+	aleatorio := make([]byte, 8)
+	_, _ = rand.Read(aleatorio)
+	return fmt.Sprintf("<%d.%s@%s>", ahora.UnixNano(), hex.EncodeToString(aleatorio), dominio)
+}
+
+// cacheCorreos guarda los correos ya resueltos para no consultar Terceros en cada envío.
+var cacheCorreos = struct {
+	mu    sync.Mutex
+	items map[int]entradaCorreo
+}{items: make(map[int]entradaCorreo)}
+
+type entradaCorreo struct {
+	correo    string
+	expiresAt time.Time
+}
+
+// correoTerceroCache resuelve el correo (InfoComplementaria CORREO) del tercero. Solo se guardan
+// en caché los correos encontrados, para que uno recién registrado se use en el siguiente intento.
+func correoTerceroCache(terceroID int) (string, error) {
+	cacheCorreos.mu.Lock()
+	if e, ok := cacheCorreos.items[terceroID]; ok && time.Now().Before(e.expiresAt) {
+		cacheCorreos.mu.Unlock()
+		return e.correo, nil
+	}
+	cacheCorreos.mu.Unlock()
+
+	correo, err := rootservices.CorreoTercero(terceroID)
+	if err != nil {
+		return "", err
+	}
+	correo = strings.TrimSpace(correo)
+	if correo != "" {
+		cacheCorreos.mu.Lock()
+		cacheCorreos.items[terceroID] = entradaCorreo{correo: correo, expiresAt: time.Now().Add(correosTTL)}
+		cacheCorreos.mu.Unlock()
+	}
+	return correo, nil
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+)
+
+func TestMensajeSMTPMultipart(t *testing.T) {
+	from := mail.Address{Name: "Sistema de Pasantías", Address: "pasantias@example.edu.co"}
+	to := mail.Address{Address: "laura@example.com"}
+	texto := "Hola Laura,\n\nTu postulación cambió de estado: " + strings.Repeat("ñ", 60)
+	n := internaldto.Notificacion{
+		Asunto:     "Postulación\r\nBcc: intruso@example.com seleccionada",
+		Cuerpo:     texto,
+		CuerpoHTML: "<p>Hola <strong>Laura</strong> = ñ</p>",
+	}
+
+	raw, err := mensajeSMTP(from, to, n, time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := msg.Header.Get("Bcc"); got != "" {
+		t.Fatalf("el asunto inyectó un encabezado Bcc: %q", got)
+	}
+	asunto, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asunto != "Postulación Bcc: intruso@example.com seleccionada" {
+		t.Errorf("Subject = %q", asunto)
+	}
+	if !strings.HasPrefix(msg.Header.Get("Message-ID"), "<") || !strings.HasSuffix(msg.Header.Get("Message-ID"), "@example.edu.co>") {
+		t.Errorf("Message-ID = %q", msg.Header.Get("Message-ID"))
+	}
+
+	tipo, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || tipo != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q (%v)", msg.Header.Get("Content-Type"), err)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	want := []struct{ tipo, contenido string }{
+		{"text/plain", strings.ReplaceAll(texto, "\n", "\r\n")},
+		{"text/html", n.CuerpoHTML},
+	}
+	for _, w := range want {
+		parte, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("parte %s: %v", w.tipo, err)
+		}
+		// multipart.Reader decodifica quoted-printable y retira el encabezado.
+		if parte.Header.Get("Content-Transfer-Encoding") != "" {
+			t.Errorf("parte %s sin decodificar", w.tipo)
+		}
+		if got, _, _ := mime.ParseMediaType(parte.Header.Get("Content-Type")); got != w.tipo {
+			t.Errorf("Content-Type = %q, se esperaba %q", got, w.tipo)
+		}
+		cuerpo, err := io.ReadAll(parte)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(cuerpo) != w.contenido {
+			t.Errorf("parte %s = %q, se esperaba %q", w.tipo, cuerpo, w.contenido)
+		}
+	}
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("se esperaban dos partes, siguiente: %v", err)
+	}
+	for _, linea := range strings.Split(string(raw), "\r\n") {
+		if len(linea) > 998 {
+			t.Fatalf("línea de %d caracteres", len(linea))
+		}
+	}
+}
+
+func TestMensajeSMTPSoloTexto(t *testing.T) {
+	from := mail.Address{Address: "pasantias@example.edu.co"}
+	to := mail.Address{Address: "laura@example.com"}
+
+	tests := []struct {
+		nombre string
+		n      internaldto.Notificacion
+		want   string
+	}{
+		{"con cuerpo", internaldto.Notificacion{Asunto: "Aviso", Cuerpo: "Hola\nLaura"}, "Hola\r\nLaura"},
+		{"sin cuerpo usa el asunto", internaldto.Notificacion{Asunto: "Aviso"}, "Aviso\r\n\r\nIngresa al sistema de pasantías para ver el detalle."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			raw, err := mensajeSMTP(from, to, tt.n, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := msg.Header.Get("Content-Type"); got != "text/plain; charset=UTF-8" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			if got := msg.Header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+				t.Errorf("Content-Transfer-Encoding = %q", got)
+			}
+			cuerpo, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(cuerpo) != tt.want {
+				t.Errorf("cuerpo = %q, se esperaba %q", cuerpo, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanalSMTPEntregar(t *testing.T) {
+	tests := []struct {
+		nombre     string
+		seguridad  string
+		correo     string
+		wantErr    bool
+		wantStatus int
+	}{
+		{nombre: "sin cifrado entrega sin autenticar", seguridad: SMTPSeguridadNinguna, correo: "laura@example.com"},
+		{nombre: "starttls no ofrecido", seguridad: SMTPSeguridadStartTLS, correo: "laura@example.com", wantErr: true, wantStatus: 502},
+		{nombre: "tercero sin correo", seguridad: SMTPSeguridadNinguna, correo: "", wantErr: true, wantStatus: 422},
+		{nombre: "correo inválido", seguridad: SMTPSeguridadNinguna, correo: "no es correo", wantErr: true, wantStatus: 422},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			srv := iniciarSMTPFalso(t)
+			canal := canalSMTP{
+				cfg: smtpConfig{
+					Host:      "127.0.0.1",
+					Puerto:    srv.puerto,
+					Usuario:   "usuario",
+					Password:  "secreto",
+					Remitente: "pasantias@example.edu.co",
+					Seguridad: tt.seguridad,
+					Timeout:   5 * time.Second,
+				},
+				correo: func(int) (string, error) { return tt.correo, nil },
+			}
+			err := canal.Entregar(nil, internaldto.Notificacion{TerceroID: 7, Asunto: "Aviso", Cuerpo: "Hola"})
+			if tt.wantErr {
+				appErr, ok := err.(*helpers.AppError)
+				if !ok || appErr.Status != tt.wantStatus {
+					t.Fatalf("error = %v, se esperaba status %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := srv.resultado()
+			if got.auth {
+				t.Error("se enviaron credenciales sin cifrado")
+			}
+			if got.from != "pasantias@example.edu.co" || got.rcpt != tt.correo {
+				t.Errorf("MAIL FROM %q RCPT TO %q", got.from, got.rcpt)
+			}
+			msg, err := mail.ReadMessage(strings.NewReader(got.data))
+			if err != nil {
+				t.Fatalf("DATA no es un correo válido: %v\n%s", err, got.data)
+			}
+			if msg.Header.Get("To") != "<"+tt.correo+">" {
+				t.Errorf("To = %q", msg.Header.Get("To"))
+			}
+		})
+	}
+}
+
+// smtpFalso es un servidor SMTP mínimo en proceso: acepta una sesión sin STARTTLS y registra lo
+// recibido.
+type smtpFalso struct {
+	puerto int
+	mu     sync.Mutex
+	hecho  chan struct{}
+	sesion sesionSMTP
+}
+
+type sesionSMTP struct {
+	auth             bool
+	from, rcpt, data string
+}
+
+func iniciarSMTPFalso(t *testing.T) *smtpFalso {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	s := &smtpFalso{puerto: ln.Addr().(*net.TCPAddr).Port, hecho: make(chan struct{})}
+	go func() {
+		defer close(s.hecho)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		s.atender(conn)
+	}()
+	return s
+}
+
+func (s *smtpFalso) atender(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	responder := func(linea string) { _, _ = io.WriteString(conn, linea+"\r\n") }
+	responder("220 localhost ESMTP")
+	for {
+		linea, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		comando := strings.ToUpper(strings.TrimSpace(linea))
+		switch {
+		case strings.HasPrefix(comando, "EHLO"):
+			responder("250-localhost")
+			responder("250 AUTH PLAIN")
+		case strings.HasPrefix(comando, "AUTH"):
+			s.mu.Lock()
+			s.sesion.auth = true
+			s.mu.Unlock()
+			responder("235 ok")
+		case strings.HasPrefix(comando, "MAIL FROM:"):
+			s.mu.Lock()
+			s.sesion.from = strings.Trim(strings.TrimSpace(linea)[len("MAIL FROM:"):], "<>")
+			s.mu.Unlock()
+			responder("250 ok")
+		case strings.HasPrefix(comando, "RCPT TO:"):
+			s.mu.Lock()
+			s.sesion.rcpt = strings.Trim(strings.TrimSpace(linea)[len("RCPT TO:"):], "<>")
+			s.mu.Unlock()
+			responder("250 ok")
+		case comando == "DATA":
+			responder("354 fin con .")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			s.mu.Lock()
+			s.sesion.data = data.String()
+			s.mu.Unlock()
+			responder("250 encolado")
+		case comando == "QUIT":
+			responder("221 adiós")
+			return
+		default:
+			responder("250 ok")
+		}
+	}
+}
+
+func (s *smtpFalso) resultado() sesionSMTP {
+	<-s.hecho
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sesion
+}
@@ -93,6 +93,9 @@ func AgregarOfertaProyectos(ctx *context.Context, tutorID, ofertaID int, proyect
 		created = append(created, pc)
 	}
 
+	if len(created) > 0 {
+		reindexarOferta(ofertaID)
+	}
 	return map[string]interface{}{
 		"creados": created,
 	}, nil
@@ -114,7 +117,11 @@ func EliminarOfertaProyecto(ctx *context.Context, tutorID, ofertaID, pcID int) e
 
 	cfg := rootservices.GetConfig()
 	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, ofertaPCResource, strconv.Itoa(recordID))
-	return helpers.DoJSON("DELETE", endpoint, nil, nil, cfg.RequestTimeout)
+	if err := helpers.DoJSON("DELETE", endpoint, nil, nil, cfg.RequestTimeout); err != nil {
+		return err
+	}
+	reindexarOferta(ofertaID)
+	return nil
 }
 
 func validarTutoria(ofertaID, tutorID int) error {
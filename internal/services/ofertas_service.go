@@ -2,8 +2,10 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,8 +36,11 @@ const (
 // CrearOfertaReq encapsula el payload necesario para crear una oferta junto a proyectos curriculares.
 type CrearOfertaReq struct {
 	Oferta struct {
-		Titulo      string `json:"titulo"`
-		Descripcion string `json:"descripcion"`
+		Titulo      string   `json:"titulo"`
+		Descripcion string   `json:"descripcion"`
+		Modalidad   string   `json:"modalidad,omitempty"`
+		Ciudad      string   `json:"ciudad,omitempty"`
+		Habilidades []string `json:"habilidades,omitempty"`
 	} `json:"oferta"`
 	ProyectosCurriculares []int `json:"proyectos_curriculares"`
 }
@@ -79,6 +84,7 @@ func CrearOfertaConPCs(ctx context.Context, tutorID int, req CrearOfertaReq) (*i
 	}
 
 	estado := models.OfertaEstadoCreada
+	habilidades := normalizeHabilidadesOferta(req.Oferta.Habilidades)
 
 	proyectos, err := normalizeProyectos(req.ProyectosCurriculares)
 	if err != nil {
@@ -97,7 +103,9 @@ func CrearOfertaConPCs(ctx context.Context, tutorID int, req CrearOfertaReq) (*i
 		strings.TrimSpace(req.Oferta.Descripcion),
 		empresaID,
 		tutorID,
-		"",
+		strings.TrimSpace(req.Oferta.Modalidad),
+		strings.TrimSpace(req.Oferta.Ciudad),
+		habilidades,
 		estado,
 	)
 	if err != nil {
@@ -119,13 +127,23 @@ func CrearOfertaConPCs(ctx context.Context, tutorID int, req CrearOfertaReq) (*i
 		}
 	}
 
+	reindexarOferta(created.Id)
+	PublicarEvento(Evento{
+		Tipo:     EventoOfertaEstado,
+		TutorID:  int64(tutorID),
+		OfertaID: int64(created.Id),
+		Estado:   created.Estado,
+	})
+
 	return &internaldto.OfertaCreateResp{
 		ID:                    created.Id,
 		FechaPublicacion:      fechaPtr,
 		Titulo:                titulo,
 		Descripcion:           strings.TrimSpace(req.Oferta.Descripcion),
 		EmpresaTerceroID:      empresaID,
-		Modalidad:             "",
+		Modalidad:             strings.TrimSpace(req.Oferta.Modalidad),
+		Ciudad:                strings.TrimSpace(req.Oferta.Ciudad),
+		Habilidades:           habilidades,
 		Estado:                created.Estado,
 		TutorExternoID:        tutorID,
 		ProyectosCurriculares: proyectos,
@@ -155,6 +173,28 @@ func normalizeEstado(raw string) (string, error) {
 	}
 }
 
+// normalizeHabilidadesOferta lleva las habilidades requeridas al nombre del catálogo y las deduplica.
+func normalizeHabilidadesOferta(in []string) []string {
+	if len(in) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(in))
+	out := make([]string, 0, len(in))
+	for _, h := range in {
+		h = nombreCanonicoHabilidad(h)
+		key := normalizarTexto(h)
+		if h == "" {
+			continue
+		}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, h)
+	}
+	return out
+}
+
 func normalizeProyectos(ids []int) ([]int, error) {
 	if len(ids) == 0 {
 		return nil, nil
@@ -174,7 +214,7 @@ func normalizeProyectos(ids []int) ([]int, error) {
 	return result, nil
 }
 
-func crearOfertaCRUD(titulo, descripcion string, empresaID, tutorExternoID int, modalidad, estado string) (crudOfertaCreateResponse, error) {
+func crearOfertaCRUD(titulo, descripcion string, empresaID, tutorExternoID int, modalidad, ciudad string, habilidades []string, estado string) (crudOfertaCreateResponse, error) {
 	cfg := rootservices.GetConfig()
 	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, "oferta_pasantia")
 	fmt.Println("Endpoint crear Oferta Pasantía", endpoint)
@@ -189,6 +229,13 @@ func crearOfertaCRUD(titulo, descripcion string, empresaID, tutorExternoID int,
 	if modalidad != "" {
 		payload["Modalidad"] = modalidad
 	}
+	if ciudad != "" {
+		payload["Ciudad"] = ciudad
+	}
+	if len(habilidades) > 0 {
+		encoded, _ := json.Marshal(habilidades)
+		payload["Habilidades"] = string(encoded)
+	}
 
 	var resp crudOfertaCreateResponse
 	fmt.Println("Payload crear Oferta Pasantía", payload)
@@ -292,7 +339,9 @@ func ListarOfertasCatalogo(
 	if tutorID > 0 {
 		baseFilters["tutor_externo_id"] = strconv.Itoa(tutorID)
 	}
-	if trimmed := strings.TrimSpace(q); trimmed != "" {
+	// Con el índice local se busca también en descripción y habilidades; sin él, solo por título en el CRUD.
+	puntajes := puntajesOfertas(q)
+	if trimmed := strings.TrimSpace(q); trimmed != "" && puntajes == nil {
 		baseFilters["query"] = fmt.Sprintf("Titulo__icontains:%s", trimmed)
 	}
 	if trimmed := strings.TrimSpace(sortField); trimmed != "" {
@@ -374,6 +423,13 @@ func ListarOfertasCatalogo(
 	items := make([]map[string]interface{}, 0, len(aggregated))
 	for _, oferta := range aggregated {
 		m := mapOferta(oferta)
+		if puntajes != nil {
+			puntaje, ok := puntajes[oferta.Id]
+			if !ok {
+				continue
+			}
+			m["puntaje_busqueda"] = puntaje
+		}
 		pcIDs, err := getPCIDsByOferta(int(oferta.Id))
 		if err != nil {
 			pcIDs = []int{}
@@ -401,6 +457,12 @@ func ListarOfertasCatalogo(
 		items = append(items, m)
 	}
 
+	if puntajes != nil && strings.TrimSpace(sortField) == "" {
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i]["puntaje_busqueda"].(float64) > items[j]["puntaje_busqueda"].(float64)
+		})
+	}
+
 	total := len(items)
 	start := (page - 1) * size
 	if start > total {
@@ -491,6 +553,13 @@ func CambiarEstadoOferta(ctx *beegocontext.Context, tutorID, ofertaID int, desti
 		return nil, helpers.AsAppError(err, "error actualizando oferta")
 	}
 
+	reindexarOferta(ofertaID)
+	PublicarEvento(Evento{
+		Tipo:     EventoOfertaEstado,
+		TutorID:  int64(tutorID),
+		OfertaID: int64(ofertaID),
+		Estado:   normalized,
+	})
 	return mapOferta(*updated), nil
 }
 
@@ -532,6 +601,9 @@ func mapOferta(oferta models.Oferta) map[string]interface{} {
 		"tutor_externo_id":        oferta.TutorExternoId,
 		"fecha_publicacion":       oferta.FechaPublicacion,
 		"proyecto_curricular_ids": oferta.ProyectoCurricularIds,
+		"modalidad":               oferta.Modalidad,
+		"ciudad":                  oferta.Ciudad,
+		"habilidades":             oferta.Habilidades,
 	}
 }
 
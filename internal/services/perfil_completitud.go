@@ -0,0 +1,147 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	"github.com/udistrital/pasantia_mid/internal/clients"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+)
+
+const (
+	minCaracteresResumenPerfil = 80
+	minHabilidadesPerfil       = 3
+	umbralCompletitudDefecto   = 70
+)
+
+// datosCompletitud reúne los campos del perfil que cuentan para el puntaje, sin importar si vienen
+// del registro del servicio o del cliente del CRUD.
+type datosCompletitud struct {
+	proyectoCurricularID int
+	resumen              string
+	habilidades          string
+	cvDocumento          json.RawMessage
+	consentimiento       bool
+	visible              bool
+}
+
+// umbralCompletitudPerfil es el puntaje mínimo para postularse a ofertas; 0 desactiva el bloqueo.
+func umbralCompletitudPerfil() int {
+	umbral := configEntero("PERFIL_COMPLETITUD_MINIMA", "perfil_completitud_minima", umbralCompletitudDefecto)
+	if umbral < 0 {
+		return 0
+	}
+	if umbral > 100 {
+		return 100
+	}
+	return umbral
+}
+
+func completitudPerfil(record perfilRecord) internaldto.PerfilCompletitud {
+	return calcularCompletitud(datosCompletitud{
+		proyectoCurricularID: record.ProyectoCurricularId,
+		resumen:              record.Resumen,
+		habilidades:          record.Habilidades,
+		cvDocumento:          record.CvDocumentoRaw,
+		consentimiento:       record.consentimientoVigente(),
+		visible:              record.Visible,
+	})
+}
+
+// completitudPerfilCRUD calcula el puntaje sobre el perfil del cliente del CRUD; nil se trata como
+// un perfil vacío para guiar al estudiante que aún no lo ha creado.
+func completitudPerfilCRUD(perfil *clients.PerfilRecord) internaldto.PerfilCompletitud {
+	if perfil == nil {
+		return calcularCompletitud(datosCompletitud{})
+	}
+	return calcularCompletitud(datosCompletitud{
+		proyectoCurricularID: perfil.ProyectoCurricularId,
+		resumen:              perfil.Resumen,
+		habilidades:          perfil.Habilidades,
+		cvDocumento:          perfil.CvDocumentoRaw,
+		consentimiento:       perfil.Extra != nil && consentimientoVigente(perfil.Extra),
+		visible:              perfil.Visible,
+	})
+}
+
+func calcularCompletitud(d datosCompletitud) internaldto.PerfilCompletitud {
+	items := []internaldto.PerfilCompletitudItem{
+		{
+			Clave:    "proyecto_curricular",
+			Peso:     15,
+			Cumplido: d.proyectoCurricularID > 0,
+			Mensaje:  "selecciona tu proyecto curricular",
+		},
+		{
+			Clave:    "resumen",
+			Peso:     20,
+			Cumplido: len([]rune(strings.TrimSpace(d.resumen))) >= minCaracteresResumenPerfil,
+			Mensaje:  fmt.Sprintf("escribe un resumen de al menos %d caracteres", minCaracteresResumenPerfil),
+		},
+		{
+			Clave:    "habilidades",
+			Peso:     20,
+			Cumplido: len(parseHabilidades(d.habilidades)) >= minHabilidadesPerfil,
+			Mensaje:  fmt.Sprintf("registra al menos %d habilidades", minHabilidadesPerfil),
+		},
+		{
+			Clave:    "cv",
+			Peso:     20,
+			Cumplido: tieneCvDocumento(d.cvDocumento),
+			Mensaje:  "adjunta tu hoja de vida",
+		},
+		{
+			Clave:    "consentimiento",
+			Peso:     15,
+			Cumplido: d.consentimiento,
+			Mensaje:  "acepta la versión vigente de los términos de tratamiento de datos",
+		},
+		{
+			Clave:    "visibilidad",
+			Peso:     10,
+			Cumplido: d.visible,
+			Mensaje:  "haz visible tu perfil para los tutores",
+		},
+	}
+
+	result := internaldto.PerfilCompletitud{
+		Umbral:    umbralCompletitudPerfil(),
+		Faltantes: []string{},
+	}
+	for i := range items {
+		if items[i].Cumplido {
+			result.Puntaje += items[i].Peso
+			items[i].Mensaje = ""
+			continue
+		}
+		result.Faltantes = append(result.Faltantes, items[i].Mensaje)
+	}
+	result.Items = items
+	result.PuedePostular = result.Puntaje >= result.Umbral
+	return result
+}
+
+// tieneCvDocumento indica si el perfil referencia un documento: un id mayor a cero o un texto no vacío.
+func tieneCvDocumento(raw json.RawMessage) bool {
+	switch v := decodeCvDocumento(raw).(type) {
+	case int:
+		return v > 0
+	case string:
+		return v != ""
+	}
+	return false
+}
+
+// validarCompletitudPostulacion responde 422 con lo que falta cuando el perfil no alcanza el umbral.
+func validarCompletitudPostulacion(perfil *clients.PerfilRecord) error {
+	completitud := completitudPerfilCRUD(perfil)
+	if completitud.PuedePostular {
+		return nil
+	}
+	msg := fmt.Sprintf("tu perfil está completo al %d%% y se requiere al menos %d%% para postularte: %s",
+		completitud.Puntaje, completitud.Umbral, strings.Join(completitud.Faltantes, "; "))
+	return helpers.NewAppError(http.StatusUnprocessableEntity, msg, nil)
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCalcularCompletitud(t *testing.T) {
+	t.Setenv("PERFIL_COMPLETITUD_MINIMA", "70")
+	completo := datosCompletitud{
+		proyectoCurricularID: 10,
+		resumen:              strings.Repeat("a", minCaracteresResumenPerfil),
+		habilidades:          `["Go","SQL","Docker"]`,
+		cvDocumento:          json.RawMessage(`123`),
+		consentimiento:       true,
+		visible:              true,
+	}
+
+	tests := []struct {
+		nombre        string
+		modificar     func(d *datosCompletitud)
+		wantPuntaje   int
+		wantFaltantes []string
+		wantPostular  bool
+	}{
+		{nombre: "perfil completo", modificar: func(*datosCompletitud) {}, wantPuntaje: 100, wantPostular: true},
+		{
+			nombre:        "perfil vacío",
+			modificar:     func(d *datosCompletitud) { *d = datosCompletitud{} },
+			wantPuntaje:   0,
+			wantFaltantes: []string{"proyecto_curricular", "resumen", "habilidades", "cv", "consentimiento", "visibilidad"},
+		},
+		{
+			nombre: "resumen corto cuenta runas sin espacios",
+			modificar: func(d *datosCompletitud) {
+				d.resumen = "  " + strings.Repeat("ñ", minCaracteresResumenPerfil-1) + "  "
+			},
+			wantPuntaje:   80,
+			wantFaltantes: []string{"resumen"},
+			wantPostular:  true,
+		},
+		{
+			nombre:       "resumen con runas multibyte alcanza el mínimo",
+			modificar:    func(d *datosCompletitud) { d.resumen = strings.Repeat("ñ", minCaracteresResumenPerfil) },
+			wantPuntaje:  100,
+			wantPostular: true,
+		},
+		{
+			nombre:        "menos habilidades que el mínimo",
+			modificar:     func(d *datosCompletitud) { d.habilidades = `["Go","SQL"]` },
+			wantPuntaje:   80,
+			wantFaltantes: []string{"habilidades"},
+			wantPostular:  true,
+		},
+		{
+			nombre:        "cv vacío no cuenta",
+			modificar:     func(d *datosCompletitud) { d.cvDocumento = json.RawMessage(`""`) },
+			wantPuntaje:   80,
+			wantFaltantes: []string{"cv"},
+			wantPostular:  true,
+		},
+		{
+			nombre:        "cv cero no cuenta",
+			modificar:     func(d *datosCompletitud) { d.cvDocumento = json.RawMessage(`0`) },
+			wantPuntaje:   80,
+			wantFaltantes: []string{"cv"},
+			wantPostular:  true,
+		},
+		{
+			nombre:       "cv como texto",
+			modificar:    func(d *datosCompletitud) { d.cvDocumento = json.RawMessage(`"hv.pdf"`) },
+			wantPuntaje:  100,
+			wantPostular: true,
+		},
+		{
+			nombre: "por debajo del umbral no puede postular",
+			modificar: func(d *datosCompletitud) {
+				d.cvDocumento = nil
+				d.consentimiento = false
+			},
+			wantPuntaje:   65,
+			wantFaltantes: []string{"cv", "consentimiento"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			d := completo
+			tt.modificar(&d)
+			got := calcularCompletitud(d)
+
+			if got.Puntaje != tt.wantPuntaje {
+				t.Errorf("puntaje = %d, se esperaba %d", got.Puntaje, tt.wantPuntaje)
+			}
+			if got.Umbral != 70 {
+				t.Errorf("umbral = %d", got.Umbral)
+			}
+			if got.PuedePostular != tt.wantPostular {
+				t.Errorf("puede postular = %v, se esperaba %v", got.PuedePostular, tt.wantPostular)
+			}
+			var faltantes []string
+			total := 0
+			for _, item := range got.Items {
+				total += item.Peso
+				if item.Cumplido != (item.Mensaje == "") {
+					t.Errorf("%s: cumplido = %v con mensaje %q", item.Clave, item.Cumplido, item.Mensaje)
+				}
+				if !item.Cumplido {
+					faltantes = append(faltantes, item.Clave)
+				}
+			}
+			if total != 100 {
+				t.Errorf("los pesos suman %d", total)
+			}
+			if strings.Join(faltantes, ",") != strings.Join(tt.wantFaltantes, ",") {
+				t.Errorf("faltantes = %v, se esperaba %v", faltantes, tt.wantFaltantes)
+			}
+			if len(got.Faltantes) != len(tt.wantFaltantes) {
+				t.Errorf("%d mensajes de faltantes, se esperaban %d", len(got.Faltantes), len(tt.wantFaltantes))
+			}
+		})
+	}
+}
+
+func TestUmbralCompletitudPerfil(t *testing.T) {
+	tests := []struct {
+		env  string
+		want int
+	}{
+		{"", umbralCompletitudDefecto},
+		{"50", 50},
+		{"0", 0},
+		{"-5", 0},
+		{"150", 100},
+		{"no", umbralCompletitudDefecto},
+	}
+	for _, tt := range tests {
+		t.Setenv("PERFIL_COMPLETITUD_MINIMA", tt.env)
+		if got := umbralCompletitudPerfil(); got != tt.want {
+			t.Errorf("PERFIL_COMPLETITUD_MINIMA=%q: umbral = %d, se esperaba %d", tt.env, got, tt.want)
+		}
+	}
+}
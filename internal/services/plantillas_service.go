@@ -0,0 +1,48 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+)
+
+// ListarPlantillasNotificacion describe las plantillas que el MID renderiza.
+func ListarPlantillasNotificacion() []internaldto.PlantillaResumen {
+	return internalhelpers.ListarPlantillas()
+}
+
+// PrevisualizarPlantilla renderiza la plantilla con sus datos de ejemplo; datosRaw (JSON) reemplaza
+// o agrega variables. Sin locale se usa el de notificaciones_locale.
+func PrevisualizarPlantilla(id, locale, datosRaw string) (internaldto.PlantillaRenderizada, error) {
+	p, ok := internalhelpers.ObtenerPlantilla(strings.TrimSpace(id))
+	if !ok {
+		return internaldto.PlantillaRenderizada{}, helpers.NewAppError(http.StatusNotFound, "plantilla no encontrada", nil)
+	}
+
+	datos := make(map[string]interface{}, len(p.Ejemplo))
+	for k, v := range p.Ejemplo {
+		datos[k] = v
+	}
+	if raw := strings.TrimSpace(datosRaw); raw != "" {
+		var extra map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &extra); err != nil {
+			return internaldto.PlantillaRenderizada{}, helpers.NewAppError(http.StatusBadRequest, "datos debe ser un objeto JSON", err)
+		}
+		for k, v := range extra {
+			datos[k] = v
+		}
+	}
+	if strings.TrimSpace(locale) == "" {
+		locale = internalhelpers.LocaleNotificacion(datos)
+	}
+
+	out, err := internalhelpers.RenderPlantilla(p.ID, locale, datos)
+	if err != nil {
+		return out, helpers.NewAppError(http.StatusUnprocessableEntity, "no fue posible renderizar la plantilla con los datos enviados", err)
+	}
+	return out, nil
+}
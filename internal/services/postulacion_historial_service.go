@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	"github.com/udistrital/pasantia_mid/internal/clients"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	"github.com/udistrital/pasantia_mid/models"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	postulacionComentarioResource = "postulacion_comentario"
+
+	rolTutor      = "TUTOR"
+	rolEstudiante = "ESTUDIANTE"
+
+	// ComentarioVisibilidadPublica es visible para tutor y estudiante.
+	ComentarioVisibilidadPublica = "PUBLICA"
+	// ComentarioVisibilidadInterna es una nota del tutor que el estudiante no ve.
+	ComentarioVisibilidadInterna = "INTERNA"
+
+	accionPostulacionCreada = "POSTULACION"
+	maxComentarioLen        = 2000
+)
+
+var accionHistorialNombres = map[string]string{
+	accionPostulacionCreada: "Postulación registrada",
+	accionVisto:             "Postulación vista",
+	accionDescartar:         "Postulación descartada",
+	accionPreseleccionar:    "Postulación preseleccionada",
+	accionSeleccionar:       "Postulación seleccionada",
+	accionRevisionAceptar:   "Selección aceptada por el estudiante",
+	accionRevisionRechazo:   "Rechazada por elección de otra oferta",
+	accionEvaluar:           "Postulación evaluada",
+}
+
+// HistorialPostulacion retorna en orden cronológico las acciones registradas sobre la postulación.
+// Los comentarios de las revisiones solo se exponen al tutor dueño de la oferta.
+func HistorialPostulacion(ctx context.Context, postulacionID int64, tutorID, estudianteID int) (map[string]interface{}, error) {
+	post, rol, err := autorizarPostulacion(ctx, postulacionID, tutorID, estudianteID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisiones, err := clients.CastorCRUD().ListPostulacionRevisiones(ctx, postulacionID)
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error consultando historial de postulación")
+	}
+	sort.SliceStable(revisiones, func(i, j int) bool {
+		return revisiones[i].Fecha.Before(revisiones[j].Fecha)
+	})
+
+	nombres := newNombreResolver(ctx)
+	items := make([]map[string]interface{}, 0, len(revisiones)+1)
+
+	if fecha := strings.TrimSpace(post.FechaPostulacion); fecha != "" {
+		items = append(items, map[string]interface{}{
+			"accion":        accionPostulacionCreada,
+			"accion_nombre": accionHistorialNombres[accionPostulacionCreada],
+			"fecha":         fecha,
+			"actor": map[string]interface{}{
+				"tercero_id":      post.EstudianteId,
+				"rol":             rolEstudiante,
+				"nombre_completo": nombres.get(int(post.EstudianteId)),
+			},
+		})
+	}
+
+	for _, rev := range revisiones {
+		if rol != rolTutor && !revisionVisibleEstudiante(rev.Accion) {
+			continue
+		}
+		actorID, actorRol := rev.TutorId, rolTutor
+		if actorID <= 0 {
+			// Las revisiones registradas por el estudiante se guardan con TutorId=0.
+			actorID, actorRol = int(post.EstudianteId), rolEstudiante
+		}
+		entry := map[string]interface{}{
+			"id":            rev.Id,
+			"accion":        rev.Accion,
+			"accion_nombre": nombreAccionHistorial(rev.Accion),
+			"actor": map[string]interface{}{
+				"tercero_id":      actorID,
+				"rol":             actorRol,
+				"nombre_completo": nombres.get(actorID),
+			},
+		}
+		if !rev.Fecha.IsZero() {
+			entry["fecha"] = rev.Fecha.Format(time.RFC3339)
+		}
+		if rev.Accion == accionEvaluar {
+			if ev, ok := parseEvaluacion(rev.Comentario); ok {
+				entry["puntaje"] = ev.Puntaje
+				if ev.Comentario != "" {
+					entry["comentario"] = ev.Comentario
+				}
+			}
+		} else if rol == rolTutor && strings.TrimSpace(rev.Comentario) != "" {
+			entry["comentario"] = strings.TrimSpace(rev.Comentario)
+		}
+		items = append(items, entry)
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(post.EstadoPostulacion))
+	return map[string]interface{}{
+		"postulacion_id": post.Id,
+		"oferta_id":      post.OfertaId,
+		"estado_det": map[string]string{
+			"code":   code,
+			"nombre": resolveEstadoNombre(code),
+		},
+		"items": items,
+		"total": len(items),
+	}, nil
+}
+
+// ListarComentariosPostulacion retorna el hilo de comentarios; el estudiante solo ve los públicos.
+func ListarComentariosPostulacion(ctx context.Context, postulacionID int64, tutorID, estudianteID int) (map[string]interface{}, error) {
+	_, rol, err := autorizarPostulacion(ctx, postulacionID, tutorID, estudianteID)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := listComentariosCRUD(postulacionID)
+	if err != nil {
+		return nil, err
+	}
+
+	nombres := newNombreResolver(ctx)
+	items := make([]map[string]interface{}, 0, len(raw))
+	for _, entry := range raw {
+		comentario := normalizeComentario(entry)
+		if comentario == nil {
+			continue
+		}
+		if rol != rolTutor && comentario["visibilidad"] != ComentarioVisibilidadPublica {
+			continue
+		}
+		if autorID, ok := normalizeToInt(comentario["autor_id"]); ok {
+			comentario["autor_nombre"] = nombres.get(autorID)
+		}
+		items = append(items, comentario)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return parseTime(fmt.Sprint(items[i]["fecha"])).Before(parseTime(fmt.Sprint(items[j]["fecha"])))
+	})
+
+	return map[string]interface{}{
+		"postulacion_id": postulacionID,
+		"items":          items,
+		"total":          len(items),
+	}, nil
+}
+
+// CrearComentarioPostulacion agrega un comentario al hilo de la postulación.
+func CrearComentarioPostulacion(ctx context.Context, postulacionID int64, tutorID, estudianteID int, payload internaldto.PostulacionComentarioCreate) (map[string]interface{}, error) {
+	_, rol, err := autorizarPostulacion(ctx, postulacionID, tutorID, estudianteID)
+	if err != nil {
+		return nil, err
+	}
+
+	mensaje := strings.TrimSpace(payload.Mensaje)
+	if mensaje == "" {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "mensaje es requerido", nil)
+	}
+	if len([]rune(mensaje)) > maxComentarioLen {
+		return nil, helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("mensaje supera %d caracteres", maxComentarioLen), nil)
+	}
+
+	visibilidad := strings.ToUpper(strings.TrimSpace(payload.Visibilidad))
+	if visibilidad == "" {
+		visibilidad = ComentarioVisibilidadPublica
+	}
+	if visibilidad != ComentarioVisibilidadPublica && visibilidad != ComentarioVisibilidadInterna {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "visibilidad no soportada", nil)
+	}
+	if visibilidad == ComentarioVisibilidadInterna && rol != rolTutor {
+		return nil, helpers.NewAppError(http.StatusForbidden, "solo el tutor puede registrar notas internas", nil)
+	}
+
+	autorID := tutorID
+	if rol == rolEstudiante {
+		autorID = estudianteID
+	}
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, postulacionComentarioResource)
+	body := map[string]interface{}{
+		"PostulacionId": postulacionID,
+		"AutorId":       autorID,
+		"AutorRol":      rol,
+		"Mensaje":       mensaje,
+		"Visibilidad":   visibilidad,
+		"Fecha":         nowISO(),
+	}
+
+	var created map[string]interface{}
+	if err := helpers.DoJSON("POST", endpoint, body, &created, cfg.RequestTimeout); err != nil {
+		return nil, helpers.AsAppError(err, "error registrando comentario")
+	}
+
+	out := normalizeComentario(created)
+	if out == nil {
+		out = normalizeComentario(body)
+	}
+	out["autor_nombre"] = NombreCompletoPorIDCoreStd(ctx, autorID)
+	return out, nil
+}
+
+// autorizarPostulacion valida que quien consulta sea el tutor dueño de la oferta o el estudiante postulado.
+func autorizarPostulacion(ctx context.Context, postulacionID int64, tutorID, estudianteID int) (*models.Postulacion, string, error) {
+	if postulacionID <= 0 {
+		return nil, "", helpers.NewAppError(http.StatusBadRequest, "postulacion_id inválido", nil)
+	}
+	if tutorID <= 0 && estudianteID <= 0 {
+		return nil, "", helpers.NewAppError(http.StatusBadRequest, "tutor_id o estudiante_id requerido", nil)
+	}
+
+	post, err := clients.CastorCRUD().GetPostulacionByID(ctx, postulacionID)
+	if err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil, "", helpers.NewAppError(http.StatusNotFound, "postulación no encontrada", nil)
+		}
+		return nil, "", helpers.AsAppError(err, "error consultando postulación")
+	}
+	if post == nil || post.Id == 0 {
+		return nil, "", helpers.NewAppError(http.StatusNotFound, "postulación no encontrada", nil)
+	}
+
+	if tutorID > 0 {
+		oferta, err := rootservices.GetOferta(post.OfertaId)
+		if err != nil {
+			return nil, "", helpers.AsAppError(err, "error consultando oferta asociada")
+		}
+		if oferta == nil {
+			return nil, "", helpers.NewAppError(http.StatusNotFound, "oferta asociada no encontrada", nil)
+		}
+		if int(oferta.TutorExternoId) == tutorID {
+			return post, rolTutor, nil
+		}
+	}
+	if estudianteID > 0 && int(post.EstudianteId) == estudianteID {
+		return post, rolEstudiante, nil
+	}
+	return nil, "", helpers.NewAppError(http.StatusForbidden, "no autorizado para consultar esta postulación", nil)
+}
+
+func listComentariosCRUD(postulacionID int64) ([]map[string]interface{}, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, postulacionComentarioResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+	values.Set("query", fmt.Sprintf("PostulacionId:%d", postulacionID))
+	values.Set("sortby", "Fecha")
+	values.Set("order", "asc")
+
+	var raw []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &raw, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return []map[string]interface{}{}, nil
+		}
+		return nil, helpers.AsAppError(err, "error consultando comentarios")
+	}
+	return raw, nil
+}
+
+func normalizeComentario(data map[string]interface{}) map[string]interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	out := map[string]interface{}{}
+	if v, ok := normalizeToInt64(data["Id"]); ok {
+		out["id"] = v
+	}
+	if v, ok := normalizeToInt64(data["PostulacionId"]); ok {
+		out["postulacion_id"] = v
+	}
+	if v, ok := normalizeToInt(data["AutorId"]); ok {
+		out["autor_id"] = v
+	}
+	out["autor_rol"] = strings.ToUpper(strings.TrimSpace(normalizeToString(data["AutorRol"])))
+	out["mensaje"] = strings.TrimSpace(normalizeToString(data["Mensaje"]))
+	visibilidad := strings.ToUpper(strings.TrimSpace(normalizeToString(data["Visibilidad"])))
+	if visibilidad == "" {
+		visibilidad = ComentarioVisibilidadPublica
+	}
+	out["visibilidad"] = visibilidad
+	out["fecha"] = strings.TrimSpace(normalizeToString(data["Fecha"]))
+	return out
+}
+
+func nombreAccionHistorial(accion string) string {
+	if nombre, ok := accionHistorialNombres[strings.ToUpper(strings.TrimSpace(accion))]; ok {
+		return nombre
+	}
+	return accion
+}
+
+// revisionVisibleEstudiante indica si la acción puede mostrarse en el historial del estudiante.
+// Las evaluaciones con rúbrica son exclusivas del tutor y coordinación.
+func revisionVisibleEstudiante(accion string) bool {
+	code := strings.ToUpper(strings.TrimSpace(accion))
+	if code == accionEvaluar {
+		return false
+	}
+	_, ok := accionHistorialNombres[code]
+	return ok
+}
+
+// nombreResolver cachea nombres de terceros durante un mismo request.
+type nombreResolver struct {
+	ctx   context.Context
+	cache map[int]string
+}
+
+func newNombreResolver(ctx context.Context) *nombreResolver {
+	return &nombreResolver{ctx: ctx, cache: map[int]string{}}
+}
+
+func (r *nombreResolver) get(id int) string {
+	if id <= 0 {
+		return ""
+	}
+	if nombre, ok := r.cache[id]; ok {
+		return nombre
+	}
+	nombre := strings.TrimSpace(NombreCompletoPorIDCoreStd(r.ctx, id))
+	r.cache[id] = nombre
+	return nombre
+}
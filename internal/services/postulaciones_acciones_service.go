@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	"github.com/udistrital/pasantia_mid/internal/clients"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	accionesLoteMaxItems    = 200
+	accionesLoteConcurrency = 6
+)
+
+// EjecutarAccionesLote aplica la misma acción a varias postulaciones de una oferta.
+// La propiedad de la oferta se valida una sola vez; cada ítem reporta su propio resultado.
+func EjecutarAccionesLote(ctx context.Context, tutorID, ofertaID int, payload internaldto.PostulacionAccionLote) (map[string]interface{}, error) {
+	accion := strings.ToUpper(strings.TrimSpace(payload.Accion))
+	if !accionLoteValida(accion) {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "accion no soportada en lote (VISTO, DESCARTAR, PRESELECCIONAR)", nil)
+	}
+
+	ids := uniquePositiveIDs(payload.Ids)
+	if len(ids) == 0 {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "ids requerido", nil)
+	}
+	if len(ids) > accionesLoteMaxItems {
+		return nil, helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("máximo %d postulaciones por lote", accionesLoteMaxItems), nil)
+	}
+
+	oferta, err := rootservices.GetOferta(int64(ofertaID))
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error consultando oferta")
+	}
+	if oferta == nil {
+		return nil, helpers.NewAppError(http.StatusNotFound, "oferta no encontrada", nil)
+	}
+	if int(oferta.TutorExternoId) != tutorID {
+		return nil, helpers.NewAppError(http.StatusForbidden, "no autorizado para gestionar esta oferta", nil)
+	}
+
+	results := make([]map[string]interface{}, len(ids))
+	sem := make(chan struct{}, accionesLoteConcurrency)
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int, postulacionID int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[idx] = ejecutarAccionItem(ctx, tutorID, int64(ofertaID), postulacionID, accion, payload.Comentario)
+		}(i, id)
+	}
+	wg.Wait()
+
+	exitosas := 0
+	for _, r := range results {
+		if ok, _ := r["success"].(bool); ok {
+			exitosas++
+		}
+	}
+
+	return map[string]interface{}{
+		"oferta_id": ofertaID,
+		"accion":    accion,
+		"total":     len(results),
+		"exitosas":  exitosas,
+		"fallidas":  len(results) - exitosas,
+		"items":     results,
+	}, nil
+}
+
+func ejecutarAccionItem(ctx context.Context, tutorID int, ofertaID, postulacionID int64, accion string, comentario *string) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":      postulacionID,
+		"success": false,
+	}
+	fail := func(err error, fallback string) map[string]interface{} {
+		appErr := helpers.AsAppError(err, fallback)
+		result["status"] = appErr.Status
+		result["error"] = appErr.Message
+		return result
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fail(helpers.NewAppError(http.StatusRequestTimeout, "solicitud cancelada", err), "solicitud cancelada")
+	}
+
+	crud := clients.CastorCRUD()
+	postulacion, err := crud.GetPostulacionByID(ctx, postulacionID)
+	if err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return fail(helpers.NewAppError(http.StatusNotFound, "postulación no encontrada", nil), "")
+		}
+		return fail(err, "error consultando postulación")
+	}
+	if postulacion == nil || postulacion.OfertaId != ofertaID {
+		return fail(helpers.NewAppError(http.StatusNotFound, "postulación no encontrada en la oferta", nil), "")
+	}
+
+	if err := aplicarAccionPostulacion(ctx, tutorID, postulacion, accion, comentario); err != nil {
+		return fail(err, "error ejecutando acción")
+	}
+
+	estado := strings.TrimSpace(postulacion.EstadoPostulacion)
+	if updated, err := crud.GetPostulacionByID(ctx, postulacionID); err == nil && updated != nil {
+		estado = strings.TrimSpace(updated.EstadoPostulacion)
+	}
+	code := strings.ToUpper(estado)
+	result["success"] = true
+	result["status"] = http.StatusOK
+	result["estado"] = estado
+	result["estado_det"] = map[string]string{
+		"code":   code,
+		"nombre": resolveEstadoNombre(code),
+	}
+	return result
+}
+
+func accionLoteValida(accion string) bool {
+	switch accion {
+	case accionVisto, accionDescartar, accionPreseleccionar:
+		return true
+	default:
+		return false
+	}
+}
+
+func uniquePositiveIDs(ids []int64) []int64 {
+	seen := make(map[int64]struct{}, len(ids))
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
@@ -36,6 +36,9 @@ func PostularOferta(ctx *context.Context, estudianteID int, ofertaID int64) (map
 	if perfil == nil {
 		return nil, helpers.NewAppError(http.StatusNotFound, "perfil no encontrado", nil)
 	}
+	if err := validarCompletitudPostulacion(perfil); err != nil {
+		return nil, err
+	}
 
 	exists, err := existsPostulacion(estudianteID, ofertaID)
 	if err != nil {
@@ -66,6 +69,14 @@ func PostularOferta(ctx *context.Context, estudianteID int, ofertaID int64) (map
 	}
 
 	id, _ := normalizeToInt64(created["Id"])
+	PublicarEvento(Evento{
+		Tipo:          EventoPostulacionCreada,
+		EstudianteID:  int64(estudianteID),
+		PerfilID:      int64(perfil.Id),
+		OfertaID:      ofertaID,
+		PostulacionID: id,
+		Estado:        "PSPO_CTR",
+	})
 
 	return map[string]interface{}{
 		"id":                id,
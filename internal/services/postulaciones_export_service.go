@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	internalhelpers "github.com/udistrital/pasantia_mid/internal/helpers"
+)
+
+const (
+	ExportFormatoCSV  = "csv"
+	ExportFormatoXLSX = "xlsx"
+
+	contentTypeCSV  = "text/csv; charset=utf-8"
+	contentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// ArchivoExportado es el resultado binario de una exportación.
+type ArchivoExportado struct {
+	Nombre      string
+	ContentType string
+	Contenido   []byte
+}
+
+var exportPostulacionesEncabezado = []interface{}{
+	"Postulación ID",
+	"Estudiante ID",
+	"Estudiante",
+	"Proyecto curricular",
+	"Estado",
+	"Fecha de postulación",
+	"Visto",
+	"Hoja de vida",
+	"Puntaje",
+}
+
+// ExportarPostulaciones genera el listado de postulaciones de la oferta en CSV o XLSX,
+// ordenado por puntaje. Aplica la misma validación de propiedad de la oferta que las acciones.
+func ExportarPostulaciones(ctx context.Context, tutorID, ofertaID int, formato string, coordinacion *internaldto.AsignacionCoordinacion) (*ArchivoExportado, error) {
+	formato = strings.ToLower(strings.TrimSpace(formato))
+	if formato == "" {
+		formato = ExportFormatoCSV
+	}
+	if formato != ExportFormatoCSV && formato != ExportFormatoXLSX {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "format debe ser csv o xlsx", nil)
+	}
+
+	oferta, err := autorizarOfertaEvaluacion(tutorID, ofertaID, coordinacion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Visto y puntaje se calculan con el tutor dueño de la oferta, también para coordinación.
+	listado, err := ListarPostulaciones(ctx, int(oferta.TutorExternoId), ofertaID, PostulacionesOrden{SortBy: "puntaje", Order: "desc"})
+	if err != nil {
+		return nil, err
+	}
+	items, _ := listado["items"].([]map[string]interface{})
+
+	rows := make([][]interface{}, 0, len(items)+1)
+	rows = append(rows, exportPostulacionesEncabezado)
+	for _, item := range items {
+		estado := ""
+		if det, ok := item["Estado"].(map[string]string); ok {
+			estado = det["nombre"]
+		}
+		visto := "No"
+		if v, _ := item["visto"].(bool); v {
+			visto = "Sí"
+		}
+		rows = append(rows, []interface{}{
+			item["id"],
+			item["estudiante_id"],
+			normalizeToString(item["estudiante_nombre"]),
+			normalizeToString(item["proyecto_curricular_nombre"]),
+			estado,
+			normalizeToString(item["fecha_postulacion"]),
+			visto,
+			normalizeToString(item["enlace_doc_hv"]),
+			item["puntaje"],
+		})
+	}
+
+	nombre := fmt.Sprintf("postulaciones_oferta_%d_%s.%s", ofertaID, time.Now().Format("20060102"), formato)
+	if formato == ExportFormatoXLSX {
+		content, err := internalhelpers.BuildXLSX("Postulaciones", rows)
+		if err != nil {
+			return nil, helpers.AsAppError(err, "error generando xlsx")
+		}
+		return &ArchivoExportado{Nombre: nombre, ContentType: contentTypeXLSX, Contenido: content}, nil
+	}
+
+	content, err := buildCSV(rows)
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error generando csv")
+	}
+	return &ArchivoExportado{Nombre: nombre, ContentType: contentTypeCSV, Contenido: content}, nil
+}
+
+// buildCSV serializa las filas con BOM UTF-8 para que Excel respete las tildes.
+func buildCSV(rows [][]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+	w := csv.NewWriter(&buf)
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			if text, ok := v.(string); ok {
+				record[i] = escapeCSVFormula(text)
+			} else if v != nil {
+				record[i] = normalizeToString(v)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// escapeCSVFormula evita que hojas de cálculo interpreten textos como fórmulas.
+func escapeCSVFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -48,14 +49,22 @@ var (
 	preselectCode string
 )
 
+// PostulacionesOrden define el ordenamiento opcional del listado de postulaciones.
+// SortBy admite "puntaje"; Order admite "asc" o "desc" (por defecto desc).
+type PostulacionesOrden struct {
+	SortBy string
+	Order  string
+}
+
 type estudianteEnriq struct {
 	NombreCompleto           string
 	ProyectoCurricularID     int
 	ProyectoCurricularNombre string
 }
 
-// ListarPostulaciones trae las postulaciones de la oferta marcando si fueron vistas.
-func ListarPostulaciones(ctx context.Context, tutorID, ofertaID int) (map[string]interface{}, error) {
+// ListarPostulaciones trae las postulaciones de la oferta marcando si fueron vistas
+// e incluyendo el último puntaje ponderado asignado por el tutor.
+func ListarPostulaciones(ctx context.Context, tutorID, ofertaID int, orden PostulacionesOrden) (map[string]interface{}, error) {
 	filters := map[string]string{
 		"oferta_id": strconv.Itoa(ofertaID),
 		"limit":     "0",
@@ -80,6 +89,7 @@ func ListarPostulaciones(ctx context.Context, tutorID, ofertaID int) (map[string
 	}
 
 	vistos := traerRevisiones(tutorID, postulacionIDs)
+	puntajes := traerPuntajes(tutorID, postulacionIDs)
 	cvMap := traerCvDocumentos(estudianteIDs)
 	enriq := enriquecerEstudiantes(ctx, estudianteIDs)
 
@@ -115,9 +125,30 @@ func ListarPostulaciones(ctx context.Context, tutorID, ofertaID int) (map[string
 		if cv, ok := cvMap[p.EstudianteId]; ok {
 			item["cv_documento_id"] = cv
 		}
+		if puntaje, ok := puntajes[p.Id]; ok {
+			item["puntaje"] = puntaje
+		} else {
+			item["puntaje"] = nil
+		}
 		items = append(items, item)
 	}
 
+	if strings.EqualFold(strings.TrimSpace(orden.SortBy), "puntaje") {
+		desc := !strings.EqualFold(strings.TrimSpace(orden.Order), "asc")
+		sort.SliceStable(items, func(i, j int) bool {
+			pi, okI := puntajes[items[i]["id"].(int64)]
+			pj, okJ := puntajes[items[j]["id"].(int64)]
+			if okI != okJ {
+				// Las postulaciones sin evaluar quedan al final.
+				return okI
+			}
+			if desc {
+				return pi > pj
+			}
+			return pi < pj
+		})
+	}
+
 	return map[string]interface{}{
 		"items": items,
 		"total": len(items),
@@ -151,69 +182,101 @@ func EjecutarAccionPostulacion(ctx context.Context, tutorID int, postulacionID i
 		return nil, helpers.NewAppError(http.StatusForbidden, "no autorizado para gestionar esta postulación", nil)
 	}
 
+	if err := aplicarAccionPostulacion(ctx, tutorID, postulacion, accion, payload.Comentario); err != nil {
+		return nil, err
+	}
+
+	updated, err := crud.GetPostulacionByID(ctx, postulacionID)
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error consultando postulación actualizada")
+	}
+	if updated == nil {
+		return nil, helpers.NewAppError(http.StatusNotFound, "postulación no encontrada", nil)
+	}
+
+	response := map[string]interface{}{
+		"id":                updated.Id,
+		"estudiante_id":     updated.EstudianteId,
+		"oferta_id":         updated.OfertaId,
+		"estado":            strings.TrimSpace(updated.EstadoPostulacion),
+		"fecha_postulacion": strings.TrimSpace(updated.FechaPostulacion),
+		"accion":            accion,
+	}
+	code := strings.ToUpper(strings.TrimSpace(updated.EstadoPostulacion))
+	response["estado_det"] = map[string]string{
+		"code":   code,
+		"nombre": resolveEstadoNombre(code),
+	}
+
+	return response, nil
+}
+
+// aplicarAccionPostulacion ejecuta la acción sobre una postulación cuya propiedad ya fue validada.
+func aplicarAccionPostulacion(ctx context.Context, tutorID int, postulacion *models.Postulacion, accion string, comentario *string) error {
+	crud := clients.CastorCRUD()
+	postulacionID := postulacion.Id
+	var err error
+
 	estadoActual := strings.ToUpper(strings.TrimSpace(postulacion.EstadoPostulacion))
 	if accion == accionDescartar && estadoActual == models.PostEstadoRechazada {
-		return nil, helpers.NewAppError(http.StatusConflict, "La postulación ya está descartada", nil)
+		return helpers.NewAppError(http.StatusConflict, "La postulación ya está descartada", nil)
 	}
 	if (accion == accionDescartar || accion == accionPreseleccionar) && estadoFinal(estadoActual) {
-		return nil, helpers.NewAppError(http.StatusConflict, "La postulación está en estado final", nil)
+		return helpers.NewAppError(http.StatusConflict, "La postulación está en estado final", nil)
 	}
 
+	estadoNuevo := ""
 	switch accion {
 	case accionVisto:
 		if estadoActual == models.PostEstadoPorRevisar {
 			if err = crud.UpdatePostulacionEstado(ctx, postulacionID, models.PostEstadoRevisada, time.Now().UTC()); err != nil {
-				return nil, err
+				return err
 			}
+			estadoNuevo = models.PostEstadoRevisada
 		}
 	case accionDescartar:
-		if err := registrarRevision(ctx, tutorID, postulacionID, accion, payload.Comentario); err != nil {
-			return nil, err
+		if err := registrarRevision(ctx, tutorID, postulacionID, accion, comentario); err != nil {
+			return err
 		}
 		if _, err = rootservices.DescartarPostulacion(postulacionID); err != nil {
-			return nil, err
+			return err
 		}
+		estadoNuevo = models.PostEstadoDescartada
 	case accionSeleccionar:
-		if err := registrarRevision(ctx, tutorID, postulacionID, accion, payload.Comentario); err != nil {
-			return nil, err
+		if err := registrarRevision(ctx, tutorID, postulacionID, accion, comentario); err != nil {
+			return err
 		}
 		if _, err = rootservices.SeleccionarPostulacion(postulacionID); err != nil {
-			return nil, err
+			return err
 		}
+		estadoNuevo = models.PostEstadoSeleccionada
 	case accionPreseleccionar:
-		if err := registrarRevision(ctx, tutorID, postulacionID, accion, payload.Comentario); err != nil {
-			return nil, err
+		if err := registrarRevision(ctx, tutorID, postulacionID, accion, comentario); err != nil {
+			return err
 		}
 		code := obtenerPreselectCodigo()
 		if err = crud.UpdatePostulacionEstado(ctx, postulacionID, code, time.Now().UTC()); err != nil {
-			return nil, err
+			return err
 		}
+		estadoNuevo = code
 	default:
 	}
-
-	updated, err := crud.GetPostulacionByID(ctx, postulacionID)
-	if err != nil {
-		return nil, helpers.AsAppError(err, "error consultando postulación actualizada")
-	}
-	if updated == nil {
-		return nil, helpers.NewAppError(http.StatusNotFound, "postulación no encontrada", nil)
-	}
-
-	response := map[string]interface{}{
-		"id":                updated.Id,
-		"estudiante_id":     updated.EstudianteId,
-		"oferta_id":         updated.OfertaId,
-		"estado":            strings.TrimSpace(updated.EstadoPostulacion),
-		"fecha_postulacion": strings.TrimSpace(updated.FechaPostulacion),
-		"accion":            accion,
-	}
-	code := strings.ToUpper(strings.TrimSpace(updated.EstadoPostulacion))
-	response["estado_det"] = map[string]string{
-		"code":   code,
-		"nombre": resolveEstadoNombre(code),
+	if estadoNuevo != "" {
+		publicarPostulacionActualizada(int64(tutorID), postulacion, estadoNuevo)
 	}
+	return nil
+}
 
-	return response, nil
+// publicarPostulacionActualizada informa al bus el nuevo estado de la postulación.
+func publicarPostulacionActualizada(tutorID int64, postulacion *models.Postulacion, estado string) {
+	PublicarEvento(Evento{
+		Tipo:          EventoPostulacionActualizada,
+		EstudianteID:  postulacion.EstudianteId,
+		TutorID:       tutorID,
+		OfertaID:      postulacion.OfertaId,
+		PostulacionID: postulacion.Id,
+		Estado:        estado,
+	})
 }
 
 func estadoFinal(estado string) bool {
@@ -279,6 +342,7 @@ func MarcarPostulacionVista(ctx context.Context, tutorID int, postulacionID int6
 		if err := crud.UpdatePostulacionEstado(ctx, postulacionID, "PSRV_CTR", time.Now().UTC()); err != nil {
 			return nil, helpers.AsAppError(err, "error actualizando estado de postulación")
 		}
+		publicarPostulacionActualizada(int64(tutorID), postulacion, "PSRV_CTR")
 		if actualizado, err := crud.GetPostulacionByID(ctx, postulacionID); err == nil && actualizado != nil {
 			postulacion = actualizado
 		}
@@ -362,6 +426,7 @@ func AceptarSeleccion(ctx context.Context, estudianteID int, postulacionID int64
 		return helpers.NewAppError(http.StatusInternalServerError, "no fue posible aceptar la selección", err)
 	}
 	_ = crud.AddPostulacionRevision(ctx, int64(post.Id), 0, "ACEPTAR_SELECCION", "Aceptada por el estudiante", now)
+	publicarPostulacionActualizada(0, post, models.PostEstadoAceptada)
 
 	// 3) Rechazar por elección las otras SELECCIONADAS del mismo estudiante
 	others, err := crud.ListPostulaciones(ctx, map[string]string{
@@ -378,6 +443,7 @@ func AceptarSeleccion(ctx context.Context, estudianteID int, postulacionID int64
 				strings.EqualFold(strings.TrimSpace(p.EstadoPostulacion), models.PostEstadoSeleccionada) {
 				_ = crud.UpdatePostulacionEstado(ctx, int64(p.Id), "RECHAZADA_POR_ELECCION", now)
 				_ = crud.AddPostulacionRevision(ctx, int64(p.Id), 0, "RECHAZAR_POR_ELECCION", "Rechazada por elección de otra oferta", now)
+				publicarPostulacionActualizada(0, &p, "RECHAZADA_POR_ELECCION")
 			}
 		}
 	}
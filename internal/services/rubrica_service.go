@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	"github.com/udistrital/pasantia_mid/internal/clients"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+	"github.com/udistrital/pasantia_mid/models"
+	rootservices "github.com/udistrital/pasantia_mid/services"
+)
+
+const (
+	ofertaRubricaResource = "oferta_rubrica"
+
+	// accionEvaluar registra la calificación de una postulación en postulacion_revision.
+	accionEvaluar = "EVALUAR"
+
+	defaultEscalaMax    = 5
+	maxCriteriosRubrica = 20
+)
+
+// GetRubricaOferta retorna la rúbrica definida para la oferta.
+// Solo el tutor dueño de la oferta o coordinación, dentro de su asignación, pueden consultarla.
+func GetRubricaOferta(ctx context.Context, tutorID, ofertaID int, coordinacion *internaldto.AsignacionCoordinacion) (map[string]interface{}, error) {
+	if _, err := autorizarOfertaEvaluacion(tutorID, ofertaID, coordinacion); err != nil {
+		return nil, err
+	}
+	rubrica, _, err := findRubricaOferta(ofertaID)
+	if err != nil {
+		return nil, err
+	}
+	return mapRubrica(ofertaID, rubrica), nil
+}
+
+// GuardarRubricaOferta crea o reemplaza la rúbrica de criterios ponderados de la oferta.
+func GuardarRubricaOferta(ctx context.Context, tutorID, ofertaID int, payload internaldto.RubricaUpsert) (map[string]interface{}, error) {
+	if _, err := autorizarOfertaEvaluacion(tutorID, ofertaID, nil); err != nil {
+		return nil, err
+	}
+
+	criterios, err := normalizeCriterios(payload.Criterios)
+	if err != nil {
+		return nil, err
+	}
+
+	_, recordID, err := findRubricaOferta(ofertaID)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(criterios)
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error serializando rúbrica")
+	}
+
+	cfg := rootservices.GetConfig()
+	body := map[string]interface{}{
+		"OfertaPasantiaId":  ofertaID,
+		"Criterios":         string(encoded),
+		"FechaModificacion": nowISO(),
+	}
+
+	method := "POST"
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, ofertaRubricaResource)
+	if recordID > 0 {
+		method = "PUT"
+		endpoint = rootservices.BuildURL(cfg.CastorCRUDBaseURL, ofertaRubricaResource, strconv.Itoa(recordID))
+	} else {
+		body["FechaCreacion"] = nowISO()
+	}
+
+	var saved map[string]interface{}
+	if err := helpers.DoJSON(method, endpoint, body, &saved, cfg.RequestTimeout); err != nil {
+		return nil, helpers.AsAppError(err, "error guardando rúbrica")
+	}
+	return mapRubrica(ofertaID, criterios), nil
+}
+
+// EvaluarPostulacion califica la postulación contra la rúbrica de la oferta y guarda el
+// resultado en el historial de revisiones.
+func EvaluarPostulacion(ctx context.Context, tutorID int, postulacionID int64, payload internaldto.EvaluacionPostulacionReq) (map[string]interface{}, error) {
+	post, rol, err := autorizarPostulacion(ctx, postulacionID, tutorID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if rol != rolTutor {
+		return nil, helpers.NewAppError(http.StatusForbidden, "no autorizado para evaluar esta postulación", nil)
+	}
+
+	criterios, _, err := findRubricaOferta(int(post.OfertaId))
+	if err != nil {
+		return nil, err
+	}
+	if len(criterios) == 0 {
+		return nil, helpers.NewAppError(http.StatusConflict, "la oferta no tiene rúbrica definida", nil)
+	}
+
+	evaluacion, err := calcularEvaluacion(criterios, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(evaluacion)
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error serializando evaluación")
+	}
+
+	now := time.Now().UTC()
+	if err := clients.CastorCRUD().AddPostulacionRevision(ctx, postulacionID, tutorID, accionEvaluar, string(encoded), now); err != nil {
+		return nil, helpers.AsAppError(err, "error registrando evaluación")
+	}
+
+	evaluacion.PostulacionID = postulacionID
+	evaluacion.TutorID = tutorID
+	evaluacion.Fecha = now.Format(time.RFC3339)
+	return map[string]interface{}{
+		"postulacion_id": postulacionID,
+		"oferta_id":      post.OfertaId,
+		"evaluacion":     evaluacion,
+	}, nil
+}
+
+// GetEvaluacionPostulacion retorna la última evaluación y el histórico de calificaciones.
+func GetEvaluacionPostulacion(ctx context.Context, tutorID int, postulacionID int64, coordinacion *internaldto.AsignacionCoordinacion) (map[string]interface{}, error) {
+	post, err := clients.CastorCRUD().GetPostulacionByID(ctx, postulacionID)
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error consultando postulación")
+	}
+	if post == nil || post.Id == 0 {
+		return nil, helpers.NewAppError(http.StatusNotFound, "postulación no encontrada", nil)
+	}
+	if _, err := autorizarOfertaEvaluacion(tutorID, int(post.OfertaId), coordinacion); err != nil {
+		return nil, err
+	}
+
+	revisiones, err := clients.CastorCRUD().ListPostulacionRevisiones(ctx, postulacionID)
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error consultando evaluaciones")
+	}
+
+	historico := make([]internaldto.EvaluacionPostulacion, 0)
+	for _, rev := range revisiones {
+		if rev.Accion != accionEvaluar {
+			continue
+		}
+		ev, ok := parseEvaluacion(rev.Comentario)
+		if !ok {
+			continue
+		}
+		ev.PostulacionID = postulacionID
+		ev.TutorID = rev.TutorId
+		if !rev.Fecha.IsZero() {
+			ev.Fecha = rev.Fecha.Format(time.RFC3339)
+		}
+		historico = append(historico, ev)
+	}
+	sort.SliceStable(historico, func(i, j int) bool {
+		return historico[i].Fecha > historico[j].Fecha
+	})
+
+	out := map[string]interface{}{
+		"postulacion_id": postulacionID,
+		"oferta_id":      post.OfertaId,
+		"historico":      historico,
+	}
+	if len(historico) > 0 {
+		out["evaluacion"] = historico[0]
+		out["puntaje"] = historico[0].Puntaje
+	}
+	return out, nil
+}
+
+// traerPuntajes retorna el puntaje ponderado más reciente de cada postulación evaluada por el tutor.
+func traerPuntajes(tutorID int, postulacionIDs []int64) map[int64]float64 {
+	result := make(map[int64]float64, len(postulacionIDs))
+	if len(postulacionIDs) == 0 {
+		return result
+	}
+	idSet := make(map[int64]struct{}, len(postulacionIDs))
+	for _, id := range postulacionIDs {
+		idSet[id] = struct{}{}
+	}
+
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, postulacionRevisionResource)
+	values := url.Values{}
+	values.Set("limit", "0")
+	values.Set("query", fmt.Sprintf("TutorId:%d,Accion:%s", tutorID, accionEvaluar))
+
+	var revisiones []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &revisiones, cfg.RequestTimeout); err != nil {
+		return result
+	}
+
+	latest := make(map[int64]time.Time, len(postulacionIDs))
+	for _, rev := range revisiones {
+		if !strings.EqualFold(normalizeToString(rev["Accion"]), accionEvaluar) {
+			continue
+		}
+		id, ok := normalizeToInt64(rev["PostulacionId"])
+		if !ok {
+			continue
+		}
+		if _, exists := idSet[id]; !exists {
+			continue
+		}
+		ev, ok := parseEvaluacion(normalizeToString(rev["Comentario"]))
+		if !ok {
+			continue
+		}
+		fecha := parseTime(normalizeToString(rev["Fecha"]))
+		if prev, seen := latest[id]; seen && fecha.Before(prev) {
+			continue
+		}
+		latest[id] = fecha
+		result[id] = ev.Puntaje
+	}
+	return result
+}
+
+// autorizarOfertaEvaluacion verifica que la oferta exista y que el tutor sea su dueño. Con
+// coordinacion (nil para tutores) exige en cambio que todos los proyectos curriculares de la oferta
+// estén dentro de la asignación del usuario. Retorna la oferta para que el llamador no la consulte
+// de nuevo.
+func autorizarOfertaEvaluacion(tutorID, ofertaID int, coordinacion *internaldto.AsignacionCoordinacion) (*models.Oferta, error) {
+	if ofertaID <= 0 {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "oferta_id inválido", nil)
+	}
+	coordinador := coordinacion != nil
+	if !coordinador && tutorID <= 0 {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "tutor_id requerido", nil)
+	}
+	oferta, err := rootservices.GetOferta(int64(ofertaID))
+	if err != nil {
+		return nil, helpers.AsAppError(err, "error consultando oferta")
+	}
+	if oferta == nil {
+		return nil, helpers.NewAppError(http.StatusNotFound, "oferta no encontrada", nil)
+	}
+	if !coordinador && int(oferta.TutorExternoId) != tutorID {
+		return nil, helpers.NewAppError(http.StatusForbidden, "no autorizado para gestionar esta oferta", nil)
+	}
+	if coordinador {
+		if err := autorizarOfertaCoordinacion(ofertaID, *coordinacion); err != nil {
+			return nil, err
+		}
+	}
+	return oferta, nil
+}
+
+// autorizarOfertaCoordinacion responde 403 si algún proyecto curricular de la oferta queda fuera de
+// la asignación del usuario; una oferta sin proyectos curriculares tampoco es visible.
+func autorizarOfertaCoordinacion(ofertaID int, asignacion internaldto.AsignacionCoordinacion) error {
+	permitidos, err := pcsAsignados(nil, asignacion.Facultades, asignacion.Proyectos)
+	if err != nil {
+		return err
+	}
+	pcIDs, err := getPCIDsByOferta(ofertaID)
+	if err != nil {
+		return helpers.NewAppError(http.StatusBadGateway, "error consultando carreras de la oferta", err)
+	}
+	if len(pcIDs) == 0 {
+		return helpers.NewAppError(http.StatusForbidden, "la oferta no está dentro de la asignación del usuario", nil)
+	}
+	for _, pc := range pcIDs {
+		if _, ok := permitidos[pc]; !ok {
+			return helpers.NewAppError(http.StatusForbidden, "la oferta no está dentro de la asignación del usuario", nil)
+		}
+	}
+	return nil
+}
+
+func findRubricaOferta(ofertaID int) ([]internaldto.RubricaCriterio, int, error) {
+	cfg := rootservices.GetConfig()
+	endpoint := rootservices.BuildURL(cfg.CastorCRUDBaseURL, ofertaRubricaResource)
+	values := url.Values{}
+	values.Set("limit", "1")
+	values.Set("query", fmt.Sprintf("OfertaPasantiaId:%d", ofertaID))
+
+	var records []map[string]interface{}
+	if err := helpers.DoJSON("GET", endpoint+"?"+values.Encode(), nil, &records, cfg.RequestTimeout); err != nil {
+		if helpers.IsHTTPError(err, http.StatusNotFound) {
+			return nil, 0, nil
+		}
+		return nil, 0, helpers.AsAppError(err, "error consultando rúbrica")
+	}
+	if len(records) == 0 || len(records[0]) == 0 {
+		return nil, 0, nil
+	}
+
+	recordID, _ := normalizeToInt(records[0]["Id"])
+	var criterios []internaldto.RubricaCriterio
+	switch raw := records[0]["Criterios"].(type) {
+	case string:
+		if strings.TrimSpace(raw) != "" {
+			if err := json.Unmarshal([]byte(raw), &criterios); err != nil {
+				return nil, recordID, helpers.AsAppError(err, "rúbrica almacenada inválida")
+			}
+		}
+	case []interface{}:
+		b, _ := json.Marshal(raw)
+		_ = json.Unmarshal(b, &criterios)
+	}
+	return criterios, recordID, nil
+}
+
+func normalizeCriterios(in []internaldto.RubricaCriterio) ([]internaldto.RubricaCriterio, error) {
+	if len(in) == 0 {
+		return nil, helpers.NewAppError(http.StatusBadRequest, "criterios requeridos", nil)
+	}
+	if len(in) > maxCriteriosRubrica {
+		return nil, helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("máximo %d criterios", maxCriteriosRubrica), nil)
+	}
+
+	seen := make(map[string]struct{}, len(in))
+	out := make([]internaldto.RubricaCriterio, 0, len(in))
+	for i, c := range in {
+		c.Nombre = strings.TrimSpace(c.Nombre)
+		c.Descripcion = strings.TrimSpace(c.Descripcion)
+		if c.Nombre == "" {
+			return nil, helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("criterio %d sin nombre", i+1), nil)
+		}
+		c.Codigo = strings.ToUpper(strings.TrimSpace(c.Codigo))
+		if c.Codigo == "" {
+			c.Codigo = fmt.Sprintf("C%d", i+1)
+		}
+		if _, dup := seen[c.Codigo]; dup {
+			return nil, helpers.NewAppError(http.StatusBadRequest, "código de criterio duplicado: "+c.Codigo, nil)
+		}
+		seen[c.Codigo] = struct{}{}
+		if c.Peso <= 0 {
+			return nil, helpers.NewAppError(http.StatusBadRequest, "el peso de "+c.Nombre+" debe ser mayor a 0", nil)
+		}
+		if c.EscalaMax <= 0 {
+			c.EscalaMax = defaultEscalaMax
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func calcularEvaluacion(criterios []internaldto.RubricaCriterio, payload internaldto.EvaluacionPostulacionReq) (internaldto.EvaluacionPostulacion, error) {
+	valores := make(map[string]float64, len(payload.Puntajes))
+	for _, p := range payload.Puntajes {
+		valores[strings.ToUpper(strings.TrimSpace(p.Criterio))] = p.Valor
+	}
+
+	var sumaPesos, sumaPonderada float64
+	detalle := make([]internaldto.PuntajeCriterio, 0, len(criterios))
+	for _, c := range criterios {
+		valor, ok := valores[c.Codigo]
+		if !ok {
+			return internaldto.EvaluacionPostulacion{}, helpers.NewAppError(http.StatusBadRequest, "falta puntaje para el criterio "+c.Codigo, nil)
+		}
+		if valor < 0 || valor > float64(c.EscalaMax) {
+			return internaldto.EvaluacionPostulacion{}, helpers.NewAppError(http.StatusBadRequest,
+				fmt.Sprintf("puntaje de %s fuera de rango (0-%d)", c.Codigo, c.EscalaMax), nil)
+		}
+		sumaPesos += c.Peso
+		sumaPonderada += c.Peso * (valor / float64(c.EscalaMax))
+		detalle = append(detalle, internaldto.PuntajeCriterio{Criterio: c.Codigo, Valor: valor})
+	}
+	if len(valores) > len(criterios) {
+		return internaldto.EvaluacionPostulacion{}, helpers.NewAppError(http.StatusBadRequest, "hay puntajes para criterios no definidos en la rúbrica", nil)
+	}
+
+	puntaje := 0.0
+	if sumaPesos > 0 {
+		puntaje = math.Round(sumaPonderada/sumaPesos*10000) / 100
+	}
+	ev := internaldto.EvaluacionPostulacion{
+		Puntaje:  puntaje,
+		Puntajes: detalle,
+	}
+	if payload.Comentario != nil {
+		ev.Comentario = strings.TrimSpace(*payload.Comentario)
+	}
+	return ev, nil
+}
+
+func parseEvaluacion(raw string) (internaldto.EvaluacionPostulacion, bool) {
+	var ev internaldto.EvaluacionPostulacion
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" || !strings.HasPrefix(trimmed, "{") {
+		return ev, false
+	}
+	if err := json.Unmarshal([]byte(trimmed), &ev); err != nil {
+		return ev, false
+	}
+	return ev, true
+}
+
+func mapRubrica(ofertaID int, criterios []internaldto.RubricaCriterio) map[string]interface{} {
+	if criterios == nil {
+		criterios = []internaldto.RubricaCriterio{}
+	}
+	var total float64
+	for _, c := range criterios {
+		total += c.Peso
+	}
+	return map[string]interface{}{
+		"oferta_id":   ofertaID,
+		"criterios":   criterios,
+		"peso_total":  total,
+		"configurada": len(criterios) > 0,
+	}
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	beegocontext "github.com/beego/beego/v2/server/web/context"
+
+	"github.com/udistrital/pasantia_mid/helpers"
+	"github.com/udistrital/pasantia_mid/internal/clients"
+	internaldto "github.com/udistrital/pasantia_mid/internal/dto"
+)
+
+const (
+	// GranularidadDia y GranularidadSemana son los periodos admitidos en la serie de visitas.
+	GranularidadDia    = "dia"
+	GranularidadSemana = "semana"
+
+	visitasRangoDefectoDias = 30
+	visitasRangoMaximoDias  = 366
+)
+
+// ventanaDeduplicacionVisitas es el tiempo en el que las visitas repetidas de un tutor al mismo perfil
+// cuentan como una sola; 0 desactiva la deduplicación.
+func ventanaDeduplicacionVisitas() time.Duration {
+	minutos := configEntero("PERFIL_VISITA_VENTANA_MIN", "perfil_visita_ventana_min", 30)
+	if minutos < 0 {
+		minutos = 0
+	}
+	return time.Duration(minutos) * time.Minute
+}
+
+// deduplicarVisitas conserva la primera visita de cada tutor y descarta las que llegan dentro de la
+// ventana desde la última visita contada. El resultado queda ordenado por fecha ascendente.
+func deduplicarVisitas(list []clients.PerfilVisita, ventana time.Duration) []clients.PerfilVisita {
+	ordenadas := make([]clients.PerfilVisita, 0, len(list))
+	for _, v := range list {
+		if v.TutorId > 0 && !v.FechaVisita.IsZero() {
+			ordenadas = append(ordenadas, v)
+		}
+	}
+	sort.SliceStable(ordenadas, func(i, j int) bool {
+		return ordenadas[i].FechaVisita.Before(ordenadas[j].FechaVisita)
+	})
+	if ventana <= 0 {
+		return ordenadas
+	}
+
+	ultima := map[int]time.Time{}
+	result := make([]clients.PerfilVisita, 0, len(ordenadas))
+	for _, v := range ordenadas {
+		if prev, ok := ultima[v.TutorId]; ok && v.FechaVisita.Sub(prev) < ventana {
+			continue
+		}
+		ultima[v.TutorId] = v.FechaVisita
+		result = append(result, v)
+	}
+	return result
+}
+
+// visitaReciente indica si una visita del tutor ahora quedaría descartada por deduplicarVisitas:
+// aplica la misma regla que la analítica, anclada en la última visita contada del tutor. Si no se
+// pueden consultar las visitas se registra de todos modos.
+func visitaReciente(ctx context.Context, tutorID, perfilID int) bool {
+	ventana := ventanaDeduplicacionVisitas()
+	if ventana <= 0 {
+		return false
+	}
+	list, err := clients.CastorCRUD().ListPerfilVisitas(ctx, perfilID)
+	if err != nil {
+		return false
+	}
+	return dentroDeVentana(list, tutorID, time.Now(), ventana)
+}
+
+// dentroDeVentana indica si una visita del tutor en la fecha dada cae dentro de la ventana de su
+// última visita contada según deduplicarVisitas.
+func dentroDeVentana(list []clients.PerfilVisita, tutorID int, fecha time.Time, ventana time.Duration) bool {
+	delTutor := make([]clients.PerfilVisita, 0)
+	for _, v := range list {
+		if v.TutorId == tutorID {
+			delTutor = append(delTutor, v)
+		}
+	}
+	contadas := deduplicarVisitas(delTutor, ventana)
+	if len(contadas) == 0 {
+		return false
+	}
+	return fecha.Sub(contadas[len(contadas)-1].FechaVisita) < ventana
+}
+
+// AnaliticaVisitasPerfil resume las visitas al perfil del estudiante entre desde y hasta (YYYY-MM-DD,
+// ambos inclusive): serie por día o semana, desglose por empresa y conversión a invitaciones.
+// Sin fechas toma los últimos 30 días.
+func AnaliticaVisitasPerfil(ctx *beegocontext.Context, estudianteID int, desdeRaw, hastaRaw, granularidad string) (internaldto.VisitasPerfilAnalitica, error) {
+	desde, hasta, err := parseRangoVisitas(desdeRaw, hastaRaw)
+	if err != nil {
+		return internaldto.VisitasPerfilAnalitica{}, err
+	}
+	granularidad = strings.ToLower(strings.TrimSpace(granularidad))
+	if granularidad == "" {
+		granularidad = GranularidadDia
+	}
+	if granularidad != GranularidadDia && granularidad != GranularidadSemana {
+		return internaldto.VisitasPerfilAnalitica{}, helpers.NewAppError(http.StatusBadRequest, "granularidad inválida (dia|semana)", nil)
+	}
+
+	stdCtx := requestContext(ctx)
+	crud := clients.CastorCRUD()
+
+	perfil, err := crud.GetPerfilByTerceroID(stdCtx, estudianteID)
+	if err != nil {
+		return internaldto.VisitasPerfilAnalitica{}, helpers.AsAppError(err, "error consultando perfil")
+	}
+	if perfil == nil {
+		return internaldto.VisitasPerfilAnalitica{}, helpers.NewAppError(http.StatusNotFound, "perfil no encontrado", nil)
+	}
+
+	list, err := crud.ListPerfilVisitas(stdCtx, perfil.Id)
+	if err != nil {
+		return internaldto.VisitasPerfilAnalitica{}, helpers.AsAppError(err, "error consultando visitas")
+	}
+
+	ventana := ventanaDeduplicacionVisitas()
+	fin := hasta.AddDate(0, 0, 1)
+	visitas := make([]clients.PerfilVisita, 0)
+	for _, v := range deduplicarVisitas(list, ventana) {
+		if !v.FechaVisita.Before(desde) && v.FechaVisita.Before(fin) {
+			visitas = append(visitas, v)
+		}
+	}
+
+	result := internaldto.VisitasPerfilAnalitica{
+		Desde:        desde.Format("2006-01-02"),
+		Hasta:        hasta.Format("2006-01-02"),
+		Granularidad: granularidad,
+		VentanaMin:   int(ventana / time.Minute),
+		Total:        len(visitas),
+		Serie:        serieVisitas(visitas, desde, hasta, granularidad),
+	}
+
+	// Agregado por tutor; primera visita del rango para medir la conversión
+	porTutor := map[int]*internaldto.VisitaTutorResumen{}
+	primera := map[int]time.Time{}
+	ultima := map[int]time.Time{}
+	for _, v := range visitas {
+		item := porTutor[v.TutorId]
+		if item == nil {
+			item = &internaldto.VisitaTutorResumen{TutorID: v.TutorId}
+			porTutor[v.TutorId] = item
+			primera[v.TutorId] = v.FechaVisita
+		}
+		item.Total++
+		ultima[v.TutorId] = v.FechaVisita
+	}
+
+	invitaciones, _, err := crud.ListInvitaciones(stdCtx, map[string]string{
+		"PerfilEstudianteId": fmt.Sprint(perfil.Id),
+	}, 0, 0)
+	if err != nil && !helpers.IsHTTPError(err, http.StatusNotFound) {
+		return internaldto.VisitasPerfilAnalitica{}, helpers.AsAppError(err, "error consultando invitaciones")
+	}
+	conversion := internaldto.VisitasConversion{TutoresVisitantes: len(porTutor)}
+	for _, inv := range invitaciones {
+		tutorID, _ := normalizeToInt(inv["TutorId"])
+		item := porTutor[tutorID]
+		if item == nil {
+			continue
+		}
+		if fecha := parseTime(normalizeToString(inv["FechaCreacion"])); !fecha.IsZero() && fecha.Before(primera[tutorID]) {
+			continue
+		}
+		conversion.Invitaciones++
+		if !item.Invito {
+			item.Invito = true
+			conversion.TutoresInvitaron++
+		}
+	}
+	if conversion.TutoresVisitantes > 0 {
+		tasa := float64(conversion.TutoresInvitaron) * 100 / float64(conversion.TutoresVisitantes)
+		conversion.Tasa = math.Round(tasa*10) / 10
+	}
+	result.Conversion = conversion
+
+	// Tutor (tercero), empresa activa del tutor y nombre de la empresa en paralelo
+	tutorIDs := make([]int, 0, len(porTutor))
+	for tutorID := range porTutor {
+		tutorIDs = append(tutorIDs, tutorID)
+	}
+	tutores := cargarEnParalelo(stdCtx, tutorIDs, getTerceroByID)
+	empresaPorTutor := cargarEnParalelo(stdCtx, tutorIDs, getEmpresaIDActivaByTutor)
+	empresaIDs := make([]int, 0, len(empresaPorTutor))
+	for _, eid := range empresaPorTutor {
+		if eid > 0 {
+			empresaIDs = append(empresaIDs, eid)
+		}
+	}
+	terceroEmpresas := cargarEnParalelo(stdCtx, empresaIDs, getTerceroByID)
+
+	result.Items = make([]internaldto.VisitaTutorResumen, 0, len(porTutor))
+	empresas := map[int]*internaldto.VisitasEmpresa{}
+	for tutorID, item := range porTutor {
+		item.UltimaVisita = ultima[tutorID].Format(time.RFC3339)
+		item.Nombre = nombreTerceroVisitas(tutores[tutorID])
+		if empresaID := empresaPorTutor[tutorID]; empresaID > 0 {
+			item.EmpresaID = empresaID
+			item.Empresa = nombreTerceroVisitas(terceroEmpresas[empresaID])
+			if item.Empresa == "" {
+				item.Empresa = fmt.Sprintf("Empresa #%d", empresaID)
+			}
+		}
+
+		empresa := empresas[item.EmpresaID]
+		if empresa == nil {
+			empresa = &internaldto.VisitasEmpresa{EmpresaID: item.EmpresaID, Empresa: item.Empresa}
+			if item.EmpresaID == 0 {
+				empresa.Empresa = "Sin empresa"
+			}
+			empresas[item.EmpresaID] = empresa
+		}
+		empresa.Visitas += item.Total
+		empresa.Tutores++
+		result.Items = append(result.Items, *item)
+	}
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		return ultima[result.Items[i].TutorID].After(ultima[result.Items[j].TutorID])
+	})
+
+	result.PorEmpresa = make([]internaldto.VisitasEmpresa, 0, len(empresas))
+	for _, e := range empresas {
+		result.PorEmpresa = append(result.PorEmpresa, *e)
+	}
+	sort.SliceStable(result.PorEmpresa, func(i, j int) bool {
+		if result.PorEmpresa[i].Visitas != result.PorEmpresa[j].Visitas {
+			return result.PorEmpresa[i].Visitas > result.PorEmpresa[j].Visitas
+		}
+		return result.PorEmpresa[i].EmpresaID < result.PorEmpresa[j].EmpresaID
+	})
+
+	return result, nil
+}
+
+func parseRangoVisitas(desdeRaw, hastaRaw string) (time.Time, time.Time, error) {
+	hoy := time.Now()
+	hasta := time.Date(hoy.Year(), hoy.Month(), hoy.Day(), 0, 0, 0, 0, time.Local)
+	if v := strings.TrimSpace(hastaRaw); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, helpers.NewAppError(http.StatusBadRequest, "hasta inválido (YYYY-MM-DD)", nil)
+		}
+		hasta = t
+	}
+	desde := hasta.AddDate(0, 0, -(visitasRangoDefectoDias - 1))
+	if v := strings.TrimSpace(desdeRaw); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, helpers.NewAppError(http.StatusBadRequest, "desde inválido (YYYY-MM-DD)", nil)
+		}
+		desde = t
+	}
+	if desde.After(hasta) {
+		return time.Time{}, time.Time{}, helpers.NewAppError(http.StatusBadRequest, "desde no puede ser posterior a hasta", nil)
+	}
+	if hasta.Sub(desde) > time.Duration(visitasRangoMaximoDias)*24*time.Hour {
+		return time.Time{}, time.Time{}, helpers.NewAppError(http.StatusBadRequest, fmt.Sprintf("el rango no puede superar %d días", visitasRangoMaximoDias), nil)
+	}
+	return desde, hasta, nil
+}
+
+// serieVisitas arma un punto por periodo del rango, incluidos los periodos sin visitas.
+func serieVisitas(visitas []clients.PerfilVisita, desde, hasta time.Time, granularidad string) []internaldto.VisitasSeriePunto {
+	inicioPeriodo := func(t time.Time) time.Time {
+		t = t.In(time.Local)
+		dia := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		if granularidad == GranularidadSemana {
+			// semanas de lunes a domingo
+			offset := (int(dia.Weekday()) + 6) % 7
+			dia = dia.AddDate(0, 0, -offset)
+		}
+		return dia
+	}
+	paso := 1
+	if granularidad == GranularidadSemana {
+		paso = 7
+	}
+
+	indice := map[string]int{}
+	serie := make([]internaldto.VisitasSeriePunto, 0)
+	for p := inicioPeriodo(desde); !p.After(hasta); p = p.AddDate(0, 0, paso) {
+		clave := p.Format("2006-01-02")
+		indice[clave] = len(serie)
+		serie = append(serie, internaldto.VisitasSeriePunto{Periodo: clave})
+	}
+
+	tutores := map[string]map[int]struct{}{}
+	for _, v := range visitas {
+		clave := inicioPeriodo(v.FechaVisita).Format("2006-01-02")
+		i, ok := indice[clave]
+		if !ok {
+			continue
+		}
+		serie[i].Visitas++
+		if tutores[clave] == nil {
+			tutores[clave] = map[int]struct{}{}
+		}
+		tutores[clave][v.TutorId] = struct{}{}
+	}
+	for clave, set := range tutores {
+		serie[indice[clave]].Tutores = len(set)
+	}
+	return serie
+}
+
+func nombreTerceroVisitas(data map[string]interface{}) string {
+	if data == nil {
+		return ""
+	}
+	nombre := strings.TrimSpace(normalizeToString(data["NombreCompleto"]))
+	if nombre == "" {
+		nombre = strings.TrimSpace(normalizeToString(data["nombre_completo"]))
+	}
+	return nombre
+}
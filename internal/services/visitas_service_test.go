@@ -0,0 +1,103 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/udistrital/pasantia_mid/internal/clients"
+)
+
+func TestDeduplicarVisitas(t *testing.T) {
+	base := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	visita := func(tutorID, min int) clients.PerfilVisita {
+		return clients.PerfilVisita{TutorId: tutorID, FechaVisita: base.Add(time.Duration(min) * time.Minute)}
+	}
+
+	tests := []struct {
+		nombre  string
+		visitas []clients.PerfilVisita
+		ventana time.Duration
+		// want son los minutos de las visitas contadas, en orden.
+		want []int
+	}{
+		{nombre: "sin visitas", ventana: 30 * time.Minute, want: []int{}},
+		{
+			nombre:  "ventana cero conserva todas ordenadas",
+			visitas: []clients.PerfilVisita{visita(1, 20), visita(1, 0), visita(1, 10)},
+			want:    []int{0, 10, 20},
+		},
+		{
+			nombre:  "repetidas dentro de la ventana cuentan una vez",
+			visitas: []clients.PerfilVisita{visita(1, 0), visita(1, 10), visita(1, 29)},
+			ventana: 30 * time.Minute,
+			want:    []int{0},
+		},
+		{
+			nombre:  "la ventana se ancla en la última visita contada",
+			visitas: []clients.PerfilVisita{visita(1, 0), visita(1, 20), visita(1, 40), visita(1, 65)},
+			ventana: 30 * time.Minute,
+			want:    []int{0, 40},
+		},
+		{
+			nombre:  "justo en el límite cuenta",
+			visitas: []clients.PerfilVisita{visita(1, 0), visita(1, 30)},
+			ventana: 30 * time.Minute,
+			want:    []int{0, 30},
+		},
+		{
+			nombre:  "cada tutor tiene su propia ventana",
+			visitas: []clients.PerfilVisita{visita(2, 5), visita(1, 0), visita(1, 10), visita(2, 15)},
+			ventana: 30 * time.Minute,
+			want:    []int{0, 5},
+		},
+		{
+			nombre:  "descarta tutor cero y fechas vacías",
+			visitas: []clients.PerfilVisita{visita(0, 0), {TutorId: 1}, visita(1, 5)},
+			ventana: 30 * time.Minute,
+			want:    []int{5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			got := deduplicarVisitas(tt.visitas, tt.ventana)
+			if len(got) != len(tt.want) {
+				t.Fatalf("%d visitas contadas, se esperaban %d: %v", len(got), len(tt.want), got)
+			}
+			for i, v := range got {
+				if min := int(v.FechaVisita.Sub(base) / time.Minute); min != tt.want[i] {
+					t.Errorf("visita %d en el minuto %d, se esperaba %d", i, min, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// Registrar solo las visitas que no caen en la ventana debe dejar guardado exactamente lo que
+// deduplicarVisitas cuenta sobre todas las visitas intentadas.
+func TestDentroDeVentanaCoincideConDeduplicar(t *testing.T) {
+	base := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	ventana := 30 * time.Minute
+	intentos := []int{0, 10, 25, 35, 50, 70, 71, 120}
+
+	var todas, guardadas []clients.PerfilVisita
+	for _, min := range intentos {
+		v := clients.PerfilVisita{TutorId: 1, FechaVisita: base.Add(time.Duration(min) * time.Minute)}
+		todas = append(todas, v)
+		if !dentroDeVentana(guardadas, 1, v.FechaVisita, ventana) {
+			guardadas = append(guardadas, v)
+		}
+	}
+
+	contadas := deduplicarVisitas(todas, ventana)
+	if len(contadas) != len(guardadas) {
+		t.Fatalf("guardadas %d, contadas %d", len(guardadas), len(contadas))
+	}
+	for i := range contadas {
+		if !contadas[i].FechaVisita.Equal(guardadas[i].FechaVisita) {
+			t.Errorf("visita %d: guardada %s, contada %s", i, guardadas[i].FechaVisita, contadas[i].FechaVisita)
+		}
+	}
+	if len(deduplicarVisitas(guardadas, ventana)) != len(guardadas) {
+		t.Error("la analítica descarta visitas que el registro guardó")
+	}
+}
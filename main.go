@@ -1,6 +1,7 @@
 package main
 
 import (
+	internalservices "github.com/udistrital/pasantia_mid/internal/services"
 	_ "github.com/udistrital/pasantia_mid/routers"
 
 	beego "github.com/beego/beego/v2/server/web"
@@ -19,5 +20,16 @@ func main() {
 		beego.BConfig.WebConfig.DirectoryIndex = true
 		beego.BConfig.WebConfig.StaticDir["/swagger"] = "swagger"
 	}
+	internalservices.IniciarRecordatoriosEntrevistas()
+	internalservices.IniciarMigracionHabilidades()
+	internalservices.IniciarIndiceBusqueda()
+	internalservices.IniciarAlertasBusquedas()
+	internalservices.IniciarCacheDashboard()
+	internalservices.IniciarStreamEventos()
+	internalservices.IniciarBandejaNotificaciones()
+	internalservices.IniciarCanalNotificaciones()
+	internalservices.IniciarOutboxNotificaciones()
+	internalservices.IniciarPreferenciasNotificaciones()
+	internalservices.IniciarNotificacionesDominio()
 	beego.Run()
 }
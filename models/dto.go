@@ -78,6 +78,9 @@ type Oferta struct {
 	EmpresaId             int64     `json:"empresa_id"`
 	TutorExternoId        int64     `json:"tutor_externo_id"`
 	ProyectoCurricularIds []int64   `json:"proyecto_curricular_ids,omitempty"`
+	Modalidad             string    `json:"modalidad,omitempty"`
+	Ciudad                string    `json:"ciudad,omitempty"`
+	Habilidades           []string  `json:"habilidades,omitempty"`
 }
 
 // CreateOfertaDTO es el payload para crear una oferta desde el MID.
@@ -114,6 +117,7 @@ type Postulacion struct {
 	OfertaId          int64  `json:"oferta_id"`
 	EstadoPostulacion string `json:"estado_postulacion"`
 	FechaPostulacion  string `json:"fecha_postulacion"`
+	FechaEstado       string `json:"fecha_estado,omitempty"`
 	EnlaceDocHv       string `json:"enlace_doc_hv"`
 }
 
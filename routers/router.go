@@ -20,6 +20,10 @@ func init() {
 	beego.Router("/v1/ofertas/:id/reactivar", &internalcontrollers.OfertaController{}, "put:PutReactivar")
 	beego.Router("/v1/ofertas", &internalcontrollers.OfertaController{}, "get:GetListado")
 	beego.Router("/v1/ofertas/:id/postulaciones", &internalcontrollers.PostulacionesController{}, "get:GetByOferta")
+	beego.Router("/v1/ofertas/:id/postulaciones/acciones", &internalcontrollers.PostulacionesController{}, "post:PostAccionesLote")
+	beego.Router("/v1/ofertas/:id/postulaciones/export", &internalcontrollers.PostulacionesController{}, "get:GetExport")
+	beego.Router("/v1/ofertas/:id/rubrica", &internalcontrollers.RubricaController{}, "get:GetRubrica;put:PutRubrica")
+	beego.Router("/v1/ofertas/:id/candidatos-sugeridos", &internalcontrollers.ExplorarController{}, "get:GetCandidatosSugeridos")
 	beego.Router("/v1/ofertas/:id/postular", &internalcontrollers.PostulacionesEstudianteController{}, "post:PostPostularOferta")
 	beego.Router("/v1/ofertas/:id", &internalcontrollers.OfertaController{}, "get:GetById")
 
@@ -28,21 +32,39 @@ func init() {
 
 	beego.Router("/v1/postulaciones/:id/accion", &internalcontrollers.PostulacionesController{}, "post:PostAccion")
 	beego.Router("/v1/postulaciones/:id/visto", &internalcontrollers.PostulacionesController{}, "put:PutVisto")
+	beego.Router("/v1/postulaciones/:id/historial", &internalcontrollers.PostulacionesController{}, "get:GetHistorial")
+	beego.Router("/v1/postulaciones/:id/comentarios", &internalcontrollers.PostulacionesController{}, "get:GetComentarios;post:PostComentario")
+	beego.Router("/v1/postulaciones/:id/evaluacion", &internalcontrollers.RubricaController{}, "get:GetEvaluacion;post:PostEvaluacion")
+	beego.Router("/v1/postulaciones/:id/entrevistas", &internalcontrollers.EntrevistasController{}, "get:GetByPostulacion;post:PostProponer")
+	beego.Router("/v1/entrevistas/:id", &internalcontrollers.EntrevistasController{}, "get:GetById")
+	beego.Router("/v1/entrevistas/:id/confirmar", &internalcontrollers.EntrevistasController{}, "put:PutConfirmar")
+	beego.Router("/v1/entrevistas/:id/reprogramar", &internalcontrollers.EntrevistasController{}, "put:PutReprogramar")
+	beego.Router("/v1/entrevistas/:id/cancelar", &internalcontrollers.EntrevistasController{}, "put:PutCancelar")
+	beego.Router("/v1/entrevistas/:id/ics", &internalcontrollers.EntrevistasController{}, "get:GetICS")
 
 	beego.Router("/v1/estudiantes/perfil", &internalcontrollers.EstudiantesController{}, "get:GetMiPerfil;post:PostUpsertPerfil;put:PutActualizarPerfil")
 	beego.Router("/v1/estudiantes/perfil/visibilidad", &internalcontrollers.EstudiantesController{}, "put:PutVisibilidad")
 	beego.Router("/v1/estudiantes/perfil/cv", &internalcontrollers.EstudiantesController{}, "put:PutCV")
 	beego.Router("/v1/estudiantes/perfil/visitas", &internalcontrollers.EstudiantesController{}, "get:GetVisitas")
+	beego.Router("/v1/estudiantes/consentimiento", &internalcontrollers.EstudiantesController{}, "get:GetConsentimiento;post:PostConsentimiento")
+	beego.Router("/v1/estudiantes/consentimiento/revocar", &internalcontrollers.EstudiantesController{}, "post:PostRevocarConsentimiento")
+	beego.Router("/v1/estudiantes/consentimiento/historial", &internalcontrollers.EstudiantesController{}, "get:GetHistorialConsentimiento")
+	beego.Router("/v1/estudiantes/mis-datos/export", &internalcontrollers.DatosPersonalesController{}, "get:GetExport")
+	beego.Router("/v1/estudiantes/mis-datos/eliminacion", &internalcontrollers.DatosPersonalesController{}, "get:GetMisSolicitudes;post:PostSolicitud")
 	beego.Router("/v1/estudiantes/perfil/consulta_documento", &internalcontrollers.EstudiantesController{}, "post:PostPerfilPorDocumento")
+	beego.Router("/v1/estudiantes/ofertas/recomendadas", &internalcontrollers.EstudiantesController{}, "get:GetOfertasRecomendadas")
 	beego.Router("/v1/estudiantes/invitaciones", &internalcontrollers.InvitacionesController{}, "get:GetBandejaEstudiante")
 	beego.Router("/v1/estudiantes/postulaciones", &internalcontrollers.PostulacionesEstudianteController{}, "get:GetMisPostulaciones")
 	beego.Router("/v1/estudiantes/postulaciones/:id/aceptar-seleccion", &internalcontrollers.PostulacionesController{}, "put:PutAceptarSeleccion")
 	beego.Router("/v1/estudiantes/postulaciones/:id", &internalcontrollers.PostulacionesEstudianteController{}, "get:GetById")
 	beego.Router("/v1/estudiantes/dashboard", &internalcontrollers.DashboardController{}, "get:GetEstudiante")
 
+	beego.Router("/v1/explorar/guardados", &internalcontrollers.ExplorarController{}, "get:GetGuardados")
+	beego.Router("/v1/explorar/carpetas", &internalcontrollers.ExplorarController{}, "get:GetCarpetas;post:PostCarpeta")
+	beego.Router("/v1/explorar/carpetas/:id", &internalcontrollers.ExplorarController{}, "put:PutCarpeta;delete:DeleteCarpeta")
 	beego.Router("/v1/explorar/estudiantes", &internalcontrollers.ExplorarController{}, "get:GetCatalogo")
 	beego.Router("/v1/explorar/estudiantes/:perfil_id", &internalcontrollers.ExplorarController{}, "get:GetPerfil")
-	beego.Router("/v1/explorar/estudiantes/:perfil_id/guardar", &internalcontrollers.ExplorarController{}, "post:PostGuardar;delete:DeleteGuardar")
+	beego.Router("/v1/explorar/estudiantes/:perfil_id/guardar", &internalcontrollers.ExplorarController{}, "post:PostGuardar;put:PutGuardar;delete:DeleteGuardar")
 	beego.Router("/v1/explorar/estudiantes/:perfil_id/visita", &internalcontrollers.ExplorarController{}, "post:PostVisita")
 	beego.Router("/v1/explorar/estudiantes/:perfil_id/invitar", &internalcontrollers.InvitacionesController{}, "post:PostInvitar")
 
@@ -57,6 +79,30 @@ func init() {
 	beego.Router("/v1/catalogos/proyectos-curriculares", &internalcontrollers.CatalogosController{}, "get:GetProyectosCurriculares")
 	beego.Router("/v1/catalogos/proyectos-curriculares/:id", &internalcontrollers.CatalogosController{}, "get:GetProyectoCurricular")
 	beego.Router("/v1/catalogos/proyecto-curricular", &internalcontrollers.CatalogosController{}, "get:GetProyectoCurricularPorCodigo")
+	beego.Router("/v1/buscar/estudiantes", &internalcontrollers.BusquedaController{}, "get:GetEstudiantes")
+	beego.Router("/v1/buscar/ofertas", &internalcontrollers.BusquedaController{}, "get:GetOfertas")
+	beego.Router("/v1/busquedas-guardadas", &internalcontrollers.BusquedasGuardadasController{}, "get:GetAll;post:Post")
+	beego.Router("/v1/busquedas-guardadas/:id", &internalcontrollers.BusquedasGuardadasController{}, "get:GetOne;put:Put;delete:Delete")
+	beego.Router("/v1/busquedas-guardadas/:id/resultados", &internalcontrollers.BusquedasGuardadasController{}, "get:GetResultados")
+	beego.Router("/v1/catalogos/habilidades", &internalcontrollers.HabilidadesController{}, "get:GetAll;post:Post")
+	beego.Router("/v1/catalogos/habilidades/:id", &internalcontrollers.HabilidadesController{}, "put:Put")
+
+	beego.Router("/v1/eventos/stream", &internalcontrollers.EventosController{}, "get:GetStream")
+	beego.Router("/v1/notificaciones", &internalcontrollers.NotificacionesController{}, "get:GetAll")
+	beego.Router("/v1/notificaciones/no-leidas", &internalcontrollers.NotificacionesController{}, "get:GetNoLeidas")
+	beego.Router("/v1/notificaciones/leidas", &internalcontrollers.NotificacionesController{}, "put:PutTodasLeidas")
+	beego.Router("/v1/notificaciones/preferencias", &internalcontrollers.NotificacionesController{}, "get:GetPreferencias;put:PutPreferencias")
+	beego.Router("/v1/notificaciones/:id/leida", &internalcontrollers.NotificacionesController{}, "put:PutLeida")
+	beego.Router("/v1/admin/notificaciones/outbox", &internalcontrollers.NotificacionesController{}, "get:GetOutbox")
+	beego.Router("/v1/admin/notificaciones/outbox/:id/reintentar", &internalcontrollers.NotificacionesController{}, "post:PostReintentar")
+	beego.Router("/v1/admin/plantillas", &internalcontrollers.PlantillasController{}, "get:GetAll")
+	beego.Router("/v1/admin/plantillas/:id/preview", &internalcontrollers.PlantillasController{}, "get:GetPreview")
+
+	beego.Router("/v1/coordinacion/dashboard", &internalcontrollers.DashboardController{}, "get:GetCoordinacion")
+	beego.Router("/v1/coordinacion/solicitudes-eliminacion", &internalcontrollers.DatosPersonalesController{}, "get:GetSolicitudes")
+	beego.Router("/v1/coordinacion/solicitudes-eliminacion/:id", &internalcontrollers.DatosPersonalesController{}, "get:GetSolicitud")
+	beego.Router("/v1/coordinacion/solicitudes-eliminacion/:id/confirmar", &internalcontrollers.DatosPersonalesController{}, "put:PutConfirmar")
+	beego.Router("/v1/coordinacion/solicitudes-eliminacion/:id/rechazar", &internalcontrollers.DatosPersonalesController{}, "put:PutRechazar")
 
 	beego.Router("/v1/terceros/tutor_externo/registrar", &internalcontrollers.TercerosController{}, "post:PostRegistrarTutorExterno")
 	beego.Router("/v1/terceros/empresa/:id", &internalcontrollers.TercerosController{}, "get:GetEmpresaByID")
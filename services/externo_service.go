@@ -255,6 +255,12 @@ func ensureInfoComplementariaDato(terceroID int, codigo, valor string) error {
 	return helpers.DoJSONWithHeaders("PUT", updateEndpoint, putHeaders, body, &updated, cfg.RequestTimeout, true)
 }
 
+// CorreoTercero retorna el correo registrado en la información complementaria (CORREO) del
+// tercero; vacío si no tiene.
+func CorreoTercero(terceroID int) (string, error) {
+	return getInfoComplementariaDato(terceroID, "CORREO")
+}
+
 func getInfoComplementariaDato(terceroID int, codigo string) (string, error) {
 	infoID, err := getInfoComplementariaID(codigo)
 	if err != nil {
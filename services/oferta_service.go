@@ -142,6 +142,15 @@ func UpdateOfertaMerge(id int64, patch map[string]interface{}) (*models.Oferta,
 	if len(raw.ProyectoCurricularIds) > 0 {
 		payload["ProyectoCurricularIds"] = raw.ProyectoCurricularIds
 	}
+	if v := rawText(raw.Modalidad); v != "" {
+		payload["Modalidad"] = v
+	}
+	if v := rawText(raw.Ciudad); v != "" {
+		payload["Ciudad"] = v
+	}
+	if len(raw.Habilidades) > 0 && string(raw.Habilidades) != "null" {
+		payload["Habilidades"] = raw.Habilidades
+	}
 
 	applyPatch(payload, patch)
 
@@ -222,14 +231,17 @@ func RemoveOfertaCarrera(id int64, carreraId int64) error {
 }
 
 type castorOferta struct {
-	Id                    int64   `json:"Id"`
-	Titulo                string  `json:"Titulo"`
-	Descripcion           string  `json:"Descripcion"`
-	Estado                string  `json:"Estado"`
-	EmpresaId             int64   `json:"EmpresaId"`
-	TutorExternoId        int64   `json:"TutorExternoId"`
-	FechaPublicacion      string  `json:"FechaPublicacion"`
-	ProyectoCurricularIds []int64 `json:"ProyectoCurricularIds"`
+	Id                    int64           `json:"Id"`
+	Titulo                string          `json:"Titulo"`
+	Descripcion           string          `json:"Descripcion"`
+	Estado                string          `json:"Estado"`
+	EmpresaId             int64           `json:"EmpresaId"`
+	TutorExternoId        int64           `json:"TutorExternoId"`
+	FechaPublicacion      string          `json:"FechaPublicacion"`
+	ProyectoCurricularIds []int64         `json:"ProyectoCurricularIds"`
+	Modalidad             json.RawMessage `json:"Modalidad"`
+	Ciudad                json.RawMessage `json:"Ciudad"`
+	Habilidades           json.RawMessage `json:"Habilidades"`
 }
 
 type ofertaCrudRecord struct {
@@ -240,6 +252,9 @@ type ofertaCrudRecord struct {
 	FechaPublicacion time.Time       `json:"FechaPublicacion"`
 	EmpresaId        json.RawMessage `json:"EmpresaId"`
 	TutorExternoId   json.RawMessage `json:"TutorExternoId"`
+	Modalidad        json.RawMessage `json:"Modalidad,omitempty"`
+	Ciudad           json.RawMessage `json:"Ciudad,omitempty"`
+	Habilidades      json.RawMessage `json:"Habilidades,omitempty"`
 }
 
 var ofertaFilterMap = map[string]string{
@@ -295,7 +310,43 @@ func mapCastorOferta(raw castorOferta) models.Oferta {
 		EmpresaId:             raw.EmpresaId,
 		TutorExternoId:        raw.TutorExternoId,
 		ProyectoCurricularIds: raw.ProyectoCurricularIds,
+		Modalidad:             rawText(raw.Modalidad),
+		Ciudad:                rawText(raw.Ciudad),
+		Habilidades:           splitHabilidades(raw.Habilidades),
+	}
+}
+
+// rawText lee un campo opcional del CRUD que puede venir como texto o número.
+func rawText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return strings.TrimSpace(s)
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// splitHabilidades acepta un arreglo JSON o un texto con un arreglo JSON o una lista separada por comas.
+func splitHabilidades(raw json.RawMessage) []string {
+	var arr []string
+	if json.Unmarshal(raw, &arr) != nil {
+		trimmed := rawText(raw)
+		if trimmed == "" {
+			return nil
+		}
+		if json.Unmarshal([]byte(trimmed), &arr) != nil {
+			arr = strings.Split(trimmed, ",")
+		}
+	}
+	out := make([]string, 0, len(arr))
+	for _, h := range arr {
+		if h = strings.TrimSpace(h); h != "" {
+			out = append(out, h)
+		}
 	}
+	return out
 }
 
 func applyPatch(payload map[string]interface{}, patch map[string]interface{}) {
@@ -342,6 +393,12 @@ func applyPatch(payload map[string]interface{}, patch map[string]interface{}) {
 			}
 		case "proyectocurricularids", "proyecto_curricular_ids":
 			payload["ProyectoCurricularIds"] = value
+		case "modalidad":
+			payload["Modalidad"] = value
+		case "ciudad":
+			payload["Ciudad"] = value
+		case "habilidades":
+			payload["Habilidades"] = value
 		default:
 
 		}